@@ -11,6 +11,7 @@ import (
 	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata"
 
 	"github.com/felipedenardo/chameleon-auth-api/internal/app"
 	"github.com/felipedenardo/chameleon-auth-api/internal/config"
@@ -52,6 +53,9 @@ func main() {
 	handlers := app.NewHandlerContainer(db, cfg, redisClient)
 	r := app.SetupRouter(handlers, cfg)
 
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	handlers.StartJobs(jobsCtx, cfg)
+
 	srv := &http.Server{
 		Addr:              ":" + cfg.Port,
 		Handler:           r,
@@ -73,6 +77,7 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	log.Println("[INFO] Shutting down server...")
+	stopJobs()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -116,6 +121,24 @@ func setupPostgres(cfg *config.Config) *gorm.DB {
 	m := gormigrate.New(db, gormigrate.DefaultOptions, []*gormigrate.Migration{
 		&migration.ID011220251300DDLCreateInitialSchema,
 		&migration.ID270220261200DDLNormalizeEmailCitext,
+		&migration.ID181020261000DDLAddPasswordExpiry,
+		&migration.ID181020261010DDLAddUserProfile,
+		&migration.ID181020261020DDLCreateRBAC,
+		&migration.ID181020261030DDLCreateOrganizations,
+		&migration.ID181020261040DDLCreateInvitations,
+		&migration.ID181020261050DDLAddBulkPermissions,
+		&migration.ID181020261060DDLCreateAuditEvents,
+		&migration.ID181020261070DDLCreateLoginHistory,
+		&migration.ID181020261080DDLAddImpersonatePermission,
+		&migration.ID181020261090DDLAddAccountStatusDetails,
+		&migration.ID181020261100DDLAddAccountDeletion,
+		&migration.ID181020261110DDLAddStatusSource,
+		&migration.ID181020261120DDLAddEmailOTP,
+		&migration.ID181020261130DDLCreateIdentities,
+		&migration.ID181020261140DDLCreateSCIM,
+		&migration.ID181020261150DDLCreatePersonalAccessTokens,
+		&migration.ID181020261200DDLAddAuditPIICommitment,
+		&migration.ID181020261210DDLAddPersonalTokenVersion,
 	})
 
 	if err = m.Migrate(); err != nil {
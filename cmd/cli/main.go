@@ -0,0 +1,237 @@
+// Command chameleon-auth-cli runs administrative tasks directly against the
+// Auth API database. It reads the same environment variables as the API and
+// expects its migrations to be applied.
+//
+// Usage:
+//
+//	chameleon-auth-cli import-users -file users.csv [-org default] [-format csv|jsonl] [-dry-run] [-batch-size 500]
+//	chameleon-auth-cli export-users [-org default] [-format csv|jsonl] [-include-password-hash] [-out users.csv]
+//	chameleon-auth-cli verify-audit-log [-expect-sequence N -expect-hash HASH]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	authdomain "github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-auth-api/internal/infra/database/postgresql/repository"
+	"github.com/joho/godotenv"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, db *gorm.DB, args []string) error
+}
+
+var commands = []command{
+	{name: "import-users", usage: "importa usuários de um arquivo CSV ou JSON Lines", run: importUsers},
+	{name: "export-users", usage: "exporta os usuários de uma organização", run: exportUsers},
+	{name: "verify-audit-log", usage: "verifica a cadeia de hashes do log de auditoria", run: verifyAuditLog},
+}
+
+func main() {
+	log.SetOutput(os.Stderr)
+
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+
+	var selected *command
+	for i := range commands {
+		if commands[i].name == os.Args[1] {
+			selected = &commands[i]
+		}
+	}
+	if selected == nil {
+		printUsage()
+		os.Exit(2)
+	}
+
+	_ = godotenv.Load()
+	cfg := config.Load()
+	db := openPostgres(cfg)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := selected.run(ctx, db, os.Args[2:]); err != nil {
+		log.Fatalf("[FATAL] %s: %v", selected.name, err)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "uso: chameleon-auth-cli <comando> [opções]")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", cmd.name, cmd.usage)
+	}
+}
+
+func importUsers(ctx context.Context, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("import-users", flag.ExitOnError)
+	file := flags.String("file", "", "arquivo .csv ou .jsonl (- para stdin)")
+	org := flags.String("org", "", "slug da organização (padrão: default)")
+	format := flags.String("format", "", "csv ou jsonl (padrão: extensão do arquivo)")
+	dryRun := flags.Bool("dry-run", false, "apenas valida, sem gravar")
+	batchSize := flags.Int("batch-size", 0, "linhas por transação (máx. 1000)")
+	_ = flags.Parse(args)
+
+	if *file == "" {
+		flags.Usage()
+		return fmt.Errorf("-file is required")
+	}
+
+	ctx, err := scopeOrganization(ctx, db, *org)
+	if err != nil {
+		return err
+	}
+
+	var input io.Reader = os.Stdin
+	if *file != "-" {
+		f, err := os.Open(*file)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		input = f
+	}
+
+	bulkFormat := user.BulkFormat(*format)
+	if bulkFormat == "" {
+		bulkFormat = user.FormatCSV
+		if ext := strings.ToLower(filepath.Ext(*file)); ext == ".jsonl" || ext == ".ndjson" {
+			bulkFormat = user.FormatJSONL
+		}
+	}
+
+	service := authdomain.NewBulkService(repository.NewUserRepository(db), audit.NewAuditService(repository.NewAuditRepository(db)))
+	report, err := service.Import(ctx, input, user.ImportOptions{
+		Format:    bulkFormat,
+		DryRun:    *dryRun,
+		BatchSize: *batchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func exportUsers(ctx context.Context, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("export-users", flag.ExitOnError)
+	org := flags.String("org", "", "slug da organização (padrão: default)")
+	format := flags.String("format", string(user.FormatCSV), "csv ou jsonl")
+	includeHash := flags.Bool("include-password-hash", false, "inclui o hash de senha")
+	out := flags.String("out", "", "arquivo de saída (padrão: stdout)")
+	_ = flags.Parse(args)
+
+	ctx, err := scopeOrganization(ctx, db, *org)
+	if err != nil {
+		return err
+	}
+
+	var output io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.OpenFile(*out, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		output = f
+	}
+
+	service := authdomain.NewBulkService(repository.NewUserRepository(db), audit.NewAuditService(repository.NewAuditRepository(db)))
+	return service.Export(ctx, output, user.BulkFormat(*format), *includeHash)
+}
+
+// verifyAuditLog checks the whole chain. The last sequence and hash of a
+// previous run can be given to also detect events removed from the end.
+func verifyAuditLog(ctx context.Context, db *gorm.DB, args []string) error {
+	flags := flag.NewFlagSet("verify-audit-log", flag.ExitOnError)
+	expectSequence := flags.Int64("expect-sequence", 0, "last_sequence de uma verificação anterior")
+	expectHash := flags.String("expect-hash", "", "last_hash de uma verificação anterior")
+	_ = flags.Parse(args)
+
+	var anchor *audit.Anchor
+	if *expectSequence > 0 {
+		anchor = &audit.Anchor{Sequence: *expectSequence, Hash: *expectHash}
+	}
+
+	service := audit.NewAuditService(repository.NewAuditRepository(db))
+	report, err := service.Verify(ctx, anchor)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return err
+	}
+	if !report.Valid {
+		os.Exit(1)
+	}
+	return nil
+}
+
+func scopeOrganization(ctx context.Context, db *gorm.DB, slug string) (context.Context, error) {
+	if slug == "" {
+		return tenant.WithOrganization(ctx, tenant.DefaultOrganizationID), nil
+	}
+
+	org, err := repository.NewOrganizationRepository(db).FindBySlug(ctx, strings.ToLower(slug))
+	if err != nil {
+		return nil, fmt.Errorf("organization %q: %w", slug, err)
+	}
+	return tenant.WithOrganization(ctx, org.ID), nil
+}
+
+func openPostgres(cfg *config.Config) *gorm.DB {
+	dsn := fmt.Sprintf(
+		"host=%s user=%s password=%s dbname=%s port=%s sslmode=%s TimeZone=UTC",
+		cfg.DBHost, cfg.DBUser, cfg.DBPassword, cfg.DBName, cfg.DBPort, cfg.DBSSLMode,
+	)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Warn)})
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to connect to PostgreSQL: %v", err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to get PostgreSQL connection: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		log.Fatalf("[FATAL] Failed to ping PostgreSQL: %v", err)
+	}
+	return db
+}
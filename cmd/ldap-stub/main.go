@@ -0,0 +1,49 @@
+// Command ldap-stub is a minimal in-memory LDAP server for trying directory
+// authentication locally. It answers simple binds and searches with
+// equality, presence and substring filters; everything else is rejected.
+// Never expose it outside a development machine.
+//
+// Usage:
+//
+//	ldap-stub [-addr :3389] [-directory entries.json]
+//
+// Without -directory it serves dc=example,dc=com with the service account
+// cn=admin,dc=example,dc=com (password admin) and the users
+// alice@example.com (alice-secret, group admins) and bob@example.com
+// (bob-secret, group staff).
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net"
+	"os"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/infra/ldap/ldaptest"
+)
+
+func main() {
+	addr := flag.String("addr", ":3389", "listen address")
+	directoryFile := flag.String("directory", "", "JSON array of entries (dn, password, attributes)")
+	flag.Parse()
+
+	entries := ldaptest.DefaultEntries
+	if *directoryFile != "" {
+		content, err := os.ReadFile(*directoryFile)
+		if err != nil {
+			log.Fatalf("[FATAL] Failed to read directory: %v", err)
+		}
+		if err := json.Unmarshal(content, &entries); err != nil {
+			log.Fatalf("[FATAL] Failed to parse directory: %v", err)
+		}
+	}
+
+	listener, err := net.Listen("tcp", *addr)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to listen: %v", err)
+	}
+	log.Printf("[INFO] LDAP stub with %d entries listening on %s", len(entries), *addr)
+
+	log.Fatalf("[FATAL] LDAP stub stopped: %v", ldaptest.NewServer(entries).Serve(listener))
+}
@@ -0,0 +1,288 @@
+// Command saml-stub is a minimal SAML 2.0 identity provider for trying SAML
+// login locally. It answers every AuthnRequest for the e-mail typed in its
+// form with an assertion signed by a key generated at startup, and writes its
+// metadata to -metadata-out for SAML_PROVIDERS_FILE. Never expose it outside a
+// development machine.
+//
+// Usage:
+//
+//	saml-stub [-addr :9100] [-entity-id http://localhost:9100/metadata] [-metadata-out saml-stub-metadata.xml]
+package main
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"flag"
+	"html/template"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+const (
+	protocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	assertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+	assertionTTL       = 5 * time.Minute
+)
+
+type authnRequest struct {
+	ID                          string `xml:"ID,attr"`
+	AssertionConsumerServiceURL string `xml:"AssertionConsumerServiceURL,attr"`
+	Issuer                      string `xml:"Issuer"`
+}
+
+type provider struct {
+	entityID string
+	ssoURL   string
+	key      *rsa.PrivateKey
+	cert     []byte
+}
+
+var loginForm = template.Must(template.New("login").Parse(`<!doctype html>
+<title>SAML stub</title>
+<h1>SAML stub</h1>
+<form method="get" action="/sso">
+  <input type="hidden" name="SAMLRequest" value="{{.SAMLRequest}}">
+  <input type="hidden" name="RelayState" value="{{.RelayState}}">
+  <p><label>E-mail <input name="email" type="email" required></label></p>
+  <p><label>Nome <input name="name"></label></p>
+  <p><label>Grupos <input name="groups" placeholder="admins, dev"></label></p>
+  <button type="submit">Entrar</button>
+</form>
+`))
+
+var postForm = template.Must(template.New("post").Parse(`<!doctype html>
+<title>SAML stub</title>
+<body onload="document.forms[0].submit()">
+<form method="post" action="{{.ACS}}">
+  <input type="hidden" name="SAMLResponse" value="{{.SAMLResponse}}">
+  <input type="hidden" name="RelayState" value="{{.RelayState}}">
+  <noscript><button type="submit">Continuar</button></noscript>
+</form>
+`))
+
+func main() {
+	addr := flag.String("addr", ":9100", "listen address")
+	entityID := flag.String("entity-id", "http://localhost:9100/metadata", "entity ID of the identity provider")
+	baseURL := flag.String("url", "http://localhost:9100", "public URL of the stub")
+	metadataOut := flag.String("metadata-out", "saml-stub-metadata.xml", "file the metadata is written to")
+	flag.Parse()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to generate signing key: %v", err)
+	}
+	certTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "saml-stub"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(1, 0, 0),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, certTemplate, certTemplate, &key.PublicKey, key)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to create certificate: %v", err)
+	}
+
+	p := &provider{
+		entityID: *entityID,
+		ssoURL:   strings.TrimSuffix(*baseURL, "/") + "/sso",
+		key:      key,
+		cert:     cert,
+	}
+	if err := os.WriteFile(*metadataOut, p.metadata(), 0o644); err != nil {
+		log.Fatalf("[FATAL] Failed to write metadata: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /metadata", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/samlmetadata+xml")
+		_, _ = w.Write(p.metadata())
+	})
+	mux.HandleFunc("GET /sso", p.sso)
+
+	log.Printf("[INFO] SAML stub %s listening on %s, metadata written to %s", p.entityID, *addr, *metadataOut)
+	log.Fatal(http.ListenAndServe(*addr, mux))
+}
+
+func (p *provider) metadata() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	b.WriteString(`<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="`)
+	_ = xml.EscapeText(&b, []byte(p.entityID))
+	b.WriteString(`">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol" WantAuthnRequestsSigned="false">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+        <ds:X509Data><ds:X509Certificate>` + base64.StdEncoding.EncodeToString(p.cert) + `</ds:X509Certificate></ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:NameIDFormat>urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress</md:NameIDFormat>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="`)
+	_ = xml.EscapeText(&b, []byte(p.ssoURL))
+	b.WriteString(`"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>
+`)
+	return b.Bytes()
+}
+
+func (p *provider) sso(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	request, err := decodeRequest(query.Get("SAMLRequest"))
+	if err != nil || request.ID == "" || request.AssertionConsumerServiceURL == "" {
+		http.Error(w, "invalid SAMLRequest", http.StatusBadRequest)
+		return
+	}
+
+	email := strings.ToLower(strings.TrimSpace(query.Get("email")))
+	if email == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = loginForm.Execute(w, map[string]string{
+			"SAMLRequest": query.Get("SAMLRequest"),
+			"RelayState":  query.Get("RelayState"),
+		})
+		return
+	}
+
+	var groups []string
+	for _, group := range strings.Split(query.Get("groups"), ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+
+	response, err := p.response(request, email, strings.TrimSpace(query.Get("name")), groups)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_ = postForm.Execute(w, map[string]string{
+		"ACS":          request.AssertionConsumerServiceURL,
+		"SAMLResponse": base64.StdEncoding.EncodeToString(response),
+		"RelayState":   query.Get("RelayState"),
+	})
+}
+
+// response builds a Response whose assertion is signed. The NameID is
+// derived from the e-mail so the same user keeps its identity across
+// restarts of the stub.
+func (p *provider) response(request *authnRequest, email, name string, groups []string) ([]byte, error) {
+	now := time.Now().UTC()
+	instant := now.Format(time.RFC3339)
+	expiry := now.Add(assertionTTL).Format(time.RFC3339)
+
+	assertion := etree.NewElement("saml:Assertion")
+	assertion.CreateAttr("xmlns:saml", assertionNamespace)
+	assertion.CreateAttr("ID", newID())
+	assertion.CreateAttr("Version", "2.0")
+	assertion.CreateAttr("IssueInstant", instant)
+	assertion.CreateElement("saml:Issuer").SetText(p.entityID)
+
+	subject := assertion.CreateElement("saml:Subject")
+	nameID := subject.CreateElement("saml:NameID")
+	nameID.CreateAttr("Format", "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress")
+	nameID.SetText(email)
+	confirmation := subject.CreateElement("saml:SubjectConfirmation")
+	confirmation.CreateAttr("Method", "urn:oasis:names:tc:SAML:2.0:cm:bearer")
+	data := confirmation.CreateElement("saml:SubjectConfirmationData")
+	data.CreateAttr("InResponseTo", request.ID)
+	data.CreateAttr("NotOnOrAfter", expiry)
+	data.CreateAttr("Recipient", request.AssertionConsumerServiceURL)
+
+	conditions := assertion.CreateElement("saml:Conditions")
+	conditions.CreateAttr("NotBefore", instant)
+	conditions.CreateAttr("NotOnOrAfter", expiry)
+	conditions.CreateElement("saml:AudienceRestriction").CreateElement("saml:Audience").SetText(request.Issuer)
+
+	authnStatement := assertion.CreateElement("saml:AuthnStatement")
+	authnStatement.CreateAttr("AuthnInstant", instant)
+	authnStatement.CreateAttr("SessionIndex", newID())
+	authnStatement.CreateElement("saml:AuthnContext").CreateElement("saml:AuthnContextClassRef").
+		SetText("urn:oasis:names:tc:SAML:2.0:ac:classes:PasswordProtectedTransport")
+
+	attributes := assertion.CreateElement("saml:AttributeStatement")
+	addAttribute(attributes, "email", email)
+	if name != "" {
+		addAttribute(attributes, "name", name)
+	}
+	if len(groups) > 0 {
+		addAttribute(attributes, "groups", groups...)
+	}
+
+	signingContext, err := dsig.NewSigningContext(p.key, [][]byte{p.cert})
+	if err != nil {
+		return nil, err
+	}
+	signingContext.Canonicalizer = dsig.MakeC14N10ExclusiveCanonicalizerWithPrefixList("")
+	signature, err := signingContext.ConstructSignature(assertion, true)
+	if err != nil {
+		return nil, err
+	}
+	// The schema places the signature right after the issuer.
+	assertion.InsertChildAt(1, signature)
+
+	response := etree.NewElement("samlp:Response")
+	response.CreateAttr("xmlns:samlp", protocolNamespace)
+	response.CreateAttr("xmlns:saml", assertionNamespace)
+	response.CreateAttr("ID", newID())
+	response.CreateAttr("Version", "2.0")
+	response.CreateAttr("IssueInstant", instant)
+	response.CreateAttr("Destination", request.AssertionConsumerServiceURL)
+	response.CreateAttr("InResponseTo", request.ID)
+	response.CreateElement("saml:Issuer").SetText(p.entityID)
+	statusCode := response.CreateElement("samlp:Status").CreateElement("samlp:StatusCode")
+	statusCode.CreateAttr("Value", "urn:oasis:names:tc:SAML:2.0:status:Success")
+	response.AddChild(assertion)
+
+	doc := etree.NewDocument()
+	doc.SetRoot(response)
+	return doc.WriteToBytes()
+}
+
+func addAttribute(statement *etree.Element, name string, values ...string) {
+	attribute := statement.CreateElement("saml:Attribute")
+	attribute.CreateAttr("Name", name)
+	for _, value := range values {
+		attribute.CreateElement("saml:AttributeValue").SetText(value)
+	}
+}
+
+func decodeRequest(value string) (*authnRequest, error) {
+	compressed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	content, err := io.ReadAll(io.LimitReader(flate.NewReader(bytes.NewReader(compressed)), 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	var request authnRequest
+	if err := xml.Unmarshal(content, &request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func newID() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return "_" + hex.EncodeToString(b)
+}
@@ -0,0 +1,139 @@
+package admin
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+)
+
+type ListUsersQuery struct {
+	Status        []string  `form:"status" binding:"omitempty,dive,oneof=active inactive invited suspended banned pending_verification"`
+	Role          []string  `form:"role" binding:"omitempty,dive,required,max=50"`
+	CreatedFrom   time.Time `form:"created_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedTo     time.Time `form:"created_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	LastLoginFrom time.Time `form:"last_login_from" time_format:"2006-01-02T15:04:05Z07:00"`
+	LastLoginTo   time.Time `form:"last_login_to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Search        string    `form:"q" binding:"max=100"`
+	Sort          string    `form:"sort" binding:"omitempty,oneof=created_at last_login_at name email"`
+	Order         string    `form:"order" binding:"omitempty,oneof=asc desc"`
+	Limit         int       `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor        string    `form:"cursor"`
+}
+
+type ImportUsersForm struct {
+	Format    string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	DryRun    bool   `form:"dry_run"`
+	BatchSize int    `form:"batch_size" binding:"omitempty,gte=1,lte=1000"`
+}
+
+type ExportUsersQuery struct {
+	Format              string `form:"format" binding:"omitempty,oneof=csv jsonl"`
+	IncludePasswordHash bool   `form:"include_password_hash"`
+}
+
+type PasswordResetRequest struct {
+	RevokeSessions bool `json:"revoke_sessions"`
+}
+
+type AdminUserResponse struct {
+	base.ModelDTO
+	Name               string     `json:"name"`
+	Email              string     `json:"email"`
+	Role               string     `json:"role"`
+	Status             string     `json:"status"`
+	LastLoginAt        *time.Time `json:"last_login_at"`
+	PasswordChangedAt  *time.Time `json:"password_changed_at"`
+	MustChangePassword bool       `json:"must_change_password"`
+}
+
+type ListUsersResponse struct {
+	Items      []AdminUserResponse `json:"items"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+type UserDetailsResponse struct {
+	AdminUserResponse
+	Locale              *string                `json:"locale"`
+	Timezone            *string                `json:"timezone"`
+	Metadata            map[string]interface{} `json:"metadata"`
+	TokenVersion        int                    `json:"token_version"`
+	SessionCount        int64                  `json:"session_count"`
+	Locked              bool                   `json:"locked"`
+	LockedUntil         *time.Time             `json:"locked_until,omitempty"`
+	FailedLoginAttempts int                    `json:"failed_login_attempts"`
+
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	StatusReason   *string    `json:"status_reason,omitempty"`
+
+	DeletionScheduledFor *time.Time `json:"deletion_scheduled_for,omitempty"`
+}
+
+func (q ListUsersQuery) toFilter() user.ListFilter {
+	filter := user.ListFilter{
+		Search:     q.Search,
+		SortBy:     user.SortField(q.Sort),
+		Descending: q.Order == "desc",
+		Limit:      q.Limit,
+	}
+	for _, s := range q.Status {
+		filter.Statuses = append(filter.Statuses, user.Status(s))
+	}
+	filter.Roles = q.Role
+	filter.CreatedFrom = optionalTime(q.CreatedFrom)
+	filter.CreatedTo = optionalTime(q.CreatedTo)
+	filter.LastLoginFrom = optionalTime(q.LastLoginFrom)
+	filter.LastLoginTo = optionalTime(q.LastLoginTo)
+	return filter
+}
+
+func ToAdminUserResponse(u *user.User) AdminUserResponse {
+	return AdminUserResponse{
+		ModelDTO:           base.ToDTO(u.Model),
+		Name:               u.Name,
+		Email:              u.Email,
+		Role:               string(u.Role),
+		Status:             string(u.Status),
+		LastLoginAt:        u.LastLoginAt,
+		PasswordChangedAt:  u.PasswordChangedAt,
+		MustChangePassword: u.MustChangePassword,
+	}
+}
+
+func ToListUsersResponse(page *user.Page) ListUsersResponse {
+	items := make([]AdminUserResponse, 0, len(page.Users))
+	for i := range page.Users {
+		items = append(items, ToAdminUserResponse(&page.Users[i]))
+	}
+	return ListUsersResponse{Items: items, NextCursor: page.NextCursor}
+}
+
+func ToUserDetailsResponse(d *user.Details) UserDetailsResponse {
+	metadata := map[string]interface{}(d.User.Metadata)
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	return UserDetailsResponse{
+		AdminUserResponse:   ToAdminUserResponse(d.User),
+		Locale:              d.User.Locale,
+		Timezone:            d.User.Timezone,
+		Metadata:            metadata,
+		TokenVersion:        d.User.TokenVersion,
+		SessionCount:        d.SessionCount,
+		Locked:              d.Locked,
+		LockedUntil:         d.LockedUntil,
+		FailedLoginAttempts: d.FailedLoginAttempts,
+		SuspendedUntil:      d.User.SuspendedUntil,
+		StatusReason:        d.User.StatusReason,
+
+		DeletionScheduledFor: d.User.DeletionScheduledFor,
+	}
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
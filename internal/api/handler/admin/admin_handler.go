@@ -0,0 +1,361 @@
+package admin
+
+import (
+	"errors"
+	"log"
+	"path/filepath"
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service     user.IAdminService
+	bulkService user.IBulkService
+}
+
+func NewAdminHandler(s user.IAdminService, bulk user.IBulkService) *Handler {
+	return &Handler{service: s, bulkService: bulk}
+}
+
+// ListUsers godoc
+// @Summary Lista usuários (Admin-only)
+// @Description Paginação por cursor (next_cursor), filtros por status, papel, data de criação e último login, e busca sem distinção de maiúsculas em nome e e-mail.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param status query []string false "Status (repetível)" collectionFormat(multi)
+// @Param role query []string false "Papel RBAC, inclusive personalizado (repetível)" collectionFormat(multi)
+// @Param created_from query string false "Criado a partir de (RFC 3339)"
+// @Param created_to query string false "Criado até (RFC 3339)"
+// @Param last_login_from query string false "Último login a partir de (RFC 3339)"
+// @Param last_login_to query string false "Último login até (RFC 3339)"
+// @Param q query string false "Busca em nome e e-mail"
+// @Param sort query string false "Campo de ordenação" Enums(created_at, last_login_at, name, email)
+// @Param order query string false "Direção" Enums(asc, desc)
+// @Param limit query int false "Itens por página (máx. 100)"
+// @Param cursor query string false "Cursor retornado em next_cursor"
+// @Success 200 {object} response.Standard{data=ListUsersResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/users [get]
+func (h *Handler) ListUsers(c *gin.Context) {
+	var query ListUsersQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	filter := query.toFilter()
+	if query.Cursor != "" {
+		cursor, err := user.DecodeCursor(query.Cursor)
+		if err != nil {
+			httphelpers.RespondParamError(c, "cursor", "Cursor inválido")
+			return
+		}
+		filter.After = cursor
+	}
+
+	page, err := h.service.ListUsers(c.Request.Context(), filter)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCursor) {
+			httphelpers.RespondParamError(c, "cursor", "Cursor inválido para a ordenação informada")
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToListUsersResponse(page))
+}
+
+// GetUser godoc
+// @Summary Detalhes de um usuário (Admin-only)
+// @Description Inclui quantidade de sessões ativas e estado de bloqueio por tentativas de login.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} response.Standard{data=UserDetailsResponse}
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/users/{id} [get]
+func (h *Handler) GetUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de usuário inválido na URL")
+		return
+	}
+
+	details, err := h.service.GetUserDetails(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToUserDetailsResponse(details))
+}
+
+// ForceLogout godoc
+// @Summary Encerra todas as sessões de um usuário (Admin-only)
+// @Description Revoga todos os access e refresh tokens do usuário imediatamente.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/users/{id}/logout [post]
+func (h *Handler) ForceLogout(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.ForceLogout(c.Request.Context(), userID); err != nil {
+		respondUserActionError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Sessões do usuário encerradas."})
+}
+
+// SendPasswordReset godoc
+// @Summary Envia ao usuário um e-mail de redefinição de senha (Admin-only)
+// @Description Opcionalmente revoga as sessões atuais do usuário.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param request body PasswordResetRequest false "Revogar sessões atuais"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/users/{id}/password-reset [post]
+func (h *Handler) SendPasswordReset(c *gin.Context) {
+	var req PasswordResetRequest
+
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			httphelpers.RespondBindingError(c, err)
+			return
+		}
+	}
+
+	if err := h.service.SendPasswordReset(c.Request.Context(), userID, req.RevokeSessions); err != nil {
+		respondUserActionError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "E-mail de redefinição de senha enviado."})
+}
+
+// Unlock godoc
+// @Summary Remove o bloqueio por tentativas de login (Admin-only)
+// @Description Libera a conta bloqueada e zera o contador de falhas de login.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/users/{id}/lock [delete]
+func (h *Handler) Unlock(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Unlock(c.Request.Context(), userID); err != nil {
+		respondUserActionError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Conta desbloqueada."})
+}
+
+// ImportUsers godoc
+// @Summary Importa usuários em lote (CSV ou JSON Lines)
+// @Description Colunas/campos: name, email, role (org_admin ou org_member) e password_hash opcional (bcrypt ou argon2id).
+// @Description Usuários sem hash definem a senha pela recuperação de senha. As linhas são gravadas em lotes, cada lote em uma transação;
+// @Description erros são reportados por linha. Com dry_run=true nada é gravado.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "Arquivo .csv ou .jsonl"
+// @Param format formData string false "Formato (padrão: extensão do arquivo)" Enums(csv, jsonl)
+// @Param dry_run formData bool false "Apenas valida, sem gravar"
+// @Param batch_size formData int false "Linhas por transação (máx. 1000)"
+// @Success 200 {object} response.Standard{data=user.ImportReport}
+// @Failure 400 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/users/import [post]
+func (h *Handler) ImportUsers(c *gin.Context) {
+	var form ImportUsersForm
+
+	if err := c.ShouldBind(&form); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		httphelpers.RespondParamError(c, "file", "Arquivo de importação obrigatório")
+		return
+	}
+
+	format := user.BulkFormat(form.Format)
+	if format == "" {
+		format = formatFromFilename(fileHeader.Filename)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+	defer file.Close()
+
+	report, err := h.bulkService.Import(c.Request.Context(), file, user.ImportOptions{
+		Format:    format,
+		DryRun:    form.DryRun,
+		BatchSize: form.BatchSize,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrUnsupportedFormat):
+			httphelpers.RespondParamError(c, "format", "Formato não suportado: use csv ou jsonl")
+		case errors.Is(err, auth.ErrInvalidImportFile):
+			httphelpers.RespondDomainFail(c, "Arquivo inválido: o CSV deve ter cabeçalho com as colunas name e email.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondOK(c, report)
+}
+
+// ExportUsers godoc
+// @Summary Exporta os usuários da organização (CSV ou JSON Lines)
+// @Description A resposta é transmitida em streaming e a exportação é registrada na auditoria com o número de linhas.
+// @Description O hash de senha só é incluído quando solicitado; nesse caso exige autenticação recente (/auth/reauthenticate) e não aceita token de acesso pessoal.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param format query string false "Formato" Enums(csv, jsonl)
+// @Param include_password_hash query bool false "Inclui o hash de senha (migrações)"
+// @Success 200 {file} file
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/users/export [get]
+func (h *Handler) ExportUsers(c *gin.Context) {
+	var query ExportUsersQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	format := user.BulkFormat(query.Format)
+	contentType := "text/csv; charset=utf-8"
+	if format == "" {
+		format = user.FormatCSV
+	}
+	if format == user.FormatJSONL {
+		contentType = "application/x-ndjson"
+	}
+
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", "attachment; filename=users."+string(format))
+	c.Header("Cache-Control", "no-store")
+	c.Status(200)
+
+	if err := h.bulkService.Export(c.Request.Context(), c.Writer, format, query.IncludePasswordHash); err != nil {
+		// Headers are already sent; the truncated body is the only signal left.
+		log.Printf("[ERROR] User export aborted: %v", err)
+	}
+}
+
+// StatusHistory godoc
+// @Summary Histórico de status de um usuário (Admin-only)
+// @Description Mudanças de status, da mais recente para a mais antiga. changed_by nulo indica mudança automática (fim de suspensão).
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} response.Standard{data=[]user.StatusHistoryEntry}
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/users/{id}/status-history [get]
+func (h *Handler) StatusHistory(c *gin.Context) {
+	userID, ok := parseUserID(c)
+	if !ok {
+		return
+	}
+
+	entries, err := h.service.StatusHistory(c.Request.Context(), userID)
+	if err != nil {
+		respondUserActionError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, entries)
+}
+
+func parseUserID(c *gin.Context) (uuid.UUID, bool) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de usuário inválido na URL")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func respondUserActionError(c *gin.Context, err error) {
+	if errors.Is(err, auth.ErrUserNotFound) {
+		httphelpers.RespondNotFound(c)
+		return
+	}
+	if errors.Is(err, auth.ErrPasswordManagedByDirectory) {
+		httphelpers.RespondDomainFail(c, "A senha desta conta é gerenciada pelo diretório da organização.")
+		return
+	}
+	httphelpers.RespondInternalError(c, err)
+}
+
+func formatFromFilename(filename string) user.BulkFormat {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".jsonl", ".ndjson":
+		return user.FormatJSONL
+	default:
+		return user.FormatCSV
+	}
+}
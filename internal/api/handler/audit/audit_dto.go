@@ -0,0 +1,90 @@
+package audit
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/google/uuid"
+)
+
+type ListEventsQuery struct {
+	ActorID   string    `form:"actor_id" binding:"omitempty,uuid"`
+	TargetID  string    `form:"target_id" binding:"omitempty,uuid"`
+	Action    []string  `form:"action" binding:"omitempty,dive,max=64"`
+	Outcome   string    `form:"outcome" binding:"omitempty,oneof=success failure"`
+	RequestID string    `form:"request_id" binding:"max=64"`
+	From      time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To        time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit     int       `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor    string    `form:"cursor"`
+}
+
+type EventResponse struct {
+	ID        uuid.UUID         `json:"id"`
+	Sequence  int64             `json:"sequence"`
+	ActorID   *uuid.UUID        `json:"actor_id"`
+	TargetID  *uuid.UUID        `json:"target_id"`
+	Action    string            `json:"action"`
+	Outcome   string            `json:"outcome"`
+	IPAddress string            `json:"ip_address"`
+	UserAgent string            `json:"user_agent"`
+	RequestID string            `json:"request_id"`
+	Metadata  map[string]string `json:"metadata"`
+	Hash      string            `json:"hash"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+type ListEventsResponse struct {
+	Items      []EventResponse `json:"items"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+func (q ListEventsQuery) toFilter() audit.Filter {
+	filter := audit.Filter{
+		Actions:   q.Action,
+		Outcome:   q.Outcome,
+		RequestID: q.RequestID,
+		Limit:     q.Limit,
+	}
+	filter.ActorID = optionalUUID(q.ActorID)
+	filter.TargetID = optionalUUID(q.TargetID)
+	filter.From = optionalTime(q.From)
+	filter.To = optionalTime(q.To)
+	return filter
+}
+
+func ToListEventsResponse(page *audit.Page) ListEventsResponse {
+	items := make([]EventResponse, 0, len(page.Events))
+	for _, e := range page.Events {
+		items = append(items, EventResponse{
+			ID:        e.ID,
+			Sequence:  e.Sequence,
+			ActorID:   e.ActorID,
+			TargetID:  e.TargetID,
+			Action:    e.Action,
+			Outcome:   e.Outcome,
+			IPAddress: e.IPAddress,
+			UserAgent: e.UserAgent,
+			RequestID: e.RequestID,
+			Metadata:  e.Metadata,
+			Hash:      e.Hash,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+	return ListEventsResponse{Items: items, NextCursor: page.NextCursor}
+}
+
+func optionalUUID(raw string) *uuid.UUID {
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	return &id
+}
+
+func optionalTime(t time.Time) *time.Time {
+	if t.IsZero() {
+		return nil
+	}
+	return &t
+}
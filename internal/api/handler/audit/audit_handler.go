@@ -0,0 +1,62 @@
+package audit
+
+import (
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+type Handler struct {
+	service audit.IService
+}
+
+func NewAuditHandler(s audit.IService) *Handler {
+	return &Handler{service: s}
+}
+
+// ListEvents godoc
+// @Summary Consulta o log de auditoria da organização
+// @Description Eventos de segurança (login, falhas de login, troca de senha, logout global, desativação e alterações administrativas), do mais recente para o mais antigo, com paginação por cursor (next_cursor).
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param actor_id query string false "ID de quem executou a ação"
+// @Param target_id query string false "ID do usuário afetado"
+// @Param action query []string false "Ação, ex.: auth.login (repetível)" collectionFormat(multi)
+// @Param outcome query string false "Resultado" Enums(success, failure)
+// @Param request_id query string false "ID da requisição (X-Request-ID)"
+// @Param from query string false "A partir de (RFC 3339)"
+// @Param to query string false "Até (RFC 3339)"
+// @Param limit query int false "Itens por página (máx. 100)"
+// @Param cursor query string false "Cursor retornado em next_cursor"
+// @Success 200 {object} response.Standard{data=ListEventsResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/audit-events [get]
+func (h *Handler) ListEvents(c *gin.Context) {
+	var query ListEventsQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	filter := query.toFilter()
+	if query.Cursor != "" {
+		sequence, err := audit.DecodeCursor(query.Cursor)
+		if err != nil {
+			httphelpers.RespondParamError(c, "cursor", "Cursor inválido")
+			return
+		}
+		filter.BeforeSequence = sequence
+	}
+
+	page, err := h.service.List(c.Request.Context(), filter)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToListEventsResponse(page))
+}
@@ -1,11 +1,14 @@
 package auth
 
 import (
+	"time"
+
 	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
 	"github.com/felipedenardo/chameleon-common/pkg/base"
 )
 
 type RegisterRequest struct {
+	Organization    string `json:"organization" binding:"omitempty,max=63" example:"default"`
 	Name            string `json:"name" binding:"required,min=3,max=100"`
 	Email           string `json:"email" binding:"required,email"`
 	Password        string `json:"password" binding:"required,min=8" example:"Senha@123"`
@@ -13,8 +16,9 @@ type RegisterRequest struct {
 }
 
 type LoginRequest struct {
-	Email    string `json:"email" binding:"required,email"`
-	Password string `json:"password" binding:"required"`
+	Organization string `json:"organization" binding:"omitempty,max=63" example:"default"`
+	Email        string `json:"email" binding:"required,email"`
+	Password     string `json:"password" binding:"required"`
 }
 
 type LogoutRequest struct {
@@ -23,25 +27,49 @@ type LogoutRequest struct {
 
 type UserResponse struct {
 	base.ModelDTO
-	Name   string `json:"name"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
-	Status string `json:"status"`
+	OrganizationID string     `json:"organization_id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	Status         string     `json:"status"`
+	LastLoginAt    *time.Time `json:"last_login_at,omitempty"`
 }
 
 type LoginResponse struct {
-	Token        string       `json:"token"`
-	RefreshToken string       `json:"refresh_token"`
-	User         UserResponse `json:"user"`
+	Token                  string       `json:"token"`
+	TokenType              string       `json:"token_type,omitempty" example:"Bearer"`
+	RefreshToken           string       `json:"refresh_token"`
+	User                   UserResponse `json:"user"`
+	PasswordChangeRequired bool         `json:"password_change_required,omitempty"`
+	DeletionCancelled      bool         `json:"deletion_cancelled,omitempty"`
+
+	// With OTPRequired set no token is issued: the login continues at
+	// /auth/login/otp with OTPChallenge and the code sent by e-mail.
+	OTPRequired  bool   `json:"otp_required,omitempty"`
+	OTPChallenge string `json:"otp_challenge,omitempty"`
 }
 
 func ToUserResponse(u *user.User) UserResponse {
 	return UserResponse{
-		ModelDTO: base.ToDTO(u.Model),
-		Name:     u.Name,
-		Email:    u.Email,
-		Role:     string(u.Role),
-		Status:   string(u.Status),
+		ModelDTO:       base.ToDTO(u.Model),
+		OrganizationID: u.OrganizationID.String(),
+		Name:           u.Name,
+		Email:          u.Email,
+		Role:           string(u.Role),
+		Status:         string(u.Status),
+		LastLoginAt:    u.LastLoginAt,
+	}
+}
+
+func ToLoginResponse(r *user.LoginResult) LoginResponse {
+	return LoginResponse{
+		Token:                  r.AccessToken,
+		RefreshToken:           r.RefreshToken,
+		User:                   ToUserResponse(r.User),
+		PasswordChangeRequired: r.PasswordChangeRequired,
+		DeletionCancelled:      r.DeletionCancelled,
+		OTPRequired:            r.OTPRequired,
+		OTPChallenge:           r.OTPChallenge,
 	}
 }
 
@@ -52,7 +80,36 @@ type ChangePasswordRequest struct {
 }
 
 type ForgotPasswordRequest struct {
-	Email string `json:"email" binding:"required,email"`
+	Organization string `json:"organization" binding:"omitempty,max=63" example:"default"`
+	Email        string `json:"email" binding:"required,email"`
+}
+
+type MagicLinkRequest struct {
+	Organization string `json:"organization" binding:"omitempty,max=63" example:"default"`
+	Email        string `json:"email" binding:"required,email"`
+}
+
+// MagicLinkResponse carries the nonce that must come back with the link. It
+// is also set as an HttpOnly cookie for browser clients.
+type MagicLinkResponse struct {
+	Message string `json:"message"`
+	Nonce   string `json:"nonce"`
+}
+
+// MagicLinkVerifyRequest takes the token of the e-mailed link. Without Nonce,
+// the magic_link_nonce cookie is used.
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+	Nonce string `json:"nonce"`
+}
+
+type ReactivationRequest struct {
+	Organization string `json:"organization" binding:"omitempty,max=63" example:"default"`
+	Email        string `json:"email" binding:"required,email"`
+}
+
+type ReactivateRequest struct {
+	Token string `json:"token" binding:"required"`
 }
 
 type ResetPasswordRequest struct {
@@ -61,14 +118,121 @@ type ResetPasswordRequest struct {
 	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=NewPassword"`
 }
 
+type OTPVerifyRequest struct {
+	Challenge string `json:"challenge" binding:"required"`
+	Code      string `json:"code" binding:"required,len=6,numeric" example:"123456"`
+}
+
+type StepUpChallengeResponse struct {
+	Challenge string `json:"challenge"`
+}
+
+// StepUpTokenResponse carries the single-use token sent in the X-Step-Up-Token
+// header of one sensitive operation.
+type StepUpTokenResponse struct {
+	StepUpToken string `json:"step_up_token"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// ReauthenticateRequest confirms the password again. With the challenge and
+// code of /me/step-up the new tokens get acr aal2.
+type ReauthenticateRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	OTPChallenge    string `json:"otp_challenge"`
+	OTPCode         string `json:"otp_code" binding:"required_with=OTPChallenge,omitempty,len=6,numeric" example:"123456"`
+	RefreshToken    string `json:"refresh_token"`
+}
+
 type DeactivateRequest struct {
 	CurrentPassword string `json:"current_password" binding:"required"`
 }
 
+// DeletionRequest confirms the account deletion with the current password.
+type DeletionRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+type DeletionResponse struct {
+	ScheduledFor time.Time `json:"scheduled_for"`
+}
+
 type StatusUpdateRequest struct {
-	NewStatus string `json:"status" binding:"required,oneof=active inactive"`
+	NewStatus      string     `json:"status" binding:"required,oneof=active inactive suspended banned pending_verification"`
+	Reason         string     `json:"reason" binding:"omitempty,max=500"`
+	SuspendedUntil *time.Time `json:"suspended_until"`
+}
+
+// AccountStatusResponse tells a user with valid credentials why the account
+// cannot sign in.
+type AccountStatusResponse struct {
+	Code           string     `json:"code" example:"account_suspended"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	// ReactivationAvailable is set for accounts the user deactivated, which
+	// POST /auth/reactivate/request can bring back.
+	ReactivationAvailable bool `json:"reactivation_available,omitempty"`
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" binding:"required"`
 }
+
+// TokenRequest is the form of the OAuth token endpoint
+// (application/x-www-form-urlencoded).
+type TokenRequest struct {
+	GrantType          string `form:"grant_type" binding:"required"`
+	SubjectToken       string `form:"subject_token"`
+	SubjectTokenType   string `form:"subject_token_type"`
+	ActorToken         string `form:"actor_token"`
+	ActorTokenType     string `form:"actor_token_type"`
+	RequestedTokenType string `form:"requested_token_type"`
+	DeviceCode         string `form:"device_code"`
+	ClientID           string `form:"client_id"`
+}
+
+// AccessTokenResponse is the token endpoint answer of grants that start a
+// session (RFC 6749, 5.1).
+type AccessTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type" example:"Bearer"`
+	ExpiresIn    int64  `json:"expires_in"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// DeviceAuthorizationRequest is the form of the device authorization
+// endpoint (application/x-www-form-urlencoded).
+type DeviceAuthorizationRequest struct {
+	ClientID string `form:"client_id" binding:"required"`
+}
+
+type DeviceAuthorizationResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code" example:"WDJB-MJHT"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int64  `json:"expires_in"`
+	Interval                int64  `json:"interval"`
+}
+
+type DeviceRequestResponse struct {
+	ClientID  string    `json:"client_id"`
+	UserCode  string    `json:"user_code" example:"WDJB-MJHT"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+type DeviceDecisionRequest struct {
+	UserCode string `json:"user_code" binding:"required" example:"WDJB-MJHT"`
+	Approve  *bool  `json:"approve" binding:"required"`
+}
+
+type TokenExchangeResponse struct {
+	AccessToken     string `json:"access_token"`
+	IssuedTokenType string `json:"issued_token_type"`
+	TokenType       string `json:"token_type" example:"Bearer"`
+	ExpiresIn       int64  `json:"expires_in"`
+}
+
+// OAuthErrorResponse is the error body of the token endpoint (RFC 6749, 5.2).
+type OAuthErrorResponse struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description,omitempty"`
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"log"
 	"net/http"
 	"regexp"
 	"strings"
@@ -12,15 +13,22 @@ import (
 
 	"github.com/felipedenardo/chameleon-auth-api/internal/config"
 	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
 	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
 	"github.com/felipedenardo/chameleon-auth-api/internal/infra/ratelimit"
 	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
 	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/felipedenardo/chameleon-common/pkg/response"
 	"github.com/felipedenardo/chameleon-common/pkg/validation"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// magicLinkNonceCookie holds the nonce that binds a magic link to the browser
+// that requested it.
+const magicLinkNonceCookie = "magic_link_nonce"
+
 type Handler struct {
 	service  user.IService
 	cfg      *config.Config
@@ -64,9 +72,11 @@ func (h *Handler) Register(c *gin.Context) {
 		return
 	}
 
-	userDomain, err := h.service.Register(c.Request.Context(), req.Name, req.Email, req.Password)
+	userDomain, err := h.service.Register(c.Request.Context(), req.Organization, req.Name, req.Email, req.Password)
 	if err != nil {
-		if errors.Is(err, auth.ErrEmailAlreadyExists) {
+		if errors.Is(err, auth.ErrEmailAlreadyExists) ||
+			errors.Is(err, auth.ErrSelfSignupDisabled) ||
+			errors.Is(err, organization.ErrOrganizationNotFound) {
 			httphelpers.RespondDomainFail(c, "Não foi possível concluir o cadastro.")
 			return
 		}
@@ -84,9 +94,15 @@ func (h *Handler) Register(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Credenciais do usuário para login"
+// @Description Credenciais válidas de uma conta que não pode entrar retornam 403 com o código do status em data.code:
+// @Description account_inactive, account_suspended (com suspended_until), account_banned ou account_pending_verification.
+// @Description Com o código por e-mail ativo, a resposta traz otp_required e otp_challenge em vez dos tokens; o login continua em /auth/login/otp.
+// @Description Em organizações com diretório LDAP/AD configurado a senha é verificada no diretório; 503 se ele estiver indisponível.
 // @Success 200 {object} response.Standard{data=LoginResponse}
 // @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard{data=AccountStatusResponse}
 // @Failure 500 {object} response.Standard
+// @Failure 503 {object} response.Standard
 // @Router /auth/login [post]
 func (h *Handler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -99,19 +115,107 @@ func (h *Handler) Login(c *gin.Context) {
 		return
 	}
 
-	token, refreshToken, userDomain, err := h.service.Login(c.Request.Context(), req.Email, req.Password)
+	result, err := h.service.Login(c.Request.Context(), req.Organization, req.Email, req.Password)
 	if err != nil {
+		var statusErr *auth.AccountStatusError
+		if errors.As(err, &statusErr) {
+			RespondAccountStatus(c, statusErr)
+			return
+		}
+		if errors.Is(err, directory.ErrUnavailable) {
+			log.Printf("[ERROR] Directory login failed: %v", err)
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, response.Standard{
+				Status:  "error",
+				Message: "O diretório da organização está indisponível. Tente novamente mais tarde.",
+			})
+			return
+		}
 		httphelpers.RespondUnauthorized(c, "Credenciais inválidas.")
 		return
 	}
 
-	responseDTO := LoginResponse{
-		Token:        token,
-		RefreshToken: refreshToken,
-		User:         ToUserResponse(userDomain),
+	httphelpers.RespondOK(c, ToLoginResponse(result))
+}
+
+// RequestMagicLink godoc
+// @Summary Solicita um link de acesso sem senha
+// @Description Envia ao e-mail da conta um link de uso único e curta duração. A resposta (igual para e-mails inexistentes) traz um nonce,
+// @Description também gravado no cookie HttpOnly magic_link_nonce, que deve acompanhar o token na verificação: um link encaminhado não funciona em outro navegador.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body MagicLinkRequest true "E-mail da conta"
+// @Success 200 {object} response.Standard{data=MagicLinkResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 429 {object} response.Standard
+// @Router /auth/login/magic-link [post]
+func (h *Handler) RequestMagicLink(c *gin.Context) {
+	var req MagicLinkRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
 	}
 
-	httphelpers.RespondOK(c, responseDTO)
+	if err := h.checkRateLimit(c, "magic_link", req.Email, h.cfg.ForgotRateLimit, h.cfg.ForgotRateWindowSec); err != nil {
+		return
+	}
+
+	nonce, err := h.service.RequestMagicLink(c.Request.Context(), req.Organization, req.Email)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkNonceCookie, nonce, h.cfg.MagicLinkTTLMin*60, "/", "", c.Request.TLS != nil, true)
+	httphelpers.RespondOK(c, MagicLinkResponse{
+		Message: "Se o usuário existir, um link de acesso foi enviado.",
+		Nonce:   nonce,
+	})
+}
+
+// VerifyMagicLink godoc
+// @Summary Entra com o link de acesso recebido por e-mail
+// @Description Emite o mesmo par de tokens do login com senha. O nonce vem do corpo ou do cookie magic_link_nonce.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body MagicLinkVerifyRequest true "Token do link e nonce"
+// @Success 200 {object} response.Standard{data=LoginResponse}
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard{data=AccountStatusResponse}
+// @Router /auth/login/magic-link/verify [post]
+func (h *Handler) VerifyMagicLink(c *gin.Context) {
+	var req MagicLinkVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	nonce := req.Nonce
+	if nonce == "" {
+		nonce, _ = c.Cookie(magicLinkNonceCookie)
+	}
+
+	result, err := h.service.LoginWithMagicLink(c.Request.Context(), req.Token, nonce)
+	if err != nil {
+		var statusErr *auth.AccountStatusError
+		switch {
+		case errors.As(err, &statusErr):
+			RespondAccountStatus(c, statusErr)
+		case errors.Is(err, auth.ErrInvalidMagicLink), errors.Is(err, auth.ErrAccountLocked):
+			httphelpers.RespondUnauthorized(c, "Link de acesso inválido ou expirado.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkNonceCookie, "", -1, "/", "", c.Request.TLS != nil, true)
+	httphelpers.RespondOK(c, ToLoginResponse(result))
 }
 
 // ChangePassword godoc
@@ -122,6 +226,7 @@ func (h *Handler) Login(c *gin.Context) {
 // @Produce json
 // @Param request body ChangePasswordRequest true "Dados para alteração de senha"
 // @Success 200 {object} response.Standard
+// @Param X-Step-Up-Token header string false "Exigido de usuários com código por e-mail ativo (/me/step-up)"
 // @Router /auth/change-password [post]
 func (h *Handler) ChangePassword(c *gin.Context) {
 	var req ChangePasswordRequest
@@ -166,6 +271,10 @@ func (h *Handler) ChangePassword(c *gin.Context) {
 			httphelpers.RespondDomainFail(c, "Não foi possível alterar a senha.")
 			return
 		}
+		if errors.Is(err, auth.ErrPasswordManagedByDirectory) {
+			httphelpers.RespondDomainFail(c, "A senha desta conta é gerenciada pelo diretório da organização.")
+			return
+		}
 		httphelpers.RespondInternalError(c, err)
 		return
 	}
@@ -277,7 +386,7 @@ func (h *Handler) ForgotPassword(c *gin.Context) {
 		return
 	}
 
-	token, err := h.service.ForgotPassword(c.Request.Context(), req.Email)
+	token, err := h.service.ForgotPassword(c.Request.Context(), req.Organization, req.Email)
 
 	if err != nil {
 		httphelpers.RespondInternalError(c, err)
@@ -296,6 +405,67 @@ func (h *Handler) ForgotPassword(c *gin.Context) {
 	httphelpers.RespondOK(c, gin.H{"message": "Se o usuário existir, um link de reset foi enviado."})
 }
 
+// RequestReactivation godoc
+// @Summary Solicita a reativação de uma conta desativada pelo próprio usuário
+// @Description Envia um link de uso único ao e-mail da conta. Contas desativadas por um administrador, suspensas ou banidas não recebem o link.
+// @Description A resposta é a mesma em todos os casos.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ReactivationRequest true "E-mail da conta"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 429 {object} response.Standard
+// @Router /auth/reactivate/request [post]
+func (h *Handler) RequestReactivation(c *gin.Context) {
+	var req ReactivationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	if err := h.checkRateLimit(c, "reactivate", req.Email, h.cfg.ForgotRateLimit, h.cfg.ForgotRateWindowSec); err != nil {
+		return
+	}
+
+	if err := h.service.RequestReactivation(c.Request.Context(), req.Organization, req.Email); err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Se a conta puder ser reativada, um link foi enviado para o e-mail."})
+}
+
+// Reactivate godoc
+// @Summary Reativa a conta com o token recebido por e-mail
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body ReactivateRequest true "Token de reativação"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Router /auth/reactivate [post]
+func (h *Handler) Reactivate(c *gin.Context) {
+	var req ReactivateRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	if err := h.service.Reactivate(c.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, auth.ErrInvalidReactivationToken) {
+			httphelpers.RespondDomainFail(c, "Link de reativação inválido ou expirado.")
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Conta reativada. Faça login novamente."})
+}
+
 // ResetPassword godoc
 // @Summary Finalizar reset de senha
 // @Description Recebe o token de reset e a nova senha para atualizar no DB.
@@ -336,11 +506,15 @@ func (h *Handler) ResetPassword(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary Renovar tokens
+// @Description Com uma prova DPoP (RFC 9449) no header DPoP, os tokens emitidos são vinculados à chave da prova e token_type é DPoP.
+// @Description Um refresh token vinculado só é aceito com a prova da mesma chave. Prova inválida ou reutilizada responde 400 invalid_dpop_proof.
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body RefreshTokenRequest true "Refresh token"
+// @Param DPoP header string false "Prova DPoP (JWT dpop+jwt) para vincular os tokens à chave"
 // @Success 200 {object} response.Standard{data=LoginResponse}
+// @Failure 400 {object} OAuthErrorResponse
 // @Failure 401 {object} response.Standard
 // @Failure 500 {object} response.Standard
 // @Router /auth/refresh [post]
@@ -368,6 +542,7 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 
 	responseDTO := LoginResponse{
 		Token:        accessToken,
+		TokenType:    tokenType(c),
 		RefreshToken: refreshToken,
 		User:         ToUserResponse(userDomain),
 	}
@@ -378,6 +553,7 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 // DeactivateSelf godoc
 // @Summary Desativa a própria conta do usuário
 // @Description Exige a senha atual para confirmar a intenção e desativa o status do usuário (soft delete).
+// @Description Exige também um login ou reautenticação (/auth/reauthenticate) dos últimos REAUTH_MAX_AGE_SEC segundos; caso contrário responde 401 com o desafio insufficient_user_authentication.
 // @Tags Auth
 // @Security ApiKeyAuth
 // @Accept json
@@ -386,6 +562,7 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 // @Success 200 {object} response.Standard
 // @Failure 401 {object} response.Standard
 // @Failure 400 {object} response.Standard
+// @Param X-Step-Up-Token header string false "Exigido de usuários com código por e-mail ativo (/me/step-up)"
 // @Router /auth/deactivate [post]
 func (h *Handler) DeactivateSelf(c *gin.Context) {
 	var req DeactivateRequest
@@ -430,9 +607,117 @@ func (h *Handler) DeactivateSelf(c *gin.Context) {
 	httphelpers.RespondOK(c, gin.H{"message": "Account deactivated successfully."})
 }
 
+// RequestDeletion godoc
+// @Summary Solicita a exclusão da própria conta
+// @Description Exige a senha atual. Os dados pessoais são anonimizados após o período de carência (ACCOUNT_DELETION_GRACE_DAYS);
+// @Description todas as sessões são encerradas e um novo login dentro do prazo cancela a exclusão.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body DeletionRequest true "Senha atual do usuário"
+// @Success 200 {object} response.Standard{data=DeletionResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Param X-Step-Up-Token header string false "Exigido de usuários com código por e-mail ativo (/me/step-up)"
+// @Router /me/delete [post]
+func (h *Handler) RequestDeletion(c *gin.Context) {
+	var req DeletionRequest
+
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return
+	}
+
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	scheduledFor, err := h.service.RequestDeletion(c.Request.Context(), userID, req.CurrentPassword)
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidCurrentPassword) {
+			httphelpers.RespondDomainFail(c, "Não foi possível solicitar a exclusão da conta.")
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, DeletionResponse{ScheduledFor: scheduledFor})
+}
+
+// Reauthenticate godoc
+// @Summary Confirma a identidade do usuário logado
+// @Description Atende ao desafio insufficient_user_authentication (RFC 9470) das rotas que exigem autenticação recente ou acr mínimo.
+// @Description Emite novos tokens com auth_time atual, amr e acr (aal2 com otp_challenge e otp_code de /me/step-up), revoga o access token atual e consome o refresh_token informado.
+// @Tags Auth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body ReauthenticateRequest true "Senha atual e, opcionalmente, código de verificação"
+// @Success 200 {object} response.Standard{data=LoginResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 429 {object} response.Standard
+// @Router /auth/reauthenticate [post]
+func (h *Handler) Reauthenticate(c *gin.Context) {
+	var req ReauthenticateRequest
+
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	token, exists := middleware.RequireRawToken(c)
+	if !exists {
+		return
+	}
+
+	if err := h.checkRateLimitKey(c, "reauthenticate", userID.String(), h.cfg.LoginRateLimit, h.cfg.LoginRateWindowSec); err != nil {
+		return
+	}
+
+	result, err := h.service.Reauthenticate(c.Request.Context(), userID, user.Reauthentication{
+		Password:     req.CurrentPassword,
+		OTPChallenge: req.OTPChallenge,
+		OTPCode:      req.OTPCode,
+		AccessToken:  token,
+		RefreshToken: req.RefreshToken,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidCurrentPassword), errors.Is(err, auth.ErrAccountLocked):
+			httphelpers.RespondUnauthorized(c, "Credenciais inválidas.")
+		case errors.Is(err, auth.ErrInvalidOTP), errors.Is(err, auth.ErrOTPAttemptsExceeded):
+			httphelpers.RespondUnauthorized(c, "Código inválido ou expirado.")
+		case errors.Is(err, auth.ErrInvalidRefreshToken):
+			httphelpers.RespondUnauthorized(c, "Refresh token inválido.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondOK(c, ToLoginResponse(result))
+}
+
 // UpdateUserStatus godoc
 // @Summary Atualiza o status de um usuário (Admin-only)
-// @Description Permite ao Admin banir, suspender ou reativar um usuário.
+// @Description Permite ao Admin banir, suspender ou reativar um usuário. Toda alteração de status revoga as sessões do usuário.
+// @Description suspended exige suspended_until no futuro (a conta volta a active automaticamente); banned exige reason.
 // @Tags Admin
 // @Security ApiKeyAuth
 // @Accept json
@@ -442,18 +727,14 @@ func (h *Handler) DeactivateSelf(c *gin.Context) {
 // @Success 200 {object} response.Standard
 // @Failure 401 {object} response.Standard
 // @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Failure 400 {object} response.Standard
 // @Router /admin/users/{id}/status [put]
 func (h *Handler) UpdateUserStatus(c *gin.Context) {
 	var req StatusUpdateRequest
 
-	requesterRole, _ := c.Get("role")
 	targetUserID := c.Param("id")
 
-	if user.Role(requesterRole.(string)) != user.RoleAdmin {
-		httphelpers.RespondUnauthorized(c, "Acesso negado.")
-		return
-	}
-
 	if err := c.ShouldBindJSON(&req); err != nil {
 		httphelpers.RespondBindingError(c, err)
 		return
@@ -465,16 +746,67 @@ func (h *Handler) UpdateUserStatus(c *gin.Context) {
 		return
 	}
 
-	err = h.service.UpdateUserStatus(c.Request.Context(), userID, user.Status(req.NewStatus))
+	change := user.StatusChange{
+		Status:         user.Status(req.NewStatus),
+		Reason:         req.Reason,
+		SuspendedUntil: req.SuspendedUntil,
+	}
+	if rawActorID, ok := middleware.GetUserID(c); ok {
+		if actorID, err := uuid.Parse(rawActorID); err == nil {
+			change.ChangedBy = &actorID
+		}
+	}
+
+	err = h.service.UpdateUserStatus(c.Request.Context(), userID, change)
 
 	if err != nil {
-		httphelpers.RespondInternalError(c, err)
+		switch {
+		case errors.Is(err, auth.ErrUserNotFound):
+			httphelpers.RespondNotFound(c)
+		case errors.Is(err, auth.ErrInvalidSuspension):
+			httphelpers.RespondDomainFail(c, "A suspensão exige suspended_until no futuro.")
+		case errors.Is(err, auth.ErrStatusReasonRequired):
+			httphelpers.RespondDomainFail(c, "O banimento exige um motivo (reason).")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
 		return
 	}
 
 	httphelpers.RespondOK(c, gin.H{"message": fmt.Sprintf("Status do usuário %s alterado para %s.", targetUserID, req.NewStatus)})
 }
 
+// ForcePasswordChange godoc
+// @Summary Força a troca de senha no próximo login (Admin-only)
+// @Description Marca o usuário para troca obrigatória de senha e revoga as sessões atuais.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/users/{id}/force-password-change [post]
+func (h *Handler) ForcePasswordChange(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de usuário inválido na URL")
+		return
+	}
+
+	if err = h.service.ForcePasswordChange(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Troca de senha obrigatória no próximo login."})
+}
+
 func (h *Handler) checkRateLimit(c *gin.Context, action string, email string, limit int, windowSec int) error {
 	if h.limiter == nil || limit <= 0 || windowSec <= 0 {
 		return nil
@@ -532,6 +864,26 @@ func (h *Handler) isStrongPassword(pw string) bool {
 	return true
 }
 
+var accountStatusCodes = map[error]string{
+	auth.ErrAccountInactive:            "account_inactive",
+	auth.ErrAccountSuspended:           "account_suspended",
+	auth.ErrAccountBanned:              "account_banned",
+	auth.ErrAccountPendingVerification: "account_pending_verification",
+}
+
+// RespondAccountStatus answers a login refused because of the account status.
+func RespondAccountStatus(c *gin.Context, statusErr *auth.AccountStatusError) {
+	c.AbortWithStatusJSON(http.StatusForbidden, response.Standard{
+		Status:  "fail",
+		Message: "Esta conta não pode entrar no momento.",
+		Data: AccountStatusResponse{
+			Code:                  accountStatusCodes[statusErr.Err],
+			SuspendedUntil:        statusErr.SuspendedUntil,
+			ReactivationAvailable: statusErr.Reactivatable,
+		},
+	})
+}
+
 func hashKey(value string) string {
 	sum := sha256.Sum256([]byte(value))
 	return hex.EncodeToString(sum[:])
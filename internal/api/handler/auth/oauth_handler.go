@@ -0,0 +1,302 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/dpop"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// Token godoc
+// @Summary Endpoint de token OAuth 2.0
+// @Description Suporta o grant token-exchange (RFC 8693) para impersonação: actor_token é o access token de um admin com a permissão users:impersonate
+// @Description e subject_token o ID do usuário alvo (subject_token_type urn:chameleon:params:oauth:token-type:user_id), que não pode ter permissões que o admin não possui.
+// @Description O token emitido é curto, não renovável, traz a claim act com o admin e é recusado em troca de senha, desativação e troca de e-mail.
+// @Description Suporta também o grant device_code (RFC 8628): o dispositivo consulta com device_code e client_id até o usuário aprovar o código,
+// @Description recebendo authorization_pending enquanto isso, slow_down se consultar antes do intervalo, access_denied se o usuário recusar e expired_token após a expiração.
+// @Description Aprovado, responde access_token, token_type, expires_in e refresh_token (AccessTokenResponse).
+// @Description Com uma prova DPoP (RFC 9449) no header DPoP, os tokens emitidos são vinculados à chave da prova (claim cnf.jkt) e token_type é DPoP;
+// @Description um actor_token vinculado exige a prova da sua chave. Prova inválida ou reutilizada responde invalid_dpop_proof.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param grant_type formData string true "urn:ietf:params:oauth:grant-type:token-exchange ou urn:ietf:params:oauth:grant-type:device_code"
+// @Param subject_token formData string false "ID do usuário alvo (token-exchange)"
+// @Param subject_token_type formData string false "urn:chameleon:params:oauth:token-type:user_id (token-exchange)"
+// @Param actor_token formData string false "Access token do admin (token-exchange)"
+// @Param actor_token_type formData string false "urn:ietf:params:oauth:token-type:access_token (token-exchange)"
+// @Param requested_token_type formData string false "urn:ietf:params:oauth:token-type:access_token (token-exchange)"
+// @Param device_code formData string false "device_code de /oauth/device_authorization (device_code)"
+// @Param client_id formData string false "Cliente que iniciou a autorização (device_code)"
+// @Param DPoP header string false "Prova DPoP (JWT dpop+jwt) para vincular os tokens à chave"
+// @Success 200 {object} TokenExchangeResponse
+// @Failure 400 {object} OAuthErrorResponse
+// @Failure 401 {object} OAuthErrorResponse
+// @Router /oauth/token [post]
+func (h *Handler) Token(c *gin.Context) {
+	var req TokenRequest
+
+	c.Header("Cache-Control", "no-store")
+
+	if err := c.ShouldBind(&req); err != nil {
+		respondOAuthError(c, http.StatusBadRequest, "invalid_request", "grant_type is required")
+		return
+	}
+
+	switch req.GrantType {
+	case auth.GrantTypeTokenExchange:
+		h.exchangeToken(c, req)
+	case auth.GrantTypeDeviceCode:
+		h.deviceToken(c, req)
+	default:
+		respondOAuthError(c, http.StatusBadRequest, "unsupported_grant_type", "")
+	}
+}
+
+func (h *Handler) exchangeToken(c *gin.Context, req TokenRequest) {
+	if req.ActorToken == "" || req.ActorTokenType != auth.TokenTypeAccessToken {
+		respondOAuthError(c, http.StatusBadRequest, "invalid_request", "actor_token of type access_token is required")
+		return
+	}
+	if req.SubjectTokenType != auth.TokenTypeUserID {
+		respondOAuthError(c, http.StatusBadRequest, "invalid_request", "unsupported subject_token_type")
+		return
+	}
+	if req.RequestedTokenType != "" && req.RequestedTokenType != auth.TokenTypeAccessToken {
+		respondOAuthError(c, http.StatusBadRequest, "invalid_request", "unsupported requested_token_type")
+		return
+	}
+
+	targetID, err := uuid.Parse(req.SubjectToken)
+	if err != nil {
+		respondOAuthError(c, http.StatusBadRequest, "invalid_request", "subject_token must be a user ID")
+		return
+	}
+
+	if err := h.checkRateLimitKey(c, "token_exchange", req.ActorToken, h.cfg.RefreshRateLimit, h.cfg.RefreshRateWindowSec); err != nil {
+		return
+	}
+
+	result, err := h.service.Impersonate(c.Request.Context(), req.ActorToken, targetID)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrInvalidActorToken):
+			respondOAuthError(c, http.StatusBadRequest, "invalid_grant", "invalid actor_token")
+		case errors.Is(err, auth.ErrImpersonationForbidden):
+			respondOAuthError(c, http.StatusBadRequest, "unauthorized_client", "impersonation of this user is not allowed")
+		case errors.Is(err, auth.ErrUserNotFound), errors.Is(err, auth.ErrAccountInactive):
+			respondOAuthError(c, http.StatusBadRequest, "invalid_grant", "invalid subject_token")
+		default:
+			respondOAuthError(c, http.StatusInternalServerError, "server_error", "")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, TokenExchangeResponse{
+		AccessToken:     result.AccessToken,
+		IssuedTokenType: auth.TokenTypeAccessToken,
+		TokenType:       tokenType(c),
+		ExpiresIn:       int64(result.ExpiresIn.Seconds()),
+	})
+}
+
+func (h *Handler) deviceToken(c *gin.Context, req TokenRequest) {
+	if req.DeviceCode == "" || req.ClientID == "" {
+		respondOAuthError(c, http.StatusBadRequest, "invalid_request", "device_code and client_id are required")
+		return
+	}
+
+	result, err := h.service.PollDeviceAuthorization(c.Request.Context(), req.ClientID, req.DeviceCode)
+	if err != nil {
+		var statusErr *auth.AccountStatusError
+		switch {
+		case errors.Is(err, auth.ErrAuthorizationPending):
+			respondOAuthError(c, http.StatusBadRequest, "authorization_pending", "")
+		case errors.Is(err, auth.ErrSlowDown):
+			respondOAuthError(c, http.StatusBadRequest, "slow_down", "")
+		case errors.Is(err, auth.ErrDeviceAccessDenied):
+			respondOAuthError(c, http.StatusBadRequest, "access_denied", "")
+		case errors.Is(err, auth.ErrDeviceCodeExpired):
+			respondOAuthError(c, http.StatusBadRequest, "expired_token", "")
+		case errors.Is(err, auth.ErrUnknownClient):
+			respondOAuthError(c, http.StatusUnauthorized, "invalid_client", "")
+		case errors.Is(err, auth.ErrPasswordChangeRequired):
+			respondOAuthError(c, http.StatusBadRequest, "invalid_grant", "password change required")
+		case errors.As(err, &statusErr):
+			respondOAuthError(c, http.StatusBadRequest, "invalid_grant", "account is not active")
+		default:
+			respondOAuthError(c, http.StatusInternalServerError, "server_error", "")
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, AccessTokenResponse{
+		AccessToken:  result.AccessToken,
+		TokenType:    tokenType(c),
+		ExpiresIn:    int64(time.Duration(h.cfg.TokenTTLHours) * time.Hour / time.Second),
+		RefreshToken: result.RefreshToken,
+	})
+}
+
+// DeviceAuthorization godoc
+// @Summary Inicia a autorização de um dispositivo (RFC 8628)
+// @Description Para CLIs e dispositivos sem navegador. O dispositivo exibe user_code e verification_uri e consulta POST /oauth/token com o grant device_code
+// @Description e o device_code a cada interval segundos, até um usuário logado aprovar o código. Só clientes de DEVICE_CLIENT_IDS são aceitos.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param client_id formData string true "ID do cliente"
+// @Success 200 {object} DeviceAuthorizationResponse
+// @Failure 400 {object} OAuthErrorResponse
+// @Failure 401 {object} OAuthErrorResponse
+// @Failure 429 {object} response.Standard
+// @Router /oauth/device_authorization [post]
+func (h *Handler) DeviceAuthorization(c *gin.Context) {
+	var req DeviceAuthorizationRequest
+
+	c.Header("Cache-Control", "no-store")
+
+	if err := c.ShouldBind(&req); err != nil {
+		respondOAuthError(c, http.StatusBadRequest, "invalid_request", "client_id is required")
+		return
+	}
+
+	if err := h.checkRateLimitKey(c, "device_authorization", req.ClientID, h.cfg.LoginRateLimit, h.cfg.LoginRateWindowSec); err != nil {
+		return
+	}
+
+	authorization, err := h.service.StartDeviceAuthorization(c.Request.Context(), req.ClientID)
+	if err != nil {
+		if errors.Is(err, auth.ErrUnknownClient) {
+			respondOAuthError(c, http.StatusUnauthorized, "invalid_client", "")
+			return
+		}
+		respondOAuthError(c, http.StatusInternalServerError, "server_error", "")
+		return
+	}
+
+	completeURI := authorization.VerificationURI + "?user_code=" + url.QueryEscape(authorization.UserCode)
+	c.JSON(http.StatusOK, DeviceAuthorizationResponse{
+		DeviceCode:              authorization.DeviceCode,
+		UserCode:                authorization.UserCode,
+		VerificationURI:         authorization.VerificationURI,
+		VerificationURIComplete: completeURI,
+		ExpiresIn:               int64(authorization.ExpiresIn.Seconds()),
+		Interval:                int64(authorization.Interval.Seconds()),
+	})
+}
+
+// GetDeviceRequest godoc
+// @Summary Consulta o pedido de autorização de um dispositivo
+// @Description Usado pela página de verificação para mostrar ao usuário logado qual cliente pede acesso antes da aprovação.
+// @Tags Auth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_code query string true "Código exibido no dispositivo"
+// @Success 200 {object} response.Standard{data=DeviceRequestResponse}
+// @Failure 401 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Failure 429 {object} response.Standard
+// @Router /oauth/device [get]
+func (h *Handler) GetDeviceRequest(c *gin.Context) {
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return
+	}
+
+	if err := h.checkRateLimitKey(c, "device_verification", userIDString, h.cfg.LoginRateLimit, h.cfg.LoginRateWindowSec); err != nil {
+		return
+	}
+
+	request, err := h.service.FindDeviceRequest(c.Request.Context(), c.Query("user_code"))
+	if err != nil {
+		if errors.Is(err, auth.ErrInvalidUserCode) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, DeviceRequestResponse{
+		ClientID:  request.ClientID,
+		UserCode:  request.UserCode,
+		ExpiresAt: request.ExpiresAt,
+	})
+}
+
+// DecideDeviceRequest godoc
+// @Summary Aprova ou recusa a autorização de um dispositivo
+// @Description Aprovando, o dispositivo recebe na próxima consulta tokens do usuário logado, com o mesmo auth_time, amr e acr da sessão que aprovou. O código só pode ser usado uma vez.
+// @Tags Auth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body DeviceDecisionRequest true "Código exibido no dispositivo e decisão"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Failure 429 {object} response.Standard
+// @Router /oauth/device [post]
+func (h *Handler) DecideDeviceRequest(c *gin.Context) {
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return
+	}
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return
+	}
+	token, exists := middleware.RequireRawToken(c)
+	if !exists {
+		return
+	}
+
+	var req DeviceDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	if err := h.checkRateLimitKey(c, "device_verification", userIDString, h.cfg.LoginRateLimit, h.cfg.LoginRateWindowSec); err != nil {
+		return
+	}
+
+	if err := h.service.DecideDeviceAuthorization(c.Request.Context(), userID, token, req.UserCode, *req.Approve); err != nil {
+		if errors.Is(err, auth.ErrInvalidUserCode) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	message := "Dispositivo recusado."
+	if *req.Approve {
+		message = "Dispositivo autorizado."
+	}
+	httphelpers.RespondOK(c, gin.H{"message": message})
+}
+
+// tokenType is the token_type of the tokens issued for the request: DPoP when
+// they are bound to the key of its DPoP proof.
+func tokenType(c *gin.Context) string {
+	if dpop.Thumbprint(c.Request.Context()) != "" {
+		return dpop.TokenType
+	}
+	return "Bearer"
+}
+
+func respondOAuthError(c *gin.Context, status int, code string, description string) {
+	c.AbortWithStatusJSON(status, OAuthErrorResponse{Error: code, ErrorDescription: description})
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/api/middleware"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	commonmiddleware "github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// VerifyLoginOTP godoc
+// @Summary Conclui o login com o código enviado por e-mail
+// @Description Usado quando o login respondeu otp_required. Códigos errados contam para o bloqueio da conta; após EMAIL_OTP_MAX_ATTEMPTS tentativas o desafio é descartado e o login deve ser refeito.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body OTPVerifyRequest true "Desafio e código"
+// @Success 200 {object} response.Standard{data=LoginResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard{data=AccountStatusResponse}
+// @Router /auth/login/otp [post]
+func (h *Handler) VerifyLoginOTP(c *gin.Context) {
+	var req OTPVerifyRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	result, err := h.service.LoginWithEmailOTP(c.Request.Context(), req.Challenge, req.Code)
+	if err != nil {
+		var statusErr *auth.AccountStatusError
+		switch {
+		case errors.As(err, &statusErr):
+			RespondAccountStatus(c, statusErr)
+		case errors.Is(err, auth.ErrOTPAttemptsExceeded):
+			httphelpers.RespondUnauthorized(c, "Muitas tentativas. Faça login novamente.")
+		case errors.Is(err, auth.ErrInvalidOTP), errors.Is(err, auth.ErrAccountLocked):
+			httphelpers.RespondUnauthorized(c, "Código inválido ou expirado.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondOK(c, ToLoginResponse(result))
+}
+
+// StartStepUp godoc
+// @Summary Envia um código de verificação adicional
+// @Description Inicia a verificação exigida, de usuários com código por e-mail ativo, antes de trocar a senha, o e-mail ou desativar/excluir a conta.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=StepUpChallengeResponse}
+// @Failure 401 {object} response.Standard
+// @Failure 429 {object} response.Standard
+// @Router /me/step-up [post]
+func (h *Handler) StartStepUp(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.checkRateLimitKey(c, "step_up", userID.String(), h.cfg.ForgotRateLimit, h.cfg.ForgotRateWindowSec); err != nil {
+		return
+	}
+
+	challenge, err := h.service.StartStepUp(c.Request.Context(), userID)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, StepUpChallengeResponse{Challenge: challenge})
+}
+
+// VerifyStepUp godoc
+// @Summary Confirma o código de verificação adicional
+// @Description Retorna um token de uso único, enviado no cabeçalho X-Step-Up-Token da operação sensível, válido por STEP_UP_TTL_MINUTES.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body OTPVerifyRequest true "Desafio e código"
+// @Success 200 {object} response.Standard{data=StepUpTokenResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Router /me/step-up/verify [post]
+func (h *Handler) VerifyStepUp(c *gin.Context) {
+	var req OTPVerifyRequest
+
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	stepUpToken, err := h.service.VerifyStepUp(c.Request.Context(), userID, req.Challenge, req.Code)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrOTPAttemptsExceeded):
+			httphelpers.RespondUnauthorized(c, "Muitas tentativas. Solicite um novo código.")
+		case errors.Is(err, auth.ErrInvalidOTP):
+			httphelpers.RespondUnauthorized(c, "Código inválido ou expirado.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondOK(c, StepUpTokenResponse{
+		StepUpToken: stepUpToken,
+		ExpiresIn:   h.cfg.StepUpTTLMin * 60,
+	})
+}
+
+// EnableEmailOTP godoc
+// @Summary Ativa o código por e-mail como segundo fator
+// @Description Exige sempre o cabeçalho X-Step-Up-Token, que comprova o recebimento dos códigos no e-mail da conta.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Param X-Step-Up-Token header string true "Token de /me/step-up/verify"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /me/mfa/email [post]
+func (h *Handler) EnableEmailOTP(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.EnableEmailOTP(c.Request.Context(), userID, c.GetHeader(middleware.StepUpHeader)); err != nil {
+		if errors.Is(err, auth.ErrStepUpRequired) {
+			httphelpers.RespondForbidden(c, "Verificação adicional necessária. Informe o código enviado por e-mail em /me/step-up.")
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Código por e-mail ativado."})
+}
+
+// DisableEmailOTP godoc
+// @Summary Desativa o código por e-mail como segundo fator
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Param X-Step-Up-Token header string true "Token de /me/step-up/verify"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /me/mfa/email [delete]
+func (h *Handler) DisableEmailOTP(c *gin.Context) {
+	userID, ok := requireTokenUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DisableEmailOTP(c.Request.Context(), userID); err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Código por e-mail desativado."})
+}
+
+func requireTokenUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDString, exists := commonmiddleware.RequireUserID(c)
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
@@ -0,0 +1,52 @@
+package federation
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/google/uuid"
+)
+
+type ProviderResponse struct {
+	Name        string `json:"name" example:"google"`
+	DisplayName string `json:"display_name" example:"Google"`
+}
+
+type AuthorizationResponse struct {
+	AuthorizationURL string `json:"authorization_url"`
+}
+
+type CallbackRequest struct {
+	State string `json:"state" binding:"required"`
+	Code  string `json:"code" binding:"required"`
+}
+
+type IdentityResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Provider    string     `json:"provider" example:"google"`
+	Email       string     `json:"email"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+func ToProviderResponses(providers []federation.ProviderInfo) []ProviderResponse {
+	responses := make([]ProviderResponse, 0, len(providers))
+	for _, p := range providers {
+		responses = append(responses, ProviderResponse{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	return responses
+}
+
+func ToIdentityResponses(identities []federation.Identity) []IdentityResponse {
+	responses := make([]IdentityResponse, 0, len(identities))
+	for _, i := range identities {
+		responses = append(responses, IdentityResponse{
+			ID:          i.ID,
+			Provider:    i.Provider,
+			Email:       i.Email,
+			CreatedAt:   i.CreatedAt,
+			LastLoginAt: i.LastLoginAt,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,238 @@
+package federation
+
+import (
+	"errors"
+	"net/http"
+
+	authhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// stateCookie binds a federated login to the browser that started it, so a
+// callback URL with someone else's code cannot be replayed elsewhere.
+const stateCookie = "oidc_state"
+
+type Handler struct {
+	service federation.IService
+	cfg     *config.Config
+}
+
+func NewFederationHandler(s federation.IService, cfg *config.Config) *Handler {
+	return &Handler{service: s, cfg: cfg}
+}
+
+// ListProviders godoc
+// @Summary Lista os provedores de login externos
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]ProviderResponse}
+// @Router /auth/oidc/providers [get]
+func (h *Handler) ListProviders(c *gin.Context) {
+	httphelpers.RespondOK(c, ToProviderResponses(h.service.Providers()))
+}
+
+// Authorize godoc
+// @Summary Inicia o login com um provedor externo
+// @Description Devolve a URL do provedor para onde o navegador deve ser enviado e grava o cookie oidc_state. O provedor redireciona para OIDC_REDIRECT_URL, que envia code e state para /auth/oidc/callback.
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Nome do provedor"
+// @Success 200 {object} response.Standard{data=AuthorizationResponse}
+// @Failure 404 {object} response.Standard
+// @Router /auth/oidc/{provider}/authorize [post]
+func (h *Handler) Authorize(c *gin.Context) {
+	authorizationURL, state, err := h.service.StartLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if errors.Is(err, federation.ErrProviderNotFound) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	h.respondAuthorization(c, authorizationURL, state)
+}
+
+// Callback godoc
+// @Summary Conclui o login com um provedor externo
+// @Description Troca o código do provedor pelos tokens da API. No primeiro acesso a identidade é vinculada à conta com o mesmo e-mail verificado ou uma conta é criada, conforme a configuração do provedor.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body CallbackRequest true "Código e state recebidos do provedor"
+// @Success 200 {object} response.Standard{data=authhandler.LoginResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard{data=authhandler.AccountStatusResponse}
+// @Router /auth/oidc/callback [post]
+func (h *Handler) Callback(c *gin.Context) {
+	var req CallbackRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	cookieState, _ := c.Cookie(stateCookie)
+	if cookieState != req.State {
+		httphelpers.RespondUnauthorized(c, "Sessão de login inválida ou expirada. Tente novamente.")
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(stateCookie, "", -1, "/", "", c.Request.TLS != nil, true)
+
+	result, err := h.service.CompleteLogin(c.Request.Context(), req.State, req.Code)
+	if err != nil {
+		var statusErr *auth.AccountStatusError
+		switch {
+		case errors.As(err, &statusErr):
+			authhandler.RespondAccountStatus(c, statusErr)
+		case errors.Is(err, federation.ErrInvalidState):
+			httphelpers.RespondUnauthorized(c, "Sessão de login inválida ou expirada. Tente novamente.")
+		case errors.Is(err, federation.ErrInvalidIDToken), errors.Is(err, federation.ErrCodeExchange), errors.Is(err, auth.ErrAccountLocked):
+			httphelpers.RespondUnauthorized(c, "Não foi possível concluir o login com o provedor.")
+		case errors.Is(err, federation.ErrEmailNotVerified):
+			httphelpers.RespondForbidden(c, "O provedor não confirmou o e-mail da conta.")
+		case errors.Is(err, federation.ErrDomainNotAllowed):
+			httphelpers.RespondForbidden(c, "O domínio do e-mail não é permitido para este provedor.")
+		case errors.Is(err, federation.ErrAccountNotFound):
+			httphelpers.RespondForbidden(c, "Não existe conta para esta identidade.")
+		case errors.Is(err, federation.ErrOrganizationMismatch):
+			httphelpers.RespondForbidden(c, "Este provedor não pertence à sua organização.")
+		case errors.Is(err, federation.ErrAccountLinkRequired):
+			httphelpers.RespondDomainFail(c, "Já existe uma conta com este e-mail. Entre com a senha e vincule o provedor no seu perfil.")
+		case errors.Is(err, federation.ErrIdentityAlreadyLinked):
+			httphelpers.RespondDomainFail(c, "Esta identidade já está vinculada a outra conta.")
+		case errors.Is(err, federation.ErrProviderAlreadyLinked):
+			httphelpers.RespondDomainFail(c, "A conta já possui uma identidade deste provedor.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondOK(c, authhandler.ToLoginResponse(result))
+}
+
+// ListIdentities godoc
+// @Summary Lista as identidades externas vinculadas ao usuário logado
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]IdentityResponse}
+// @Failure 401 {object} response.Standard
+// @Router /me/identities [get]
+func (h *Handler) ListIdentities(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	identities, err := h.service.ListIdentities(c.Request.Context(), userID)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToIdentityResponses(identities))
+}
+
+// Link godoc
+// @Summary Vincula um provedor externo à conta do usuário logado
+// @Description Inicia o mesmo fluxo do login; o callback vincula a identidade do provedor à conta em vez de criar uma sessão nova. Exige autenticação recente.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Param provider path string true "Nome do provedor"
+// @Success 200 {object} response.Standard{data=AuthorizationResponse}
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /me/identities/{provider} [post]
+func (h *Handler) Link(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	authorizationURL, state, err := h.service.StartLink(c.Request.Context(), userID, c.Param("provider"))
+	if err != nil {
+		switch {
+		case errors.Is(err, federation.ErrProviderNotFound):
+			httphelpers.RespondNotFound(c)
+		case errors.Is(err, federation.ErrOrganizationMismatch):
+			httphelpers.RespondForbidden(c, "Este provedor não pertence à sua organização.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	h.respondAuthorization(c, authorizationURL, state)
+}
+
+// Unlink godoc
+// @Summary Desvincula uma identidade externa
+// @Description Não é possível remover a única forma de acesso de uma conta sem senha.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID da identidade"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /me/identities/{id} [delete]
+func (h *Handler) Unlink(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	identityID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de identidade inválido na URL")
+		return
+	}
+
+	if err := h.service.Unlink(c.Request.Context(), userID, identityID); err != nil {
+		switch {
+		case errors.Is(err, federation.ErrIdentityNotFound):
+			httphelpers.RespondNotFound(c)
+		case errors.Is(err, federation.ErrLastLoginMethod):
+			httphelpers.RespondDomainFail(c, "Defina uma senha antes de remover a única forma de acesso da conta.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondDeleted(c)
+}
+
+func (h *Handler) respondAuthorization(c *gin.Context, authorizationURL string, state string) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(stateCookie, state, h.cfg.OIDCStateTTLMin*60, "/", "", c.Request.TLS != nil, true)
+	httphelpers.RespondOK(c, AuthorizationResponse{AuthorizationURL: authorizationURL})
+}
+
+func requireUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
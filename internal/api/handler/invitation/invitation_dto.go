@@ -0,0 +1,53 @@
+package invitation
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/invitation"
+	"github.com/google/uuid"
+)
+
+type CreateInvitationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	Name  string `json:"name" binding:"omitempty,max=100"`
+	Role  string `json:"role" binding:"omitempty,oneof=org_admin org_member" example:"org_member"`
+}
+
+type AcceptInvitationRequest struct {
+	Token           string `json:"token" binding:"required"`
+	Name            string `json:"name" binding:"required,min=3,max=100"`
+	Password        string `json:"password" binding:"required,min=8" example:"Senha@123"`
+	ConfirmPassword string `json:"confirm_password" binding:"required,eqfield=Password"`
+}
+
+type InvitationResponse struct {
+	ID        uuid.UUID  `json:"id"`
+	UserID    *uuid.UUID `json:"user_id"`
+	Email     string     `json:"email"`
+	Role      string     `json:"role"`
+	Status    string     `json:"status"`
+	InvitedBy *uuid.UUID `json:"invited_by"`
+	ExpiresAt time.Time  `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+func ToInvitationResponse(inv *invitation.Invitation) InvitationResponse {
+	return InvitationResponse{
+		ID:        inv.ID,
+		UserID:    inv.UserID,
+		Email:     inv.Email,
+		Role:      inv.Role,
+		Status:    inv.Status(time.Now()),
+		InvitedBy: inv.InvitedBy,
+		ExpiresAt: inv.ExpiresAt,
+		CreatedAt: inv.CreatedAt,
+	}
+}
+
+func ToInvitationResponses(invitations []invitation.Invitation) []InvitationResponse {
+	responses := make([]InvitationResponse, 0, len(invitations))
+	for i := range invitations {
+		responses = append(responses, ToInvitationResponse(&invitations[i]))
+	}
+	return responses
+}
@@ -0,0 +1,198 @@
+package invitation
+
+import (
+	"errors"
+	"regexp"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/invitation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var (
+	pwUpper  = regexp.MustCompile(`[A-Z]`)
+	pwLower  = regexp.MustCompile(`[a-z]`)
+	pwSymbol = regexp.MustCompile(`[^A-Za-z0-9]`)
+)
+
+type Handler struct {
+	service invitation.IService
+}
+
+func NewInvitationHandler(s invitation.IService) *Handler {
+	return &Handler{service: s}
+}
+
+// CreateInvitation godoc
+// @Summary Convida um usuário para a organização
+// @Description Cria o usuário com status invited e envia por e-mail um token de uso único para definir a senha.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateInvitationRequest true "E-mail, nome opcional e papel na organização"
+// @Success 201 {object} response.Standard{data=InvitationResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/invitations [post]
+func (h *Handler) CreateInvitation(c *gin.Context) {
+	var req CreateInvitationRequest
+
+	inviterIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return
+	}
+	inviterID, err := uuid.Parse(inviterIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	inv, err := h.service.Invite(c.Request.Context(), inviterID, req.Email, req.Name, req.Role)
+	if err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	httphelpers.RespondCreated(c, ToInvitationResponse(inv))
+}
+
+// ListInvitations godoc
+// @Summary Lista os convites em aberto da organização
+// @Description Inclui convites expirados, que ainda podem ser reenviados.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]InvitationResponse}
+// @Failure 403 {object} response.Standard
+// @Router /admin/invitations [get]
+func (h *Handler) ListInvitations(c *gin.Context) {
+	invitations, err := h.service.ListOpen(c.Request.Context())
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToInvitationResponses(invitations))
+}
+
+// ResendInvitation godoc
+// @Summary Reenvia um convite
+// @Description Gera um novo token, invalidando o anterior, e reinicia a expiração.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do convite"
+// @Success 200 {object} response.Standard{data=InvitationResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/invitations/{id}/resend [post]
+func (h *Handler) ResendInvitation(c *gin.Context) {
+	invitationID, ok := parseInvitationID(c)
+	if !ok {
+		return
+	}
+
+	inv, err := h.service.Resend(c.Request.Context(), invitationID)
+	if err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToInvitationResponse(inv))
+}
+
+// RevokeInvitation godoc
+// @Summary Revoga um convite
+// @Description O usuário convidado, que ainda não acessou, é removido.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do convite"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/invitations/{id} [delete]
+func (h *Handler) RevokeInvitation(c *gin.Context) {
+	invitationID, ok := parseInvitationID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), invitationID); err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	httphelpers.RespondDeleted(c)
+}
+
+// AcceptInvitation godoc
+// @Summary Aceita um convite
+// @Description O convidado define nome e senha; a conta é ativada e o token é consumido.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body AcceptInvitationRequest true "Token do convite, nome e senha"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Router /invitations/accept [post]
+func (h *Handler) AcceptInvitation(c *gin.Context) {
+	var req AcceptInvitationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	if !isStrongPassword(req.Password) {
+		httphelpers.RespondDomainFail(c, "Senha deve ter no mínimo 8 caracteres, 1 maiúscula, 1 minúscula e 1 especial.")
+		return
+	}
+
+	if err := h.service.Accept(c.Request.Context(), req.Token, req.Name, req.Password); err != nil {
+		respondInvitationError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Convite aceito. Você já pode fazer login."})
+}
+
+func parseInvitationID(c *gin.Context) (uuid.UUID, bool) {
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de convite inválido na URL")
+		return uuid.Nil, false
+	}
+	return invitationID, true
+}
+
+func respondInvitationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, invitation.ErrInvitationNotFound):
+		httphelpers.RespondNotFound(c)
+	case errors.Is(err, invitation.ErrInvalidInvitation):
+		httphelpers.RespondDomainFail(c, "Convite inválido ou expirado.")
+	case errors.Is(err, invitation.ErrInvitationClosed):
+		httphelpers.RespondDomainFail(c, "O convite já foi aceito ou revogado.")
+	case errors.Is(err, organization.ErrInvalidMemberRole):
+		httphelpers.RespondDomainFail(c, "Papel de organização inválido.")
+	case errors.Is(err, auth.ErrEmailAlreadyExists):
+		httphelpers.RespondDomainFail(c, "Já existe um usuário com este e-mail na organização.")
+	default:
+		httphelpers.RespondInternalError(c, err)
+	}
+}
+
+func isStrongPassword(pw string) bool {
+	return len(pw) >= 8 && pwUpper.MatchString(pw) && pwLower.MatchString(pw) && pwSymbol.MatchString(pw)
+}
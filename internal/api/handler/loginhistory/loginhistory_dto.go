@@ -0,0 +1,46 @@
+package loginhistory
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/google/uuid"
+)
+
+type ListLoginHistoryQuery struct {
+	Limit  int    `form:"limit" binding:"omitempty,gte=1,lte=100"`
+	Cursor string `form:"cursor"`
+}
+
+type LoginHistoryEntryResponse struct {
+	ID                uuid.UUID `json:"id"`
+	Success           bool      `json:"success"`
+	FailureReason     string    `json:"failure_reason,omitempty"`
+	IPAddress         string    `json:"ip_address"`
+	UserAgent         string    `json:"user_agent"`
+	DeviceFingerprint string    `json:"device_fingerprint"`
+	NewDevice         bool      `json:"new_device"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type ListLoginHistoryResponse struct {
+	Items      []LoginHistoryEntryResponse `json:"items"`
+	NextCursor string                      `json:"next_cursor,omitempty"`
+}
+
+func ToListLoginHistoryResponse(page *loginhistory.Page) ListLoginHistoryResponse {
+	items := make([]LoginHistoryEntryResponse, 0, len(page.Entries))
+	for _, e := range page.Entries {
+		items = append(items, LoginHistoryEntryResponse{
+			ID:                e.ID,
+			Success:           e.Success,
+			FailureReason:     e.FailureReason,
+			IPAddress:         e.IPAddress,
+			UserAgent:         e.UserAgent,
+			DeviceFingerprint: e.DeviceFingerprint,
+			NewDevice:         e.NewDevice,
+			CreatedAt:         e.CreatedAt,
+		})
+	}
+	return ListLoginHistoryResponse{Items: items, NextCursor: page.NextCursor}
+}
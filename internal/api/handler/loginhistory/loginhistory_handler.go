@@ -0,0 +1,94 @@
+package loginhistory
+
+import (
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service loginhistory.IService
+}
+
+func NewLoginHistoryHandler(s loginhistory.IService) *Handler {
+	return &Handler{service: s}
+}
+
+// ListMine godoc
+// @Summary Histórico de login do usuário logado
+// @Description Tentativas de login com e sem sucesso, da mais recente para a mais antiga, com IP, user agent e indicação de novo dispositivo. Paginação por cursor (next_cursor).
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Param limit query int false "Itens por página (máx. 100)"
+// @Param cursor query string false "Cursor retornado em next_cursor"
+// @Success 200 {object} response.Standard{data=ListLoginHistoryResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Router /me/login-history [get]
+func (h *Handler) ListMine(c *gin.Context) {
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return
+	}
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return
+	}
+
+	h.list(c, userID)
+}
+
+// ListForUser godoc
+// @Summary Histórico de login de um usuário (Admin-only)
+// @Description Tentativas de login com e sem sucesso do usuário da organização, da mais recente para a mais antiga.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param limit query int false "Itens por página (máx. 100)"
+// @Param cursor query string false "Cursor retornado em next_cursor"
+// @Success 200 {object} response.Standard{data=ListLoginHistoryResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/users/{id}/login-history [get]
+func (h *Handler) ListForUser(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de usuário inválido na URL")
+		return
+	}
+
+	h.list(c, userID)
+}
+
+func (h *Handler) list(c *gin.Context, userID uuid.UUID) {
+	var query ListLoginHistoryQuery
+
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	var cursor *loginhistory.Cursor
+	if query.Cursor != "" {
+		decoded, err := loginhistory.DecodeCursor(query.Cursor)
+		if err != nil {
+			httphelpers.RespondParamError(c, "cursor", "Cursor inválido")
+			return
+		}
+		cursor = decoded
+	}
+
+	page, err := h.service.List(c.Request.Context(), userID, cursor, query.Limit)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToListLoginHistoryResponse(page))
+}
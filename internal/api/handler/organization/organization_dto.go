@@ -0,0 +1,88 @@
+package organization
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/google/uuid"
+)
+
+type CreateOrganizationRequest struct {
+	Name            string `json:"name" binding:"required,min=2,max=255" example:"Acme"`
+	Slug            string `json:"slug" binding:"required,min=1,max=63" example:"acme"`
+	AllowSelfSignup bool   `json:"allow_self_signup"`
+	OwnerName       string `json:"owner_name" binding:"required,min=3,max=100"`
+	OwnerEmail      string `json:"owner_email" binding:"required,email"`
+	OwnerPassword   string `json:"owner_password" binding:"required,min=8" example:"Senha@123"`
+}
+
+type UpdateMemberRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=org_admin org_member" example:"org_admin"`
+}
+
+type OrganizationResponse struct {
+	ID              uuid.UUID  `json:"id"`
+	Name            string     `json:"name"`
+	Slug            string     `json:"slug"`
+	Status          string     `json:"status"`
+	AllowSelfSignup bool       `json:"allow_self_signup"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+type MemberResponse struct {
+	UserID      uuid.UUID  `json:"user_id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Status      string     `json:"status"`
+	Role        string     `json:"role"`
+	JoinedAt    time.Time  `json:"joined_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
+
+func (r CreateOrganizationRequest) toDomain() organization.NewOrganization {
+	return organization.NewOrganization{
+		Name:            r.Name,
+		Slug:            r.Slug,
+		AllowSelfSignup: r.AllowSelfSignup,
+		OwnerName:       r.OwnerName,
+		OwnerEmail:      r.OwnerEmail,
+		OwnerPassword:   r.OwnerPassword,
+	}
+}
+
+func ToOrganizationResponse(o *organization.Organization) OrganizationResponse {
+	return OrganizationResponse{
+		ID:              o.ID,
+		Name:            o.Name,
+		Slug:            o.Slug,
+		Status:          o.Status,
+		AllowSelfSignup: o.AllowSelfSignup,
+		CreatedAt:       o.CreatedAt,
+		UpdatedAt:       o.UpdatedAt,
+	}
+}
+
+func ToOrganizationResponses(orgs []organization.Organization) []OrganizationResponse {
+	responses := make([]OrganizationResponse, 0, len(orgs))
+	for i := range orgs {
+		responses = append(responses, ToOrganizationResponse(&orgs[i]))
+	}
+	return responses
+}
+
+func ToMemberResponses(members []organization.Member) []MemberResponse {
+	responses := make([]MemberResponse, 0, len(members))
+	for _, m := range members {
+		responses = append(responses, MemberResponse{
+			UserID:      m.UserID,
+			Name:        m.Name,
+			Email:       m.Email,
+			Status:      m.Status,
+			Role:        m.RoleName,
+			JoinedAt:    m.JoinedAt,
+			LastLoginAt: m.LastSeen,
+		})
+	}
+	return responses
+}
@@ -0,0 +1,208 @@
+package organization
+
+import (
+	"errors"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service organization.IService
+}
+
+func NewOrganizationHandler(s organization.IService) *Handler {
+	return &Handler{service: s}
+}
+
+// CreateOrganization godoc
+// @Summary Cria uma organização e seu proprietário
+// @Description O proprietário deve trocar a senha no primeiro login.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateOrganizationRequest true "Organização e credenciais do proprietário"
+// @Success 201 {object} response.Standard{data=OrganizationResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/organizations [post]
+func (h *Handler) CreateOrganization(c *gin.Context) {
+	var req CreateOrganizationRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	org, err := h.service.CreateOrganization(c.Request.Context(), req.toDomain())
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	httphelpers.RespondCreated(c, ToOrganizationResponse(org))
+}
+
+// ListOrganizations godoc
+// @Summary Lista as organizações
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]OrganizationResponse}
+// @Failure 403 {object} response.Standard
+// @Router /admin/organizations [get]
+func (h *Handler) ListOrganizations(c *gin.Context) {
+	orgs, err := h.service.ListOrganizations(c.Request.Context())
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToOrganizationResponses(orgs))
+}
+
+// GetCurrentOrganization godoc
+// @Summary Retorna a organização do usuário logado
+// @Tags Organization
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=OrganizationResponse}
+// @Failure 401 {object} response.Standard
+// @Router /organization [get]
+func (h *Handler) GetCurrentOrganization(c *gin.Context) {
+	org, err := h.service.GetOrganization(c.Request.Context(), tenant.OrganizationID(c.Request.Context()))
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToOrganizationResponse(org))
+}
+
+// ListMembers godoc
+// @Summary Lista os membros da organização do usuário logado
+// @Tags Organization
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]MemberResponse}
+// @Failure 403 {object} response.Standard
+// @Router /organization/members [get]
+func (h *Handler) ListMembers(c *gin.Context) {
+	members, err := h.service.ListMembers(c.Request.Context(), tenant.OrganizationID(c.Request.Context()))
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToMemberResponses(members))
+}
+
+// UpdateMemberRole godoc
+// @Summary Altera o papel de um membro na organização
+// @Description Proprietários não podem ser alterados. As sessões do membro são revogadas.
+// @Tags Organization
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param user_id path string true "ID do usuário"
+// @Param request body UpdateMemberRoleRequest true "Novo papel"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /organization/members/{user_id}/role [put]
+func (h *Handler) UpdateMemberRole(c *gin.Context) {
+	var req UpdateMemberRoleRequest
+
+	actorID, userID, ok := parseMemberIDs(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.UpdateMemberRole(ctx, tenant.OrganizationID(ctx), actorID, userID, req.Role); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	httphelpers.RespondUpdated(c, gin.H{"message": "Papel do membro atualizado."})
+}
+
+// RemoveMember godoc
+// @Summary Remove um membro da organização
+// @Description A conta do membro é desativada e suas sessões revogadas.
+// @Tags Organization
+// @Security ApiKeyAuth
+// @Produce json
+// @Param user_id path string true "ID do usuário"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /organization/members/{user_id} [delete]
+func (h *Handler) RemoveMember(c *gin.Context) {
+	actorID, userID, ok := parseMemberIDs(c)
+	if !ok {
+		return
+	}
+
+	ctx := c.Request.Context()
+	if err := h.service.RemoveMember(ctx, tenant.OrganizationID(ctx), actorID, userID); err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+
+	httphelpers.RespondDeleted(c)
+}
+
+func parseMemberIDs(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	actorIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	actorID, err := uuid.Parse(actorIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(c.Param("user_id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "ID de usuário inválido na URL")
+		return uuid.Nil, uuid.Nil, false
+	}
+	return actorID, userID, true
+}
+
+func respondOrganizationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, organization.ErrOrganizationNotFound),
+		errors.Is(err, organization.ErrMemberNotFound),
+		errors.Is(err, auth.ErrUserNotFound):
+		httphelpers.RespondNotFound(c)
+	case errors.Is(err, organization.ErrSlugTaken):
+		httphelpers.RespondDomainFail(c, "Já existe uma organização com este identificador.")
+	case errors.Is(err, organization.ErrInvalidSlug):
+		httphelpers.RespondDomainFail(c, "Identificador inválido: use letras minúsculas, números e hífens.")
+	case errors.Is(err, organization.ErrInvalidMemberRole):
+		httphelpers.RespondDomainFail(c, "Papel de organização inválido.")
+	case errors.Is(err, organization.ErrOwnerProtected):
+		httphelpers.RespondForbidden(c, "Proprietários da organização não podem ser alterados.")
+	case errors.Is(err, organization.ErrCannotManageSelf):
+		httphelpers.RespondDomainFail(c, "Você não pode alterar a própria participação.")
+	case errors.Is(err, auth.ErrEmailAlreadyExists):
+		httphelpers.RespondDomainFail(c, "Não foi possível criar o proprietário.")
+	default:
+		httphelpers.RespondInternalError(c, err)
+	}
+}
@@ -0,0 +1,56 @@
+package personaltoken
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/personaltoken"
+	"github.com/google/uuid"
+)
+
+type CreateTokenRequest struct {
+	Name string `json:"name" binding:"required,min=3,max=100" example:"deploy-ci"`
+	// Scopes are permissions of the user; none gives a token that only
+	// reaches the routes without a permission check.
+	Scopes        []string `json:"scopes" binding:"omitempty,max=50,dive,min=1,max=100" example:"users:read"`
+	ExpiresInDays int      `json:"expires_in_days" binding:"omitempty,min=1" example:"90"`
+}
+
+type TokenResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix" example:"pat_Xk2hQ9aL"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Expired    bool       `json:"expired"`
+	Revoked    bool       `json:"revoked"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type CreateTokenResponse struct {
+	TokenResponse
+	// Token is only returned here.
+	Token string `json:"token"`
+}
+
+func ToTokenResponse(t *personaltoken.Token) TokenResponse {
+	return TokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		Scopes:     t.ScopeList(),
+		ExpiresAt:  t.ExpiresAt,
+		Expired:    t.Expired(time.Now()),
+		Revoked:    t.Revoked,
+		CreatedAt:  t.CreatedAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
+
+func ToTokenResponses(tokens []personaltoken.Token) []TokenResponse {
+	responses := make([]TokenResponse, 0, len(tokens))
+	for i := range tokens {
+		responses = append(responses, ToTokenResponse(&tokens[i]))
+	}
+	return responses
+}
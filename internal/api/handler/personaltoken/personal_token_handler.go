@@ -0,0 +1,138 @@
+package personaltoken
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/personaltoken"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service personaltoken.IService
+}
+
+func NewPersonalTokenHandler(s personaltoken.IService) *Handler {
+	return &Handler{service: s}
+}
+
+// ListTokens godoc
+// @Summary Lista os tokens de acesso pessoal do usuário logado
+// @Description Inclui os tokens expirados e os invalidados pela revogação das sessões (revoked), até serem removidos. O token em si nunca é exibido de novo, só o prefixo.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]TokenResponse}
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /me/tokens [get]
+func (h *Handler) ListTokens(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	tokens, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToTokenResponses(tokens))
+}
+
+// CreateToken godoc
+// @Summary Cria um token de acesso pessoal
+// @Description Para scripts e jobs de CI, no lugar da senha. O token é exibido apenas nesta resposta e é enviado como Bearer, como um access token. Os escopos devem ser permissões do usuário; a cada uso o token só tem os escopos que o usuário ainda possui. Sem expires_in_days vale PAT_DEFAULT_TTL_DAYS, até PAT_MAX_TTL_DAYS.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateTokenRequest true "Nome, escopos e validade"
+// @Success 201 {object} response.Standard{data=CreateTokenResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /me/tokens [post]
+func (h *Handler) CreateToken(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	token, rawToken, err := h.service.Create(c.Request.Context(), userID, req.Name, req.Scopes, req.ExpiresInDays)
+	if err != nil {
+		switch {
+		case errors.Is(err, personaltoken.ErrInvalidScope):
+			httphelpers.RespondParamError(c, "scopes", "Os escopos devem ser permissões do usuário.")
+		case errors.Is(err, personaltoken.ErrInvalidExpiry):
+			httphelpers.RespondParamError(c, "expires_in_days", "Validade acima do máximo permitido.")
+		case errors.Is(err, personaltoken.ErrNameTaken):
+			httphelpers.RespondDomainFail(c, "Já existe um token com este nome.")
+		case errors.Is(err, personaltoken.ErrTooManyTokens):
+			httphelpers.RespondDomainFail(c, fmt.Sprintf("Limite de %d tokens atingido. Revogue um token antes de criar outro.", personaltoken.MaxTokensPerUser))
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondCreated(c, CreateTokenResponse{TokenResponse: ToTokenResponse(token), Token: rawToken})
+}
+
+// RevokeToken godoc
+// @Summary Revoga um token de acesso pessoal
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do token"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /me/tokens/{id} [delete]
+func (h *Handler) RevokeToken(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID inválido")
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), userID, id); err != nil {
+		if errors.Is(err, personaltoken.ErrTokenNotFound) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondDeleted(c)
+}
+
+func requireUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
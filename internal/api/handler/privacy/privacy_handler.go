@@ -0,0 +1,84 @@
+package privacy
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/privacy"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service privacy.IService
+}
+
+func NewPrivacyHandler(s privacy.IService) *Handler {
+	return &Handler{service: s}
+}
+
+// ExportMine godoc
+// @Summary Exporta os dados pessoais do usuário logado
+// @Description Arquivo JSON com perfil, papéis, histórico de status, histórico de login e eventos de auditoria do usuário.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} privacy.Export
+// @Failure 401 {object} response.Standard
+// @Router /me/export [get]
+func (h *Handler) ExportMine(c *gin.Context) {
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return
+	}
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return
+	}
+
+	export, err := h.service.Export(c.Request.Context(), userID)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename=personal-data.json")
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, export)
+}
+
+// Purge godoc
+// @Summary Anonimiza um usuário imediatamente (Admin-only)
+// @Description Apaga os dados pessoais do usuário sem período de carência: perfil, sessões, históricos e IP/user agent/e-mail dos eventos de auditoria.
+// @Description Irreversível.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/users/{id} [delete]
+func (h *Handler) Purge(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de usuário inválido na URL")
+		return
+	}
+
+	if err := h.service.Purge(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Dados pessoais do usuário anonimizados."})
+}
@@ -0,0 +1,68 @@
+package profile
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+)
+
+type ProfileResponse struct {
+	base.ModelDTO
+	Name              string                 `json:"name"`
+	Email             string                 `json:"email"`
+	Role              string                 `json:"role"`
+	Status            string                 `json:"status"`
+	Locale            *string                `json:"locale"`
+	Timezone          *string                `json:"timezone"`
+	Metadata          map[string]interface{} `json:"metadata"`
+	LastLoginAt       *time.Time             `json:"last_login_at"`
+	PasswordChangedAt *time.Time             `json:"password_changed_at,omitempty"`
+	EmailOTPEnabled   bool                   `json:"email_otp_enabled"`
+}
+
+type UpdateProfileRequest struct {
+	Name     *string                `json:"name" binding:"omitempty,min=3,max=100"`
+	Locale   *string                `json:"locale" binding:"omitempty,max=35" example:"pt-BR"`
+	Timezone *string                `json:"timezone" binding:"omitempty,max=64" example:"America/Sao_Paulo"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+func ToProfileResponse(u *user.User) ProfileResponse {
+	metadata := map[string]interface{}(u.Metadata)
+	if metadata == nil {
+		metadata = map[string]interface{}{}
+	}
+
+	return ProfileResponse{
+		ModelDTO:          base.ToDTO(u.Model),
+		Name:              u.Name,
+		Email:             u.Email,
+		Role:              string(u.Role),
+		Status:            string(u.Status),
+		Locale:            u.Locale,
+		Timezone:          u.Timezone,
+		Metadata:          metadata,
+		LastLoginAt:       u.LastLoginAt,
+		PasswordChangedAt: u.PasswordChangedAt,
+		EmailOTPEnabled:   u.EmailOTPEnabled,
+	}
+}
+
+func (r UpdateProfileRequest) toDomain() user.ProfileUpdate {
+	return user.ProfileUpdate{
+		Name:     r.Name,
+		Locale:   r.Locale,
+		Timezone: r.Timezone,
+		Metadata: r.Metadata,
+	}
+}
+
+type EmailChangeRequest struct {
+	NewEmail        string `json:"new_email" binding:"required,email"`
+	CurrentPassword string `json:"current_password" binding:"required"`
+}
+
+type EmailTokenRequest struct {
+	Token string `json:"token" binding:"required"`
+}
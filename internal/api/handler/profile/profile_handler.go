@@ -0,0 +1,241 @@
+package profile
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/felipedenardo/chameleon-common/pkg/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service user.IProfileService
+}
+
+func NewProfileHandler(s user.IProfileService) *Handler {
+	return &Handler{service: s}
+}
+
+// GetMe godoc
+// @Summary Retorna o perfil do usuário logado
+// @Description O header ETag identifica a revisão atual do perfil, usada no If-Match do PATCH /me.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=ProfileResponse}
+// @Failure 401 {object} response.Standard
+// @Router /me [get]
+func (h *Handler) GetMe(c *gin.Context) {
+	userID, ok := requireUserUUID(c)
+	if !ok {
+		return
+	}
+
+	u, err := h.service.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	c.Header("ETag", formatETag(u.Revision()))
+	httphelpers.RespondOK(c, ToProfileResponse(u))
+}
+
+// UpdateMe godoc
+// @Summary Atualiza o perfil do usuário logado
+// @Description Atualização parcial de nome, locale, timezone e metadados. Chaves de metadata com valor null são removidas.
+// @Description Quando enviado, o If-Match deve conter o ETag atual; caso contrário retorna 412.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param If-Match header string false "ETag obtido em GET /me"
+// @Param request body UpdateProfileRequest true "Campos a atualizar"
+// @Success 200 {object} response.Standard{data=ProfileResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 412 {object} response.Standard
+// @Router /me [patch]
+func (h *Handler) UpdateMe(c *gin.Context) {
+	var req UpdateProfileRequest
+
+	userID, ok := requireUserUUID(c)
+	if !ok {
+		return
+	}
+
+	var expectedRevision *time.Time
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" && ifMatch != "*" {
+		revision, err := parseETag(ifMatch)
+		if err != nil {
+			httphelpers.RespondParamError(c, "If-Match", "ETag inválido")
+			return
+		}
+		expectedRevision = &revision
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	u, err := h.service.UpdateProfile(c.Request.Context(), userID, req.toDomain(), expectedRevision)
+	if err != nil {
+		switch {
+		case errors.Is(err, auth.ErrProfileModified):
+			c.JSON(http.StatusPreconditionFailed, response.NewErrorCustom("O perfil foi alterado por outra requisição."))
+		case errors.Is(err, auth.ErrInvalidLocale):
+			httphelpers.RespondParamError(c, "locale", "Locale inválido")
+		case errors.Is(err, auth.ErrInvalidTimezone):
+			httphelpers.RespondParamError(c, "timezone", "Timezone inválido")
+		case errors.Is(err, auth.ErrMetadataTooLarge):
+			httphelpers.RespondParamError(c, "metadata", "Metadados excedem o tamanho permitido")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	c.Header("ETag", formatETag(u.Revision()))
+	httphelpers.RespondOK(c, ToProfileResponse(u))
+}
+
+// RequestEmailChange godoc
+// @Summary Solicita a troca do e-mail do usuário logado
+// @Description Exige a senha atual. Contas de organizações com diretório LDAP não podem trocar o e-mail, que vem do diretório. Envia um token de confirmação ao novo endereço e um aviso com link para desfazer ao endereço atual.
+// @Tags Profile
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body EmailChangeRequest true "Novo e-mail e senha atual"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Param X-Step-Up-Token header string false "Exigido de usuários com código por e-mail ativo (/me/step-up)"
+// @Router /me/email [post]
+func (h *Handler) RequestEmailChange(c *gin.Context) {
+	var req EmailChangeRequest
+
+	userID, ok := requireUserUUID(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	err := h.service.RequestEmailChange(c.Request.Context(), userID, req.CurrentPassword, req.NewEmail)
+	if err != nil {
+		if errors.Is(err, auth.ErrEmailManagedByDirectory) {
+			httphelpers.RespondDomainFail(c, "O e-mail desta conta é gerenciado pelo diretório da organização.")
+			return
+		}
+		if errors.Is(err, auth.ErrInvalidCurrentPassword) ||
+			errors.Is(err, auth.ErrSameEmail) ||
+			errors.Is(err, auth.ErrEmailAlreadyExists) {
+			httphelpers.RespondDomainFail(c, "Não foi possível solicitar a troca de e-mail.")
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Enviamos um link de confirmação para o novo e-mail."})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirma a troca de e-mail
+// @Description Recebe o token enviado ao novo endereço, efetiva a troca e encerra todas as sessões.
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param request body EmailTokenRequest true "Token de confirmação"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Router /me/email/confirm [post]
+func (h *Handler) ConfirmEmailChange(c *gin.Context) {
+	var req EmailTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	if err := h.service.ConfirmEmailChange(c.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, auth.ErrInvalidEmailChange) || errors.Is(err, auth.ErrEmailAlreadyExists) {
+			httphelpers.RespondDomainFail(c, "Não foi possível confirmar a troca de e-mail.")
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "E-mail alterado com sucesso."})
+}
+
+// RevertEmailChange godoc
+// @Summary Desfaz uma troca de e-mail
+// @Description Recebe o token enviado ao endereço antigo, cancela ou desfaz a troca, encerra as sessões e exige nova senha.
+// @Tags Profile
+// @Accept json
+// @Produce json
+// @Param request body EmailTokenRequest true "Token de reversão"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Router /me/email/revert [post]
+func (h *Handler) RevertEmailChange(c *gin.Context) {
+	var req EmailTokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	if err := h.service.RevertEmailChange(c.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, auth.ErrInvalidEmailChange) || errors.Is(err, auth.ErrEmailAlreadyExists) {
+			httphelpers.RespondDomainFail(c, "Não foi possível desfazer a troca de e-mail.")
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, gin.H{"message": "Troca de e-mail desfeita. Redefina sua senha para acessar a conta."})
+}
+
+func requireUserUUID(c *gin.Context) (uuid.UUID, bool) {
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
+
+func formatETag(revision time.Time) string {
+	return `"` + strconv.FormatInt(revision.UnixMicro(), 10) + `"`
+}
+
+func parseETag(value string) (time.Time, error) {
+	value = strings.TrimPrefix(strings.TrimSpace(value), "W/")
+	micros, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMicro(micros).UTC(), nil
+}
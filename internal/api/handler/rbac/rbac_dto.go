@@ -0,0 +1,67 @@
+package rbac
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/google/uuid"
+)
+
+type CreateRoleRequest struct {
+	Name        string   `json:"name" binding:"required,min=3,max=50" example:"support"`
+	Description string   `json:"description" binding:"max=255"`
+	Permissions []string `json:"permissions" binding:"omitempty,dive,max=100" example:"users:read"`
+}
+
+type UpdateRoleRequest struct {
+	Name        *string  `json:"name" binding:"omitempty,min=3,max=50"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	Permissions []string `json:"permissions" binding:"omitempty,dive,max=100"`
+}
+
+type SetUserRolesRequest struct {
+	Roles []string `json:"roles" binding:"required,min=1,dive,required,max=50" example:"user"`
+}
+
+type RoleResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Name        string     `json:"name"`
+	Description string     `json:"description"`
+	IsSystem    bool       `json:"is_system"`
+	Permissions []string   `json:"permissions"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   *time.Time `json:"updated_at"`
+}
+
+type PermissionResponse struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+func ToRoleResponse(r *rbac.Role) RoleResponse {
+	return RoleResponse{
+		ID:          r.ID,
+		Name:        r.Name,
+		Description: r.Description,
+		IsSystem:    r.IsSystem,
+		Permissions: r.PermissionNames(),
+		CreatedAt:   r.CreatedAt,
+		UpdatedAt:   r.UpdatedAt,
+	}
+}
+
+func ToRoleResponses(roles []rbac.Role) []RoleResponse {
+	responses := make([]RoleResponse, 0, len(roles))
+	for i := range roles {
+		responses = append(responses, ToRoleResponse(&roles[i]))
+	}
+	return responses
+}
+
+func ToPermissionResponses(permissions []rbac.Permission) []PermissionResponse {
+	responses := make([]PermissionResponse, 0, len(permissions))
+	for _, p := range permissions {
+		responses = append(responses, PermissionResponse{Name: p.Name, Description: p.Description})
+	}
+	return responses
+}
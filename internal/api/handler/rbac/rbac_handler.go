@@ -0,0 +1,252 @@
+package rbac
+
+import (
+	"errors"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type Handler struct {
+	service rbac.IService
+}
+
+func NewRBACHandler(s rbac.IService) *Handler {
+	return &Handler{service: s}
+}
+
+// ListRoles godoc
+// @Summary Lista os papéis e suas permissões
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]RoleResponse}
+// @Failure 403 {object} response.Standard
+// @Router /admin/roles [get]
+func (h *Handler) ListRoles(c *gin.Context) {
+	roles, err := h.service.ListRoles(c.Request.Context())
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToRoleResponses(roles))
+}
+
+// GetRole godoc
+// @Summary Detalhes de um papel
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do papel"
+// @Success 200 {object} response.Standard{data=RoleResponse}
+// @Failure 404 {object} response.Standard
+// @Router /admin/roles/{id} [get]
+func (h *Handler) GetRole(c *gin.Context) {
+	roleID, ok := parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	role, err := h.service.GetRole(c.Request.Context(), roleID)
+	if err != nil {
+		respondRBACError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToRoleResponse(role))
+}
+
+// CreateRole godoc
+// @Summary Cria um papel
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateRoleRequest true "Nome, descrição e permissões"
+// @Success 201 {object} response.Standard{data=RoleResponse}
+// @Failure 400 {object} response.Standard
+// @Router /admin/roles [post]
+func (h *Handler) CreateRole(c *gin.Context) {
+	var req CreateRoleRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	role, err := h.service.CreateRole(c.Request.Context(), req.Name, req.Description, req.Permissions)
+	if err != nil {
+		respondRBACError(c, err)
+		return
+	}
+
+	httphelpers.RespondCreated(c, ToRoleResponse(role))
+}
+
+// UpdateRole godoc
+// @Summary Atualiza um papel
+// @Description Papéis de sistema (admin, user) não podem ser alterados. Quando enviadas, as permissões substituem as atuais.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do papel"
+// @Param request body UpdateRoleRequest true "Campos a atualizar"
+// @Success 200 {object} response.Standard{data=RoleResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/roles/{id} [put]
+func (h *Handler) UpdateRole(c *gin.Context) {
+	var req UpdateRoleRequest
+
+	roleID, ok := parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	role, err := h.service.UpdateRole(c.Request.Context(), roleID, req.Name, req.Description, req.Permissions)
+	if err != nil {
+		respondRBACError(c, err)
+		return
+	}
+
+	httphelpers.RespondUpdated(c, ToRoleResponse(role))
+}
+
+// DeleteRole godoc
+// @Summary Remove um papel
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do papel"
+// @Success 200 {object} response.Standard
+// @Failure 400 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/roles/{id} [delete]
+func (h *Handler) DeleteRole(c *gin.Context) {
+	roleID, ok := parseRoleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteRole(c.Request.Context(), roleID); err != nil {
+		respondRBACError(c, err)
+		return
+	}
+
+	httphelpers.RespondDeleted(c)
+}
+
+// ListPermissions godoc
+// @Summary Lista as permissões disponíveis
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]PermissionResponse}
+// @Router /admin/permissions [get]
+func (h *Handler) ListPermissions(c *gin.Context) {
+	permissions, err := h.service.ListPermissions(c.Request.Context())
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToPermissionResponses(permissions))
+}
+
+// GetUserRoles godoc
+// @Summary Lista os papéis de um usuário
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} response.Standard{data=[]RoleResponse}
+// @Router /admin/users/{id}/roles [get]
+func (h *Handler) GetUserRoles(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de usuário inválido na URL")
+		return
+	}
+
+	roles, err := h.service.GetUserRoles(c.Request.Context(), userID)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToRoleResponses(roles))
+}
+
+// SetUserRoles godoc
+// @Summary Define os papéis de um usuário
+// @Description Substitui os papéis atuais. As novas permissões valem a partir do próximo token emitido.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param request body SetUserRolesRequest true "Nomes dos papéis"
+// @Success 200 {object} response.Standard{data=[]RoleResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/users/{id}/roles [put]
+func (h *Handler) SetUserRoles(c *gin.Context) {
+	var req SetUserRolesRequest
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de usuário inválido na URL")
+		return
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	roles, err := h.service.SetUserRoles(c.Request.Context(), userID, req.Roles)
+	if err != nil {
+		if errors.Is(err, auth.ErrUserNotFound) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		respondRBACError(c, err)
+		return
+	}
+
+	httphelpers.RespondUpdated(c, ToRoleResponses(roles))
+}
+
+func parseRoleID(c *gin.Context) (uuid.UUID, bool) {
+	roleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID de papel inválido na URL")
+		return uuid.Nil, false
+	}
+	return roleID, true
+}
+
+func respondRBACError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, rbac.ErrRoleNotFound):
+		httphelpers.RespondNotFound(c)
+	case errors.Is(err, rbac.ErrRoleNameTaken):
+		httphelpers.RespondDomainFail(c, "Já existe um papel com este nome.")
+	case errors.Is(err, rbac.ErrSystemRole):
+		httphelpers.RespondDomainFail(c, "Papéis de sistema não podem ser alterados.")
+	case errors.Is(err, rbac.ErrUnknownPermission):
+		httphelpers.RespondDomainFail(c, "Permissão desconhecida.")
+	default:
+		httphelpers.RespondInternalError(c, err)
+	}
+}
@@ -0,0 +1,24 @@
+package saml
+
+import "github.com/felipedenardo/chameleon-auth-api/internal/domain/saml"
+
+type ProviderResponse struct {
+	Name        string `json:"name" example:"okta"`
+	DisplayName string `json:"display_name" example:"Okta"`
+}
+
+type AuthorizationResponse struct {
+	RedirectURL string `json:"redirect_url"`
+}
+
+type TokenRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+func ToProviderResponses(providers []saml.ProviderInfo) []ProviderResponse {
+	responses := make([]ProviderResponse, 0, len(providers))
+	for _, p := range providers {
+		responses = append(responses, ProviderResponse{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	return responses
+}
@@ -0,0 +1,171 @@
+package saml
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"net/url"
+
+	authhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/saml"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+// relayStateCookie binds a SAML login to the browser that started it. The
+// provider posts the response cross-site, where the cookie is not sent, so it
+// is checked when the frontend exchanges the login code.
+const relayStateCookie = "saml_relay_state"
+
+type Handler struct {
+	service saml.IService
+	cfg     *config.Config
+}
+
+func NewSAMLHandler(s saml.IService, cfg *config.Config) *Handler {
+	return &Handler{service: s, cfg: cfg}
+}
+
+// ListProviders godoc
+// @Summary Lista os provedores SAML
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]ProviderResponse}
+// @Router /auth/saml/providers [get]
+func (h *Handler) ListProviders(c *gin.Context) {
+	httphelpers.RespondOK(c, ToProviderResponses(h.service.Providers()))
+}
+
+// Metadata godoc
+// @Summary Metadados do provedor de serviço SAML
+// @Description Documento a cadastrar nos provedores de identidade, com o entity ID (SAML_SP_ENTITY_ID) e o assertion consumer service (SAML_ACS_URL).
+// @Tags Auth
+// @Produce xml
+// @Success 200 {string} string
+// @Router /auth/saml/metadata [get]
+func (h *Handler) Metadata(c *gin.Context) {
+	metadata, err := h.service.Metadata()
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "application/samlmetadata+xml", metadata)
+}
+
+// Authorize godoc
+// @Summary Inicia o login com um provedor SAML
+// @Description Devolve a URL do provedor, com o AuthnRequest, para onde o navegador deve ser enviado e grava o cookie saml_relay_state. O provedor envia a resposta para o assertion consumer service.
+// @Tags Auth
+// @Produce json
+// @Param provider path string true "Nome do provedor"
+// @Success 200 {object} response.Standard{data=AuthorizationResponse}
+// @Failure 404 {object} response.Standard
+// @Router /auth/saml/{provider}/authorize [post]
+func (h *Handler) Authorize(c *gin.Context) {
+	redirectURL, relayState, err := h.service.StartLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		if errors.Is(err, saml.ErrProviderNotFound) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(relayStateCookie, relayState, h.cfg.SAMLRequestTTLMin*60, "/", "", c.Request.TLS != nil, true)
+	httphelpers.RespondOK(c, AuthorizationResponse{RedirectURL: redirectURL})
+}
+
+// ConsumeAssertion godoc
+// @Summary Assertion consumer service
+// @Description Recebe a resposta assinada do provedor (binding HTTP-POST) e redireciona o navegador para SAML_LOGIN_REDIRECT_URL com um código de uso único em code, ou com o motivo da falha em error.
+// @Tags Auth
+// @Accept x-www-form-urlencoded
+// @Param SAMLResponse formData string true "Resposta do provedor em base64"
+// @Param RelayState formData string true "RelayState enviado com o AuthnRequest"
+// @Success 303
+// @Router /auth/saml/acs [post]
+func (h *Handler) ConsumeAssertion(c *gin.Context) {
+	code, err := h.service.ConsumeResponse(c.Request.Context(), c.PostForm("SAMLResponse"), c.PostForm("RelayState"))
+	if err != nil {
+		var reason string
+		switch {
+		case errors.Is(err, saml.ErrInvalidRelayState):
+			reason = "invalid_request"
+		case errors.Is(err, saml.ErrInvalidResponse), errors.Is(err, saml.ErrAssertionReplayed):
+			reason = "invalid_response"
+		case errors.Is(err, saml.ErrMissingAttributes):
+			reason = "missing_attributes"
+		case errors.Is(err, saml.ErrDomainNotAllowed):
+			reason = "domain_not_allowed"
+		case errors.Is(err, saml.ErrAccountNotFound):
+			reason = "account_not_found"
+		case errors.Is(err, saml.ErrAccountLinkRequired):
+			reason = "account_link_required"
+		default:
+			log.Printf("[ERROR] Failed to consume SAML response: %v", err)
+			reason = "server_error"
+		}
+		h.redirectToFrontend(c, "error", reason)
+		return
+	}
+
+	h.redirectToFrontend(c, "code", code)
+}
+
+// Token godoc
+// @Summary Conclui o login com um provedor SAML
+// @Description Troca o código entregue pelo assertion consumer service pelos tokens da API. Exige o cookie saml_relay_state do navegador que iniciou o login.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body TokenRequest true "Código recebido em SAML_LOGIN_REDIRECT_URL"
+// @Success 200 {object} response.Standard{data=authhandler.LoginResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard{data=authhandler.AccountStatusResponse}
+// @Router /auth/saml/token [post]
+func (h *Handler) Token(c *gin.Context) {
+	var req TokenRequest
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	relayState, _ := c.Cookie(relayStateCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(relayStateCookie, "", -1, "/", "", c.Request.TLS != nil, true)
+
+	result, err := h.service.CompleteLogin(c.Request.Context(), req.Code, relayState)
+	if err != nil {
+		var statusErr *auth.AccountStatusError
+		switch {
+		case errors.As(err, &statusErr):
+			authhandler.RespondAccountStatus(c, statusErr)
+		case errors.Is(err, saml.ErrInvalidLoginCode), errors.Is(err, auth.ErrAccountLocked):
+			httphelpers.RespondUnauthorized(c, "Sessão de login inválida ou expirada. Tente novamente.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondOK(c, authhandler.ToLoginResponse(result))
+}
+
+func (h *Handler) redirectToFrontend(c *gin.Context, key string, value string) {
+	redirectURL, err := url.Parse(h.cfg.SAMLLoginRedirectURL)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+	query := redirectURL.Query()
+	query.Set(key, value)
+	redirectURL.RawQuery = query.Encode()
+	c.Redirect(http.StatusSeeOther, redirectURL.String())
+}
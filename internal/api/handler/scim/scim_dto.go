@@ -0,0 +1,317 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/scim"
+	"github.com/google/uuid"
+)
+
+// Resources of the SCIM protocol (RFC 7643). Attribute names follow the
+// protocol, in camelCase.
+
+type NameValue struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type EmailValue struct {
+	Value   string `json:"value"`
+	Type    string `json:"type,omitempty" example:"work"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type Reference struct {
+	Value   string `json:"value"`
+	Ref     string `json:"$ref,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+type Meta struct {
+	ResourceType string    `json:"resourceType" example:"User"`
+	Created      time.Time `json:"created"`
+	LastModified time.Time `json:"lastModified"`
+	Location     string    `json:"location"`
+	Version      string    `json:"version"`
+}
+
+type UserRequest struct {
+	Schemas     []string     `json:"schemas"`
+	ExternalID  string       `json:"externalId"`
+	UserName    string       `json:"userName" example:"ana@example.com"`
+	Name        NameValue    `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Emails      []EmailValue `json:"emails"`
+	// Active defaults to true.
+	Active *bool `json:"active"`
+}
+
+type UserResponse struct {
+	Schemas     []string     `json:"schemas"`
+	ID          uuid.UUID    `json:"id"`
+	ExternalID  *string      `json:"externalId,omitempty"`
+	UserName    string       `json:"userName"`
+	Name        NameValue    `json:"name"`
+	DisplayName string       `json:"displayName"`
+	Emails      []EmailValue `json:"emails"`
+	Active      bool         `json:"active"`
+	Groups      []Reference  `json:"groups"`
+	Meta        Meta         `json:"meta"`
+}
+
+type GroupRequest struct {
+	Schemas     []string    `json:"schemas"`
+	ExternalID  string      `json:"externalId"`
+	DisplayName string      `json:"displayName" example:"Engenharia"`
+	Members     []Reference `json:"members"`
+}
+
+type GroupResponse struct {
+	Schemas     []string    `json:"schemas"`
+	ID          uuid.UUID   `json:"id"`
+	ExternalID  *string     `json:"externalId,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []Reference `json:"members,omitempty"`
+	Meta        Meta        `json:"meta"`
+}
+
+type PatchOperation struct {
+	Op    string          `json:"op" example:"replace"`
+	Path  string          `json:"path" example:"active"`
+	Value json.RawMessage `json:"value" swaggertype:"object"`
+}
+
+type PatchRequest struct {
+	Schemas    []string         `json:"schemas"`
+	Operations []PatchOperation `json:"Operations" binding:"required,min=1"`
+}
+
+type ListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int64       `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type ErrorResponse struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status" example:"409"`
+	SCIMType string   `json:"scimType,omitempty" example:"uniqueness"`
+	Detail   string   `json:"detail"`
+}
+
+type ListQuery struct {
+	Filter             string `form:"filter"`
+	StartIndex         int    `form:"startIndex"`
+	Count              *int   `form:"count"`
+	ExcludedAttributes string `form:"excludedAttributes"`
+}
+
+// Admin endpoints, in the format of the rest of the API.
+
+type CreateTokenRequest struct {
+	Name string `json:"name" binding:"required,min=3,max=100" example:"Okta"`
+}
+
+type TokenResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix" example:"scim_Xk2hQ9a"`
+	CreatedBy  *uuid.UUID `json:"created_by,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
+}
+
+type CreateTokenResponse struct {
+	TokenResponse
+	// Token is only returned here; store it in the identity provider.
+	Token string `json:"token"`
+}
+
+type ListGroupsQuery struct {
+	Page    int `form:"page" binding:"omitempty,min=1"`
+	PerPage int `form:"per_page" binding:"omitempty,min=1,max=200"`
+}
+
+type AdminGroupResponse struct {
+	ID           uuid.UUID `json:"id"`
+	DisplayName  string    `json:"display_name"`
+	ExternalID   *string   `json:"external_id,omitempty"`
+	MembersCount int       `json:"members_count"`
+	Roles        []string  `json:"roles"`
+}
+
+type SetGroupRolesRequest struct {
+	Roles []string `json:"roles" binding:"required,dive,required"`
+}
+
+func (r UserRequest) toAttributes() scim.UserAttributes {
+	name := strings.TrimSpace(r.DisplayName)
+	if name == "" {
+		name = strings.TrimSpace(r.Name.Formatted)
+	}
+	if name == "" {
+		name = scim.JoinName(r.Name.GivenName, r.Name.FamilyName)
+	}
+
+	attrs := scim.UserAttributes{
+		UserName:   r.UserName,
+		ExternalID: optional(r.ExternalID),
+		Name:       name,
+		Active:     r.Active == nil || *r.Active,
+	}
+	if strings.TrimSpace(attrs.UserName) == "" {
+		attrs.UserName = r.primaryEmail()
+	}
+	return attrs
+}
+
+func (r UserRequest) primaryEmail() string {
+	for _, email := range r.Emails {
+		if email.Primary {
+			return email.Value
+		}
+	}
+	if len(r.Emails) > 0 {
+		return r.Emails[0].Value
+	}
+	return ""
+}
+
+func (r GroupRequest) toAttributes() (scim.GroupAttributes, error) {
+	attrs := scim.GroupAttributes{
+		DisplayName: r.DisplayName,
+		ExternalID:  optional(r.ExternalID),
+	}
+	for _, m := range r.Members {
+		id, err := uuid.Parse(m.Value)
+		if err != nil {
+			return attrs, fmt.Errorf("%w: member %s", scim.ErrInvalidValue, m.Value)
+		}
+		attrs.Members = append(attrs.Members, id)
+	}
+	return attrs, nil
+}
+
+func (r PatchRequest) toOperations() []scim.PatchOperation {
+	ops := make([]scim.PatchOperation, 0, len(r.Operations))
+	for _, op := range r.Operations {
+		ops = append(ops, scim.PatchOperation{Op: op.Op, Path: op.Path, Value: op.Value})
+	}
+	return ops
+}
+
+func ToUserResponse(u *scim.User, baseURL string) UserResponse {
+	groups := make([]Reference, 0, len(u.Groups))
+	for _, g := range u.Groups {
+		groups = append(groups, Reference{Value: g.ID.String(), Ref: baseURL + "/Groups/" + g.ID.String(), Display: g.Display})
+	}
+
+	return UserResponse{
+		Schemas:     []string{scim.UserSchema},
+		ID:          u.ID,
+		ExternalID:  u.ExternalID,
+		UserName:    u.Email,
+		Name:        NameValue{Formatted: u.Name},
+		DisplayName: u.Name,
+		Emails:      []EmailValue{{Value: u.Email, Type: "work", Primary: true}},
+		Active:      u.Active(),
+		Groups:      groups,
+		Meta:        newMeta("User", u.CreatedAt, u.Revision(), baseURL+"/Users/"+u.ID.String()),
+	}
+}
+
+func ToUserResponses(users []scim.User, baseURL string) []UserResponse {
+	responses := make([]UserResponse, 0, len(users))
+	for i := range users {
+		responses = append(responses, ToUserResponse(&users[i], baseURL))
+	}
+	return responses
+}
+
+// ToGroupResponse leaves the members out when withMembers is false, as asked
+// by excludedAttributes=members.
+func ToGroupResponse(g *scim.Group, baseURL string, withMembers bool) GroupResponse {
+	response := GroupResponse{
+		Schemas:     []string{scim.GroupSchema},
+		ID:          g.ID,
+		ExternalID:  g.ExternalID,
+		DisplayName: g.DisplayName,
+		Meta:        newMeta("Group", g.CreatedAt, g.Revision(), baseURL+"/Groups/"+g.ID.String()),
+	}
+	if withMembers {
+		response.Members = make([]Reference, 0, len(g.Members))
+		for _, m := range g.Members {
+			response.Members = append(response.Members, Reference{Value: m.ID.String(), Ref: baseURL + "/Users/" + m.ID.String(), Display: m.Display})
+		}
+	}
+	return response
+}
+
+func ToGroupResponses(groups []scim.Group, baseURL string, withMembers bool) []GroupResponse {
+	responses := make([]GroupResponse, 0, len(groups))
+	for i := range groups {
+		responses = append(responses, ToGroupResponse(&groups[i], baseURL, withMembers))
+	}
+	return responses
+}
+
+func ToTokenResponse(t *scim.Token) TokenResponse {
+	return TokenResponse{
+		ID:         t.ID,
+		Name:       t.Name,
+		Prefix:     t.Prefix,
+		CreatedBy:  t.CreatedBy,
+		CreatedAt:  t.CreatedAt,
+		LastUsedAt: t.LastUsedAt,
+	}
+}
+
+func ToTokenResponses(tokens []scim.Token) []TokenResponse {
+	responses := make([]TokenResponse, 0, len(tokens))
+	for i := range tokens {
+		responses = append(responses, ToTokenResponse(&tokens[i]))
+	}
+	return responses
+}
+
+func ToAdminGroupResponse(g *scim.Group) AdminGroupResponse {
+	return AdminGroupResponse{
+		ID:           g.ID,
+		DisplayName:  g.DisplayName,
+		ExternalID:   g.ExternalID,
+		MembersCount: len(g.Members),
+		Roles:        g.Roles,
+	}
+}
+
+func ToAdminGroupResponses(groups []scim.Group) []AdminGroupResponse {
+	responses := make([]AdminGroupResponse, 0, len(groups))
+	for i := range groups {
+		responses = append(responses, ToAdminGroupResponse(&groups[i]))
+	}
+	return responses
+}
+
+func newMeta(resourceType string, created time.Time, lastModified time.Time, location string) Meta {
+	return Meta{
+		ResourceType: resourceType,
+		Created:      created.UTC(),
+		LastModified: lastModified.UTC(),
+		Location:     location,
+		Version:      fmt.Sprintf(`W/"%d"`, lastModified.UnixMicro()),
+	}
+}
+
+func optional(s string) *string {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
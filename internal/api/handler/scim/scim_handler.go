@@ -0,0 +1,697 @@
+package scim
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/scim"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// basePath is where the SCIM routes are mounted, used to build the location
+// of the resources.
+const basePath = "/scim/v2"
+
+type Handler struct {
+	service scim.IService
+}
+
+func NewSCIMHandler(s scim.IService) *Handler {
+	return &Handler{service: s}
+}
+
+// ServiceProviderConfig godoc
+// @Summary Descreve os recursos SCIM suportados
+// @Description Filtros suportam apenas eq em um atributo; PATCH é suportado; bulk, ordenação, ETag e troca de senha não.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} object
+// @Failure 401 {object} ErrorResponse
+// @Router /scim/v2/ServiceProviderConfig [get]
+func (h *Handler) ServiceProviderConfig(c *gin.Context) {
+	respond(c, http.StatusOK, gin.H{
+		"schemas":        []string{scim.ServiceConfigSchema},
+		"patch":          gin.H{"supported": true},
+		"bulk":           gin.H{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+		"filter":         gin.H{"supported": true, "maxResults": scim.MaxPageSize},
+		"changePassword": gin.H{"supported": false},
+		"sort":           gin.H{"supported": false},
+		"etag":           gin.H{"supported": false},
+		"authenticationSchemes": []gin.H{{
+			"type":        "oauthbearertoken",
+			"name":        "Bearer token",
+			"description": "Token SCIM criado em /admin/scim/tokens",
+			"primary":     true,
+		}},
+	})
+}
+
+// ListUsers godoc
+// @Summary Lista os usuários da organização (SCIM)
+// @Description Paginação por startIndex (a partir de 1) e count (máx. 200). O filtro aceita apenas eq em userName, emails.value, externalId ou id, ex.: userName eq "ana@example.com". Usuários com exclusão agendada não são listados.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Produce json
+// @Param filter query string false "Filtro SCIM"
+// @Param startIndex query int false "Índice do primeiro resultado (a partir de 1)"
+// @Param count query int false "Itens por página (máx. 200)"
+// @Success 200 {object} ListResponse{Resources=[]UserResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /scim/v2/Users [get]
+func (h *Handler) ListUsers(c *gin.Context) {
+	q, ok := bindListQuery(c, "userName", "emails.value", "externalId", "id")
+	if !ok {
+		return
+	}
+
+	users, total, err := h.service.ListUsers(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondList(c, q, total, len(users), ToUserResponses(users, baseURL(c)))
+}
+
+// GetUser godoc
+// @Summary Obtém um usuário (SCIM)
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} UserResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Users/{id} [get]
+func (h *Handler) GetUser(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+
+	u, err := h.service.GetUser(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, ToUserResponse(u, baseURL(c)))
+}
+
+// CreateUser godoc
+// @Summary Provisiona um usuário (SCIM)
+// @Description userName deve ser o e-mail de login. A conta é criada sem senha: o usuário entra pelo provedor de identidade ou define uma senha pela recuperação de senha. Com active=false a conta é criada desativada. Recriar um usuário excluído pelo provedor dentro do prazo de exclusão o restaura.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body UserRequest true "Usuário"
+// @Success 201 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Users [post]
+func (h *Handler) CreateUser(c *gin.Context) {
+	var req UserRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+
+	u, err := h.service.CreateUser(c.Request.Context(), req.toAttributes())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := ToUserResponse(u, baseURL(c))
+	c.Header("Location", response.Meta.Location)
+	respond(c, http.StatusCreated, response)
+}
+
+// ReplaceUser godoc
+// @Summary Substitui os atributos de um usuário (SCIM)
+// @Description Atributos ausentes, como externalId, são removidos. active=false desativa a conta e revoga todas as sessões; active=true só reativa contas desativadas pelo provedor.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param request body UserRequest true "Usuário"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Users/{id} [put]
+func (h *Handler) ReplaceUser(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+	var req UserRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+
+	u, err := h.service.ReplaceUser(c.Request.Context(), id, req.toAttributes())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, ToUserResponse(u, baseURL(c)))
+}
+
+// PatchUser godoc
+// @Summary Altera atributos de um usuário (SCIM)
+// @Description Operações add, replace e remove em active, userName, externalId, displayName e name. Atributos não mantidos pela API, como telefones ou a extensão enterprise, são ignorados. active=false desativa a conta e revoga todas as sessões.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do usuário"
+// @Param request body PatchRequest true "Operações"
+// @Success 200 {object} UserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Users/{id} [patch]
+func (h *Handler) PatchUser(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+	var req PatchRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+
+	u, err := h.service.PatchUser(c.Request.Context(), id, req.toOperations())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, ToUserResponse(u, baseURL(c)))
+}
+
+// DeleteUser godoc
+// @Summary Desprovisiona um usuário (SCIM)
+// @Description Desativa a conta, revoga todas as sessões e agenda a exclusão para depois do prazo de ACCOUNT_DELETION_GRACE_DAYS.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Param id path string true "ID do usuário"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Users/{id} [delete]
+func (h *Handler) DeleteUser(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteUser(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListGroups godoc
+// @Summary Lista os grupos da organização (SCIM)
+// @Description O filtro aceita apenas eq em displayName, externalId ou id. Use excludedAttributes=members para omitir os membros.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Produce json
+// @Param filter query string false "Filtro SCIM"
+// @Param startIndex query int false "Índice do primeiro resultado (a partir de 1)"
+// @Param count query int false "Itens por página (máx. 200)"
+// @Param excludedAttributes query string false "members para omitir os membros"
+// @Success 200 {object} ListResponse{Resources=[]GroupResponse}
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Router /scim/v2/Groups [get]
+func (h *Handler) ListGroups(c *gin.Context) {
+	q, ok := bindListQuery(c, "displayName", "externalId", "id")
+	if !ok {
+		return
+	}
+
+	groups, total, err := h.service.ListGroups(c.Request.Context(), q)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respondList(c, q, total, len(groups), ToGroupResponses(groups, baseURL(c), withMembers(c)))
+}
+
+// GetGroup godoc
+// @Summary Obtém um grupo (SCIM)
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do grupo"
+// @Param excludedAttributes query string false "members para omitir os membros"
+// @Success 200 {object} GroupResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Groups/{id} [get]
+func (h *Handler) GetGroup(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+
+	g, err := h.service.GetGroup(c.Request.Context(), id)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, ToGroupResponse(g, baseURL(c), withMembers(c)))
+}
+
+// CreateGroup godoc
+// @Summary Cria um grupo (SCIM)
+// @Description Os membros recebem as roles mapeadas para o grupo em /admin/scim/groups/{id}/roles.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body GroupRequest true "Grupo"
+// @Success 201 {object} GroupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Groups [post]
+func (h *Handler) CreateGroup(c *gin.Context) {
+	var req GroupRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+	attrs, err := req.toAttributes()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	g, err := h.service.CreateGroup(c.Request.Context(), attrs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := ToGroupResponse(g, baseURL(c), true)
+	c.Header("Location", response.Meta.Location)
+	respond(c, http.StatusCreated, response)
+}
+
+// ReplaceGroup godoc
+// @Summary Substitui um grupo e seus membros (SCIM)
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do grupo"
+// @Param request body GroupRequest true "Grupo"
+// @Success 200 {object} GroupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Groups/{id} [put]
+func (h *Handler) ReplaceGroup(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+	var req GroupRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+	attrs, err := req.toAttributes()
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	g, err := h.service.ReplaceGroup(c.Request.Context(), id, attrs)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, ToGroupResponse(g, baseURL(c), true))
+}
+
+// PatchGroup godoc
+// @Summary Altera um grupo ou seus membros (SCIM)
+// @Description Operações em displayName, externalId e members, incluindo remove com path members[value eq "id"].
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do grupo"
+// @Param request body PatchRequest true "Operações"
+// @Success 200 {object} GroupResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Router /scim/v2/Groups/{id} [patch]
+func (h *Handler) PatchGroup(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+	var req PatchRequest
+	if !bindSCIM(c, &req) {
+		return
+	}
+
+	g, err := h.service.PatchGroup(c.Request.Context(), id, req.toOperations())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	respond(c, http.StatusOK, ToGroupResponse(g, baseURL(c), true))
+}
+
+// DeleteGroup godoc
+// @Summary Exclui um grupo (SCIM)
+// @Description Os ex-membros perdem as roles mapeadas para o grupo.
+// @Tags SCIM
+// @Security ApiKeyAuth
+// @Param id path string true "ID do grupo"
+// @Success 204
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /scim/v2/Groups/{id} [delete]
+func (h *Handler) DeleteGroup(c *gin.Context) {
+	id, ok := resourceID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteGroup(c.Request.Context(), id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ListTokens godoc
+// @Summary Lista os tokens SCIM da organização
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Success 200 {object} response.Standard{data=[]TokenResponse}
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/scim/tokens [get]
+func (h *Handler) ListTokens(c *gin.Context) {
+	tokens, err := h.service.ListTokens(c.Request.Context())
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondOK(c, ToTokenResponses(tokens))
+}
+
+// CreateToken godoc
+// @Summary Cria um token SCIM para o provedor de identidade
+// @Description O token é exibido apenas nesta resposta; só o hash é armazenado. O provedor o envia como Bearer nas rotas /scim/v2, que ficam restritas à organização do token.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateTokenRequest true "Nome da integração"
+// @Success 201 {object} response.Standard{data=CreateTokenResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/scim/tokens [post]
+func (h *Handler) CreateToken(c *gin.Context) {
+	userID, ok := requireUserID(c)
+	if !ok {
+		return
+	}
+	var req CreateTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	token, rawToken, err := h.service.CreateToken(c.Request.Context(), req.Name, userID)
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondCreated(c, CreateTokenResponse{TokenResponse: ToTokenResponse(token), Token: rawToken})
+}
+
+// RevokeToken godoc
+// @Summary Revoga um token SCIM
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path string true "ID do token"
+// @Success 200 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/scim/tokens/{id} [delete]
+func (h *Handler) RevokeToken(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID inválido")
+		return
+	}
+
+	if err := h.service.RevokeToken(c.Request.Context(), id); err != nil {
+		if errors.Is(err, scim.ErrTokenNotFound) {
+			httphelpers.RespondNotFound(c)
+			return
+		}
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondDeleted(c)
+}
+
+// AdminListGroups godoc
+// @Summary Lista os grupos SCIM e as roles mapeadas para eles
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Produce json
+// @Param page query int false "Página (a partir de 1)"
+// @Param per_page query int false "Itens por página (máx. 200)"
+// @Success 200 {object} response.Standard{data=[]AdminGroupResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Router /admin/scim/groups [get]
+func (h *Handler) AdminListGroups(c *gin.Context) {
+	var query ListGroupsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+	if query.Page == 0 {
+		query.Page = 1
+	}
+	if query.PerPage == 0 {
+		query.PerPage = scim.DefaultPageSize
+	}
+
+	groups, total, err := h.service.ListGroups(c.Request.Context(), scim.Query{
+		StartIndex: (query.Page-1)*query.PerPage + 1,
+		Count:      query.PerPage,
+	})
+	if err != nil {
+		httphelpers.RespondInternalError(c, err)
+		return
+	}
+
+	httphelpers.RespondPaged(c, ToAdminGroupResponses(groups), query.Page, query.PerPage, total)
+}
+
+// SetGroupRoles godoc
+// @Summary Define as roles concedidas aos membros de um grupo SCIM
+// @Description Enquanto algum grupo da organização tiver roles, as roles dos membros de grupos passam a ser gerenciadas pelo provedor: cada membro recebe a união das roles de seus grupos, ou a role user se nenhum grupo dele tiver roles. Lista vazia remove o mapeamento do grupo.
+// @Tags Admin
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do grupo"
+// @Param request body SetGroupRolesRequest true "Nomes das roles"
+// @Success 200 {object} response.Standard{data=AdminGroupResponse}
+// @Failure 400 {object} response.Standard
+// @Failure 401 {object} response.Standard
+// @Failure 403 {object} response.Standard
+// @Failure 404 {object} response.Standard
+// @Router /admin/scim/groups/{id}/roles [put]
+func (h *Handler) SetGroupRoles(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		httphelpers.RespondParamError(c, "id", "ID inválido")
+		return
+	}
+	var req SetGroupRolesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		httphelpers.RespondBindingError(c, err)
+		return
+	}
+
+	g, err := h.service.SetGroupRoles(c.Request.Context(), id, req.Roles)
+	if err != nil {
+		switch {
+		case errors.Is(err, scim.ErrGroupNotFound):
+			httphelpers.RespondNotFound(c)
+		case errors.Is(err, scim.ErrUnknownRole):
+			httphelpers.RespondDomainFail(c, "Uma ou mais roles não existem.")
+		default:
+			httphelpers.RespondInternalError(c, err)
+		}
+		return
+	}
+
+	httphelpers.RespondOK(c, ToAdminGroupResponse(g))
+}
+
+// bindListQuery reads filter, startIndex and count; allowed lists the
+// attributes the resource can be filtered by.
+func bindListQuery(c *gin.Context, allowed ...string) (scim.Query, bool) {
+	var query ListQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondSCIMError(c, http.StatusBadRequest, "invalidValue", "Parâmetros de paginação inválidos.")
+		return scim.Query{}, false
+	}
+
+	filter, err := scim.ParseFilter(query.Filter, allowed...)
+	if err != nil {
+		respondError(c, err)
+		return scim.Query{}, false
+	}
+
+	q := scim.Query{Filter: filter, StartIndex: max(query.StartIndex, 1), Count: scim.DefaultPageSize}
+	if query.Count != nil {
+		q.Count = *query.Count
+	}
+	return q, true
+}
+
+func bindSCIM(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		respondSCIMError(c, http.StatusBadRequest, "invalidSyntax", "Corpo da requisição inválido.")
+		return false
+	}
+	return true
+}
+
+// resourceID parses the id path parameter; ids that are not UUIDs cannot
+// exist.
+func resourceID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		respondSCIMError(c, http.StatusNotFound, "", "Recurso não encontrado.")
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func withMembers(c *gin.Context) bool {
+	for _, attribute := range strings.Split(c.Query("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			return false
+		}
+	}
+	return true
+}
+
+// baseURL returns the absolute URL of the SCIM routes of the request.
+func baseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	prefix, _, _ := strings.Cut(c.Request.URL.Path, basePath)
+	return scheme + "://" + c.Request.Host + prefix + basePath
+}
+
+func respondList(c *gin.Context, q scim.Query, total int64, count int, resources interface{}) {
+	respond(c, http.StatusOK, ListResponse{
+		Schemas:      []string{scim.ListResponseSchema},
+		TotalResults: total,
+		StartIndex:   q.StartIndex,
+		ItemsPerPage: count,
+		Resources:    resources,
+	})
+}
+
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, scim.ErrUserNotFound), errors.Is(err, scim.ErrGroupNotFound):
+		respondSCIMError(c, http.StatusNotFound, "", "Recurso não encontrado.")
+	case errors.Is(err, scim.ErrUserNameTaken):
+		respondSCIMError(c, http.StatusConflict, "uniqueness", "Já existe um usuário com este userName.")
+	case errors.Is(err, scim.ErrExternalIDTaken):
+		respondSCIMError(c, http.StatusConflict, "uniqueness", "Já existe um recurso com este externalId.")
+	case errors.Is(err, scim.ErrGroupNameTaken):
+		respondSCIMError(c, http.StatusConflict, "uniqueness", "Já existe um grupo com este displayName.")
+	case errors.Is(err, scim.ErrInvalidFilter):
+		respondSCIMError(c, http.StatusBadRequest, "invalidFilter", "Filtro não suportado: "+err.Error())
+	case errors.Is(err, scim.ErrInvalidPath):
+		respondSCIMError(c, http.StatusBadRequest, "invalidPath", "Caminho inválido: "+err.Error())
+	case errors.Is(err, scim.ErrInvalidValue):
+		respondSCIMError(c, http.StatusBadRequest, "invalidValue", "Valor inválido: "+err.Error())
+	default:
+		log.Printf("[ERROR] SCIM request %s %s failed: %v", c.Request.Method, c.FullPath(), err)
+		respondSCIMError(c, http.StatusInternalServerError, "", "Erro interno.")
+	}
+}
+
+func respondSCIMError(c *gin.Context, status int, scimType string, detail string) {
+	respond(c, status, ErrorResponse{
+		Schemas:  []string{scim.ErrorSchema},
+		Status:   strconv.Itoa(status),
+		SCIMType: scimType,
+		Detail:   detail,
+	})
+}
+
+func respond(c *gin.Context, status int, body interface{}) {
+	c.Header("Content-Type", scim.MediaType)
+	c.JSON(status, body)
+}
+
+func requireUserID(c *gin.Context) (uuid.UUID, bool) {
+	userIDString, exists := middleware.RequireUserID(c)
+	if !exists {
+		return uuid.Nil, false
+	}
+
+	userID, err := uuid.Parse(userIDString)
+	if err != nil {
+		httphelpers.RespondParamError(c, "user_id", "Invalid user id in token")
+		return uuid.Nil, false
+	}
+	return userID, true
+}
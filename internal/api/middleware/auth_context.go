@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAuthContext demands an acr of at least minACR and, when maxAge is
+// positive, an auth_time no older than maxAge. Other tokens get the RFC 9470
+// challenge, which the client answers through /auth/reauthenticate. It must
+// run after the AuthMiddleware.
+func RequireAuthContext(minACR string, maxAge time.Duration) gin.HandlerFunc {
+	challenge := `Bearer error="insufficient_user_authentication"`
+	if minACR != "" {
+		challenge += fmt.Sprintf(`, acr_values="%s"`, minACR)
+	}
+	if maxAge > 0 {
+		challenge += fmt.Sprintf(`, max_age=%d`, int(maxAge.Seconds()))
+	}
+
+	return func(c *gin.Context) {
+		claims, ok := TokenClaims(c)
+		if !ok {
+			httphelpers.RespondUnauthorized(c, "Authentication context missing")
+			c.Abort()
+			return
+		}
+
+		acr, _ := claims["acr"].(string)
+		authTime, _ := claims["auth_time"].(float64)
+		recent := maxAge <= 0 || (authTime > 0 && time.Since(time.Unix(int64(authTime), 0)) <= maxAge)
+
+		if !auth.ACRSatisfies(acr, minACR) || !recent {
+			c.Header("WWW-Authenticate", challenge)
+			httphelpers.RespondUnauthorized(c, "Autenticação recente necessária. Confirme sua identidade em /auth/reauthenticate.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
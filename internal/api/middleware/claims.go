@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	commonmiddleware "github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const claimsKey = "tokenClaims"
+
+// TokenClaims returns the claims of the access token accepted by the common
+// AuthMiddleware. The signature was already verified there, so the token is
+// only decoded here and cached for the rest of the request.
+func TokenClaims(c *gin.Context) (jwt.MapClaims, bool) {
+	if cached, exists := c.Get(claimsKey); exists {
+		claims, ok := cached.(jwt.MapClaims)
+		return claims, ok
+	}
+
+	rawToken, ok := commonmiddleware.GetRawToken(c)
+	if !ok {
+		return nil, false
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(rawToken, claims); err != nil {
+		return nil, false
+	}
+
+	c.Set(claimsKey, claims)
+	return claims, true
+}
@@ -0,0 +1,16 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// When runs guard only for the requests matching match; the others move on
+// to the next handler. guard aborts the request or calls c.Next(), as every
+// middleware here does.
+func When(match func(c *gin.Context) bool, guard gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !match(c) {
+			c.Next()
+			return
+		}
+		guard(c)
+	}
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/dpop"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// DPoPProof verifies the DPoP header sent to the token endpoints and marks
+// the request with the thumbprint of its key, so the tokens issued for it are
+// bound to the key. Requests without the header get bearer tokens. baseURL is
+// the public URL of the API, against which the htu claim is checked.
+func DPoPProof(verifier dpop.IVerifier, baseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		proofs := c.Request.Header.Values(dpop.HeaderName)
+		if len(proofs) == 0 {
+			c.Next()
+			return
+		}
+
+		if len(proofs) > 1 {
+			respondInvalidProof(c, dpop.ErrInvalidProof)
+			return
+		}
+
+		proof, err := verifier.Verify(c.Request.Context(), proofs[0], dpop.Request{
+			Method: c.Request.Method,
+			URL:    requestURL(c, baseURL),
+		})
+		if err != nil {
+			respondInvalidProof(c, err)
+			return
+		}
+
+		c.Request = c.Request.WithContext(dpop.WithThumbprint(c.Request.Context(), proof.Thumbprint))
+		c.Next()
+	}
+}
+
+// RequireDPoPBinding enforces the binding of DPoP-bound access tokens (cnf.jkt
+// claim): they are only accepted with the DPoP scheme and a proof signed by
+// the bound key that carries the hash of the token (ath), and the DPoP scheme
+// is only accepted for bound tokens. Bearer tokens pass unchanged. It must run
+// first in the chain: it rewrites the DPoP scheme to Bearer for the
+// middlewares after it, and only decodes the token, whose signature the
+// AuthMiddleware still checks.
+func RequireDPoPBinding(verifier dpop.IVerifier, baseURL string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		rawToken, isDPoP := strings.CutPrefix(header, dpop.Scheme+" ")
+		if !isDPoP {
+			rawToken, _ = strings.CutPrefix(header, "Bearer ")
+		}
+		rawToken = strings.TrimSpace(rawToken)
+
+		claims := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(rawToken, claims); err != nil {
+			claims = nil
+		}
+		cnf, _ := claims["cnf"].(map[string]interface{})
+		jkt, _ := cnf["jkt"].(string)
+
+		if !isDPoP {
+			if jkt != "" {
+				respondDPoPChallenge(c, "invalid_token", "Token vinculado a DPoP exige o esquema DPoP.")
+				return
+			}
+			c.Next()
+			return
+		}
+
+		if jkt == "" {
+			respondDPoPChallenge(c, "invalid_token", "Token não vinculado a DPoP.")
+			return
+		}
+
+		proofs := c.Request.Header.Values(dpop.HeaderName)
+		if len(proofs) != 1 {
+			respondDPoPChallenge(c, "invalid_dpop_proof", "Prova DPoP ausente ou inválida.")
+			return
+		}
+
+		_, err := verifier.Verify(c.Request.Context(), proofs[0], dpop.Request{
+			Method:      c.Request.Method,
+			URL:         requestURL(c, baseURL),
+			AccessToken: rawToken,
+			Thumbprint:  jkt,
+		})
+		if err != nil {
+			switch {
+			case errors.Is(err, dpop.ErrKeyMismatch):
+				respondDPoPChallenge(c, "invalid_dpop_proof", "Prova DPoP assinada por outra chave.")
+			case isProofError(err):
+				respondDPoPChallenge(c, "invalid_dpop_proof", "Prova DPoP ausente ou inválida.")
+			default:
+				log.Printf("[ERROR] Failed to verify DPoP proof: %v", err)
+				httphelpers.RespondInternalError(c, err)
+				c.Abort()
+			}
+			return
+		}
+
+		c.Request.Header.Set("Authorization", "Bearer "+rawToken)
+		c.Request = c.Request.WithContext(dpop.WithThumbprint(c.Request.Context(), jkt))
+		c.Next()
+	}
+}
+
+// requestURL returns the URL of the request as the client addressed it,
+// without the query.
+func requestURL(c *gin.Context, baseURL string) string {
+	return strings.TrimSuffix(baseURL, "/") + c.Request.URL.Path
+}
+
+func isProofError(err error) bool {
+	return errors.Is(err, dpop.ErrMissingProof) ||
+		errors.Is(err, dpop.ErrInvalidProof) ||
+		errors.Is(err, dpop.ErrProofReplayed)
+}
+
+// respondInvalidProof answers the token endpoints as RFC 9449, section 5.
+func respondInvalidProof(c *gin.Context, err error) {
+	if !isProofError(err) {
+		log.Printf("[ERROR] Failed to verify DPoP proof: %v", err)
+		httphelpers.RespondInternalError(c, err)
+		c.Abort()
+		return
+	}
+	c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+		"error":             "invalid_dpop_proof",
+		"error_description": "Prova DPoP inválida.",
+	})
+}
+
+// respondDPoPChallenge answers resource requests as RFC 9449, section 7.1.
+func respondDPoPChallenge(c *gin.Context, code string, message string) {
+	c.Header("WWW-Authenticate", fmt.Sprintf(`%s error="%s", algs="%s"`,
+		dpop.Scheme, code, strings.Join(dpop.SupportedAlgorithms, " ")))
+	httphelpers.RespondUnauthorized(c, message)
+	c.Abort()
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+// RejectImpersonation blocks tokens issued by the token exchange grant (act
+// claim) from sensitive account operations. It must run after the
+// AuthMiddleware.
+func RejectImpersonation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := TokenClaims(c)
+		if !ok {
+			httphelpers.RespondUnauthorized(c, "Authentication context missing")
+			c.Abort()
+			return
+		}
+
+		if _, impersonating := claims["act"]; impersonating {
+			httphelpers.RespondForbidden(c, "Operação não permitida durante impersonação.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// ImpersonatorID returns the sub of the act claim of an impersonation token.
+func ImpersonatorID(c *gin.Context) (string, bool) {
+	claims, ok := TokenClaims(c)
+	if !ok {
+		return "", false
+	}
+	act, _ := claims["act"].(map[string]interface{})
+	actorID, _ := act["sub"].(string)
+	return actorID, actorID != ""
+}
@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePasswordChangeCompleted rejects the restricted tokens issued by Login
+// when the password expired or an admin forced a change. It must run after
+// the AuthMiddleware; only the change-password route is registered without it.
+func RequirePasswordChangeCompleted() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := TokenClaims(c)
+		if !ok {
+			httphelpers.RespondUnauthorized(c, "Authentication context missing")
+			c.Abort()
+			return
+		}
+
+		if required, _ := claims["pwd_change_required"].(bool); required {
+			httphelpers.RespondForbidden(c, "Troca de senha obrigatória.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
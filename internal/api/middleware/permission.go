@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission only lets through access tokens whose permissions claim
+// contains the given permission. It must run after the AuthMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := TokenClaims(c)
+		if !ok {
+			httphelpers.RespondUnauthorized(c, "Authentication context missing")
+			c.Abort()
+			return
+		}
+
+		granted, _ := claims["permissions"].([]interface{})
+		for _, p := range granted {
+			if name, _ := p.(string); name == permission {
+				c.Next()
+				return
+			}
+		}
+
+		httphelpers.RespondForbidden(c, "Permissão insuficiente.")
+		c.Abort()
+	}
+}
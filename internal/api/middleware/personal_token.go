@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/personaltoken"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// personalTokenClaim carries the ID of the personal access token in the
+// claims built for it.
+const personalTokenClaim = "pat"
+
+type PersonalTokenAuthenticator interface {
+	Authenticate(ctx context.Context, rawToken string) (*personaltoken.Principal, error)
+}
+
+// Authenticate accepts personal access tokens alongside the JWTs checked by
+// jwtAuth, the common AuthMiddleware. For a personal access token it scopes
+// the request to the organization of the token and sets the same context
+// keys as the AuthMiddleware, with claims holding the scopes of the token as
+// permissions, so the permission checks apply unchanged. The claims have no
+// auth_time, so routes that require a recent authentication reject them.
+func Authenticate(jwtAuth gin.HandlerFunc, authenticator PersonalTokenAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawToken, _ := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		rawToken = strings.TrimSpace(rawToken)
+		if !strings.HasPrefix(rawToken, personaltoken.TokenPrefix) {
+			jwtAuth(c)
+			return
+		}
+
+		principal, err := authenticator.Authenticate(c.Request.Context(), rawToken)
+		if err != nil {
+			if !errors.Is(err, personaltoken.ErrInvalidToken) {
+				log.Printf("[ERROR] Failed to authenticate personal access token: %v", err)
+			}
+			httphelpers.RespondUnauthorized(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+
+		u := principal.User
+		permissions := make([]interface{}, 0, len(principal.Permissions))
+		for _, p := range principal.Permissions {
+			permissions = append(permissions, p)
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithOrganization(c.Request.Context(), u.OrganizationID))
+		c.Set("userID", u.ID.String())
+		c.Set("role", string(u.Role))
+		c.Set(claimsKey, jwt.MapClaims{
+			"sub":              u.ID.String(),
+			"org_id":           u.OrganizationID.String(),
+			"role":             string(u.Role),
+			"permissions":      permissions,
+			"typ":              "access",
+			personalTokenClaim: principal.Token.ID.String(),
+		})
+		c.Next()
+	}
+}
+
+// RejectPersonalTokens blocks personal access tokens from the routes that
+// manage the account or its credentials, personal access tokens included.
+// It must run after Authenticate.
+func RejectPersonalTokens() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := TokenClaims(c)
+		if !ok {
+			httphelpers.RespondUnauthorized(c, "Authentication context missing")
+			c.Abort()
+			return
+		}
+
+		if _, personal := claims[personalTokenClaim]; personal {
+			httphelpers.RespondForbidden(c, "Operação não permitida com token de acesso pessoal.")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
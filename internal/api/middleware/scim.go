@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/scim"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/gin-gonic/gin"
+)
+
+type SCIMAuthenticator interface {
+	Authenticate(ctx context.Context, rawToken string) (*scim.Token, error)
+}
+
+// RequireSCIMToken authenticates the identity provider by its SCIM token and
+// scopes the request to the organization of the token. It replaces the
+// ScopeTenant and AuthMiddleware on the SCIM routes, and answers failures
+// with SCIM errors.
+func RequireSCIMToken(authenticator SCIMAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawToken, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found {
+			abortSCIM(c, http.StatusUnauthorized, "Token SCIM ausente.")
+			return
+		}
+
+		token, err := authenticator.Authenticate(c.Request.Context(), strings.TrimSpace(rawToken))
+		if err != nil {
+			if errors.Is(err, scim.ErrInvalidToken) {
+				abortSCIM(c, http.StatusUnauthorized, "Token SCIM inválido.")
+				return
+			}
+			log.Printf("[ERROR] Failed to authenticate SCIM token: %v", err)
+			abortSCIM(c, http.StatusInternalServerError, "Erro interno.")
+			return
+		}
+
+		c.Request = c.Request.WithContext(tenant.WithOrganization(c.Request.Context(), token.OrganizationID))
+		c.Next()
+	}
+}
+
+func abortSCIM(c *gin.Context, status int, detail string) {
+	if status == http.StatusUnauthorized {
+		c.Header("WWW-Authenticate", `Bearer realm="scim"`)
+	}
+	c.Header("Content-Type", scim.MediaType)
+	c.AbortWithStatusJSON(status, gin.H{
+		"schemas": []string{scim.ErrorSchema},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	})
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	commonmiddleware "github.com/felipedenardo/chameleon-common/pkg/middleware"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// StepUpHeader carries the token returned by the step-up verification.
+const StepUpHeader = "X-Step-Up-Token"
+
+type StepUpVerifier interface {
+	RequireStepUp(ctx context.Context, userID uuid.UUID, stepUpToken string) error
+}
+
+// RequireStepUp asks users with e-mail OTP enabled for a step-up token before
+// sensitive operations. The token is consumed even if the handler fails. It
+// must run after the AuthMiddleware.
+func RequireStepUp(verifier StepUpVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userIDString, ok := commonmiddleware.RequireUserID(c)
+		if !ok {
+			c.Abort()
+			return
+		}
+
+		userID, err := uuid.Parse(userIDString)
+		if err != nil {
+			httphelpers.RespondUnauthorized(c, "Invalid user id in token")
+			c.Abort()
+			return
+		}
+
+		if err := verifier.RequireStepUp(c.Request.Context(), userID, c.GetHeader(StepUpHeader)); err != nil {
+			if errors.Is(err, auth.ErrStepUpRequired) {
+				httphelpers.RespondForbidden(c, "Verificação adicional necessária. Informe o código enviado por e-mail em /me/step-up.")
+			} else {
+				httphelpers.RespondInternalError(c, err)
+			}
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	httphelpers "github.com/felipedenardo/chameleon-common/pkg/http"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// ScopeTenant scopes the request context to the organization of the bearer
+// token (org_id claim), so every user query of the request only sees that
+// tenant. It must run before the AuthMiddleware, whose token version lookup is
+// also tenant scoped; the claim is only decoded here and the AuthMiddleware
+// still rejects tokens with an invalid signature.
+func ScopeTenant() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rawToken, found := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if !found {
+			c.Next()
+			return
+		}
+
+		claims := jwt.MapClaims{}
+		if _, _, err := jwt.NewParser().ParseUnverified(strings.TrimSpace(rawToken), claims); err != nil {
+			c.Next()
+			return
+		}
+
+		if rawOrg, _ := claims["org_id"].(string); rawOrg != "" {
+			organizationID, err := uuid.Parse(rawOrg)
+			if err != nil {
+				httphelpers.RespondUnauthorized(c, "Token inválido.")
+				c.Abort()
+				return
+			}
+			c.Request = c.Request.WithContext(tenant.WithOrganization(c.Request.Context(), organizationID))
+		}
+
+		c.Next()
+	}
+}
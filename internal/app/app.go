@@ -3,15 +3,43 @@ package app
 import (
 	"log"
 	"net/http"
+	"strconv"
+	"time"
 
 	_ "github.com/felipedenardo/chameleon-auth-api/docs"
+	adminhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/admin"
+	audithandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/audit"
 	authhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/auth"
+	federationhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/federation"
+	invitationhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/invitation"
+	loginhistoryhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/loginhistory"
+	organizationhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/organization"
+	personaltokenhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/personaltoken"
+	privacyhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/privacy"
+	profilehandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/profile"
+	rbachandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/rbac"
+	samlhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/saml"
+	scimhandler "github.com/felipedenardo/chameleon-auth-api/internal/api/handler/scim"
+	apimiddleware "github.com/felipedenardo/chameleon-auth-api/internal/api/middleware"
 	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
 	authdomain "github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/dpop"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/invitation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/personaltoken"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/privacy"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/scim"
 	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
 	"github.com/felipedenardo/chameleon-auth-api/internal/infra/database/postgresql/repository"
 	redisrepository "github.com/felipedenardo/chameleon-auth-api/internal/infra/database/redis"
+	"github.com/felipedenardo/chameleon-auth-api/internal/infra/ldap"
+	"github.com/felipedenardo/chameleon-auth-api/internal/infra/mailer"
+	"github.com/felipedenardo/chameleon-auth-api/internal/infra/oidc"
 	"github.com/felipedenardo/chameleon-auth-api/internal/infra/ratelimit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/infra/samlsp"
 	"github.com/felipedenardo/chameleon-common/pkg/middleware"
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
@@ -21,20 +49,85 @@ import (
 )
 
 type HandlerContainer struct {
-	AuthHandler *authhandler.Handler
-	RedisClient *redis.Client
-	DB          *gorm.DB
-	UserRepo    user.IRepository
+	AuthHandler       *authhandler.Handler
+	ProfileHandler    *profilehandler.Handler
+	AdminHandler      *adminhandler.Handler
+	RBACHandler       *rbachandler.Handler
+	OrgHandler        *organizationhandler.Handler
+	InviteHandler     *invitationhandler.Handler
+	AuditHandler      *audithandler.Handler
+	HistoryHandler    *loginhistoryhandler.Handler
+	PrivacyHandler    *privacyhandler.Handler
+	FederationHandler *federationhandler.Handler
+	SAMLHandler       *samlhandler.Handler
+	SCIMHandler       *scimhandler.Handler
+	TokenHandler      *personaltokenhandler.Handler
+	RedisClient       *redis.Client
+	DB                *gorm.DB
+	UserRepo          user.IRepository
+	PrivacyService    privacy.IService
+	AuthService       user.IService
+	SCIMService       scim.IService
+	TokenService      personaltoken.IService
 }
 
 func NewHandlerContainer(db *gorm.DB, cfg *config.Config, redisClient *redis.Client) *HandlerContainer {
 	userRepo := repository.NewUserRepository(db)
+	rbacRepo := repository.NewRBACRepository(db)
+	orgRepo := repository.NewOrganizationRepository(db)
+	invitationRepo := repository.NewInvitationRepository(db)
+	cacheRepo := redisrepository.NewCacheRepository(redisClient)
 	limiter := ratelimit.New(redisClient)
+	outboundMailer := mailer.New(cfg)
+	invitationService := invitation.NewInvitationService(invitationRepo, userRepo, orgRepo, rbacRepo, outboundMailer, cfg)
+	auditService := audit.NewAuditService(repository.NewAuditRepository(db))
+	historyRepo := repository.NewLoginHistoryRepository(db)
+	historyService := loginhistory.NewLoginHistoryService(historyRepo, outboundMailer)
+	privacyService := privacy.NewPrivacyService(userRepo, rbacRepo, historyRepo, auditService, cacheRepo)
+	identityRepo := repository.NewIdentityRepository(db)
+	directories, err := ldap.LoadBackends(cfg.LDAPBackendsFile)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to load LDAP backends: %v", err)
+	}
+	authService := authdomain.NewAuthService(userRepo, cacheRepo, rbacRepo, orgRepo, auditService, historyService, outboundMailer, cfg, identityRepo, directories)
+	providers, err := oidc.LoadProviders(cfg.OIDCProvidersFile)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to load OIDC providers: %v", err)
+	}
+	federationService := authdomain.NewFederationService(userRepo, cacheRepo, rbacRepo, orgRepo, auditService, historyService, outboundMailer, cfg, identityRepo, directories, oidc.NewClient(), providers)
+	samlProviders, err := samlsp.LoadIdentityProviders(cfg.SAMLProvidersFile)
+	if err != nil {
+		log.Fatalf("[FATAL] Failed to load SAML providers: %v", err)
+	}
+	serviceProvider := samlsp.NewServiceProvider(samlsp.Config{
+		EntityID:  cfg.SAMLEntityID,
+		ACSURL:    cfg.SAMLACSURL,
+		ClockSkew: time.Duration(cfg.SAMLClockSkewSec) * time.Second,
+	})
+	samlService := authdomain.NewSAMLService(userRepo, cacheRepo, rbacRepo, orgRepo, auditService, historyService, outboundMailer, cfg, identityRepo, directories, serviceProvider, samlProviders)
+	scimService := authdomain.NewSCIMService(userRepo, cacheRepo, rbacRepo, orgRepo, auditService, cfg, repository.NewSCIMRepository(db))
+	tokenService := authdomain.NewPersonalTokenService(userRepo, cacheRepo, rbacRepo, orgRepo, auditService, cfg, repository.NewPersonalTokenRepository(db))
 	return &HandlerContainer{
-		AuthHandler: newAuthHandler(cfg, redisClient, userRepo, limiter),
-		RedisClient: redisClient,
-		DB:          db,
-		UserRepo:    userRepo,
+		AuthHandler:       authhandler.NewAuthHandler(authService, cfg, limiter),
+		ProfileHandler:    profilehandler.NewProfileHandler(authdomain.NewProfileService(userRepo, cacheRepo, orgRepo, outboundMailer, cfg, directories)),
+		AdminHandler:      adminhandler.NewAdminHandler(authdomain.NewAdminService(userRepo, cacheRepo, orgRepo, auditService, outboundMailer, cfg, directories), authdomain.NewBulkService(userRepo, auditService)),
+		RBACHandler:       rbachandler.NewRBACHandler(rbac.NewRBACService(rbacRepo)),
+		OrgHandler:        organizationhandler.NewOrganizationHandler(organization.NewOrganizationService(orgRepo, userRepo, rbacRepo, cacheRepo, cfg)),
+		InviteHandler:     invitationhandler.NewInvitationHandler(invitationService),
+		AuditHandler:      audithandler.NewAuditHandler(auditService),
+		HistoryHandler:    loginhistoryhandler.NewLoginHistoryHandler(historyService),
+		PrivacyHandler:    privacyhandler.NewPrivacyHandler(privacyService),
+		FederationHandler: federationhandler.NewFederationHandler(federationService, cfg),
+		SAMLHandler:       samlhandler.NewSAMLHandler(samlService, cfg),
+		SCIMHandler:       scimhandler.NewSCIMHandler(scimService),
+		TokenHandler:      personaltokenhandler.NewPersonalTokenHandler(tokenService),
+		RedisClient:       redisClient,
+		DB:                db,
+		UserRepo:          userRepo,
+		PrivacyService:    privacyService,
+		AuthService:       authService,
+		SCIMService:       scimService,
+		TokenService:      tokenService,
 	}
 }
 
@@ -52,12 +145,6 @@ func (hc *HandlerContainer) Close() {
 	}
 }
 
-func newAuthHandler(cfg *config.Config, redisClient *redis.Client, userRepo user.IRepository, limiter *ratelimit.Limiter) *authhandler.Handler {
-	cacheRepo := redisrepository.NewCacheRepository(redisClient)
-	authService := authdomain.NewAuthService(userRepo, cacheRepo, cfg)
-	return authhandler.NewAuthHandler(authService, cfg, limiter)
-}
-
 func SetupRouter(handlers *HandlerContainer, cfg *config.Config) *gin.Engine {
 	r := gin.Default()
 
@@ -68,9 +155,13 @@ func SetupRouter(handlers *HandlerContainer, cfg *config.Config) *gin.Engine {
 		c.Header("Referrer-Policy", "no-referrer")
 		c.Next()
 	})
+	r.Use(apimiddleware.AuditRequest())
 
 	cacheRepo := redisrepository.NewCacheRepository(handlers.RedisClient)
 	tokenManager := redisrepository.NewTokenVersionManager(cacheRepo, handlers.UserRepo)
+	dpopVerifier := dpop.NewVerifier(cacheRepo, cfg)
+	dpopProof := apimiddleware.DPoPProof(dpopVerifier, cfg.AppPublicURL)
+	dpopBinding := apimiddleware.RequireDPoPBinding(dpopVerifier, cfg.AppPublicURL)
 
 	basePath := r.Group(cfg.AppBasePath)
 	{
@@ -82,24 +173,166 @@ func SetupRouter(handlers *HandlerContainer, cfg *config.Config) *gin.Engine {
 			{
 				public.POST("/register", handlers.AuthHandler.Register)
 				public.POST("/login", handlers.AuthHandler.Login)
-				public.POST("/refresh", handlers.AuthHandler.RefreshToken)
+				public.POST("/login/magic-link", handlers.AuthHandler.RequestMagicLink)
+				public.POST("/login/magic-link/verify", handlers.AuthHandler.VerifyMagicLink)
+				public.POST("/login/otp", handlers.AuthHandler.VerifyLoginOTP)
+				public.POST("/refresh", dpopProof, handlers.AuthHandler.RefreshToken)
 				public.POST("/forgot-password", handlers.AuthHandler.ForgotPassword)
 				public.POST("/reset-password", handlers.AuthHandler.ResetPassword)
+				public.POST("/reactivate/request", handlers.AuthHandler.RequestReactivation)
+				public.POST("/reactivate", handlers.AuthHandler.Reactivate)
+				public.POST("/me/email/confirm", handlers.ProfileHandler.ConfirmEmailChange)
+				public.POST("/me/email/revert", handlers.ProfileHandler.RevertEmailChange)
+				public.POST("/invitations/accept", handlers.InviteHandler.AcceptInvitation)
+				public.POST("/oauth/token", dpopProof, handlers.AuthHandler.Token)
+				public.POST("/oauth/device_authorization", handlers.AuthHandler.DeviceAuthorization)
+				public.GET("/oidc/providers", handlers.FederationHandler.ListProviders)
+				public.POST("/oidc/:provider/authorize", handlers.FederationHandler.Authorize)
+				public.POST("/oidc/callback", handlers.FederationHandler.Callback)
+				public.GET("/saml/providers", handlers.SAMLHandler.ListProviders)
+				public.GET("/saml/metadata", handlers.SAMLHandler.Metadata)
+				public.POST("/saml/:provider/authorize", handlers.SAMLHandler.Authorize)
+				public.POST("/saml/acs", handlers.SAMLHandler.ConsumeAssertion)
+				public.POST("/saml/token", handlers.SAMLHandler.Token)
+			}
+
+			scopeTenant := apimiddleware.ScopeTenant()
+			authMiddleware := apimiddleware.Authenticate(middleware.AuthMiddleware(cfg.JWTSecret, cacheRepo, tokenManager), handlers.TokenService)
+			passwordChangeGuard := apimiddleware.RequirePasswordChangeCompleted()
+			auditActor := apimiddleware.AuditActor()
+			rejectImpersonation := apimiddleware.RejectImpersonation()
+			rejectPersonalTokens := apimiddleware.RejectPersonalTokens()
+			stepUp := apimiddleware.RequireStepUp(handlers.AuthService)
+			recentSelfAuth := apimiddleware.RequireAuthContext("", time.Duration(cfg.ReauthMaxAgeSec)*time.Second)
+
+			pendingPasswordChange := api.Group("/").Use(dpopBinding, scopeTenant, authMiddleware, auditActor)
+			{
+				pendingPasswordChange.POST("/change-password", rejectPersonalTokens, rejectImpersonation, stepUp, handlers.AuthHandler.ChangePassword)
+				pendingPasswordChange.POST("/me/step-up", rejectPersonalTokens, rejectImpersonation, handlers.AuthHandler.StartStepUp)
+				pendingPasswordChange.POST("/me/step-up/verify", rejectPersonalTokens, rejectImpersonation, handlers.AuthHandler.VerifyStepUp)
+			}
+
+			protected := api.Group("/").Use(dpopBinding, scopeTenant, authMiddleware, auditActor, passwordChangeGuard)
+			{
+				protected.POST("/logout", rejectPersonalTokens, handlers.AuthHandler.Logout)
+				protected.POST("/logout-all", rejectPersonalTokens, rejectImpersonation, handlers.AuthHandler.LogoutAll)
+				protected.POST("/reauthenticate", rejectPersonalTokens, rejectImpersonation, handlers.AuthHandler.Reauthenticate)
+				protected.POST("/deactivate", rejectPersonalTokens, rejectImpersonation, stepUp, recentSelfAuth, handlers.AuthHandler.DeactivateSelf)
+				protected.GET("/me", handlers.ProfileHandler.GetMe)
+				protected.PATCH("/me", handlers.ProfileHandler.UpdateMe)
+				protected.POST("/me/email", rejectPersonalTokens, rejectImpersonation, stepUp, handlers.ProfileHandler.RequestEmailChange)
+				protected.GET("/me/login-history", handlers.HistoryHandler.ListMine)
+				protected.GET("/me/export", handlers.PrivacyHandler.ExportMine)
+				protected.POST("/me/delete", rejectPersonalTokens, rejectImpersonation, stepUp, handlers.AuthHandler.RequestDeletion)
+				protected.POST("/me/mfa/email", rejectPersonalTokens, rejectImpersonation, handlers.AuthHandler.EnableEmailOTP)
+				protected.DELETE("/me/mfa/email", rejectPersonalTokens, rejectImpersonation, stepUp, handlers.AuthHandler.DisableEmailOTP)
+				protected.GET("/me/identities", handlers.FederationHandler.ListIdentities)
+				protected.POST("/me/identities/:provider", rejectPersonalTokens, rejectImpersonation, recentSelfAuth, handlers.FederationHandler.Link)
+				protected.DELETE("/me/identities/:id", rejectPersonalTokens, rejectImpersonation, stepUp, handlers.FederationHandler.Unlink)
+				protected.GET("/me/tokens", rejectPersonalTokens, handlers.TokenHandler.ListTokens)
+				protected.POST("/me/tokens", rejectPersonalTokens, rejectImpersonation, handlers.TokenHandler.CreateToken)
+				protected.DELETE("/me/tokens/:id", rejectPersonalTokens, rejectImpersonation, handlers.TokenHandler.RevokeToken)
+				protected.GET("/oauth/device", rejectPersonalTokens, handlers.AuthHandler.GetDeviceRequest)
+				protected.POST("/oauth/device", rejectPersonalTokens, rejectImpersonation, handlers.AuthHandler.DecideDeviceRequest)
 			}
 
-			authMiddleware := middleware.AuthMiddleware(cfg.JWTSecret, cacheRepo, tokenManager)
+			org := api.Group("/organization").Use(dpopBinding, scopeTenant, authMiddleware, auditActor, passwordChangeGuard)
+			{
+				membersRead := apimiddleware.RequirePermission(organization.PermMembersRead)
+				membersWrite := apimiddleware.RequirePermission(organization.PermMembersWrite)
+
+				org.GET("", handlers.OrgHandler.GetCurrentOrganization)
+				org.GET("/members", membersRead, handlers.OrgHandler.ListMembers)
+				org.PUT("/members/:user_id/role", membersWrite, handlers.OrgHandler.UpdateMemberRole)
+				org.DELETE("/members/:user_id", membersWrite, handlers.OrgHandler.RemoveMember)
+			}
 
-			protected := api.Group("/").Use(authMiddleware)
+			admin := api.Group("/admin").Use(dpopBinding, scopeTenant, authMiddleware, auditActor, passwordChangeGuard)
 			{
-				protected.POST("/change-password", handlers.AuthHandler.ChangePassword)
-				protected.POST("/logout", handlers.AuthHandler.Logout)
-				protected.POST("/logout-all", handlers.AuthHandler.LogoutAll)
-				protected.POST("/deactivate", handlers.AuthHandler.DeactivateSelf)
+				recentAuth := apimiddleware.RequireAuthContext(cfg.AdminMinACR, time.Duration(cfg.AdminAuthMaxAgeSec)*time.Second)
+
+				usersRead := apimiddleware.RequirePermission(rbac.PermUsersRead)
+				usersWrite := apimiddleware.RequirePermission(rbac.PermUsersWrite)
+				rolesRead := apimiddleware.RequirePermission(rbac.PermRolesRead)
+				rolesWrite := apimiddleware.RequirePermission(rbac.PermRolesWrite)
+
+				usersImport := apimiddleware.RequirePermission(rbac.PermUsersImport)
+				usersExport := apimiddleware.RequirePermission(rbac.PermUsersExport)
+				usersDelete := apimiddleware.RequirePermission(rbac.PermUsersDelete)
+				// Exporting password hashes needs the same recent sign-in as
+				// the import, and never a personal access token.
+				exportsHashes := func(c *gin.Context) bool {
+					include, _ := strconv.ParseBool(c.Query("include_password_hash"))
+					return include
+				}
+
+				admin.GET("/users", usersRead, handlers.AdminHandler.ListUsers)
+				admin.POST("/users/import", usersImport, recentAuth, handlers.AdminHandler.ImportUsers)
+				admin.GET("/users/export", usersExport,
+					apimiddleware.When(exportsHashes, rejectPersonalTokens),
+					apimiddleware.When(exportsHashes, recentAuth),
+					handlers.AdminHandler.ExportUsers)
+				admin.GET("/users/:id", usersRead, handlers.AdminHandler.GetUser)
+				admin.DELETE("/users/:id", usersDelete, recentAuth, handlers.PrivacyHandler.Purge)
+				admin.GET("/users/:id/login-history", usersRead, handlers.HistoryHandler.ListForUser)
+				admin.PUT("/users/:id/status", usersWrite, recentAuth, handlers.AuthHandler.UpdateUserStatus)
+				admin.GET("/users/:id/status-history", usersRead, handlers.AdminHandler.StatusHistory)
+				admin.POST("/users/:id/force-password-change", usersWrite, recentAuth, handlers.AuthHandler.ForcePasswordChange)
+				admin.POST("/users/:id/logout", usersWrite, recentAuth, handlers.AdminHandler.ForceLogout)
+				admin.POST("/users/:id/password-reset", usersWrite, recentAuth, handlers.AdminHandler.SendPasswordReset)
+				admin.DELETE("/users/:id/lock", usersWrite, recentAuth, handlers.AdminHandler.Unlock)
+				admin.GET("/users/:id/roles", rolesRead, handlers.RBACHandler.GetUserRoles)
+				admin.PUT("/users/:id/roles", rolesWrite, recentAuth, handlers.RBACHandler.SetUserRoles)
+
+				admin.GET("/roles", rolesRead, handlers.RBACHandler.ListRoles)
+				admin.GET("/roles/:id", rolesRead, handlers.RBACHandler.GetRole)
+				admin.POST("/roles", rolesWrite, recentAuth, handlers.RBACHandler.CreateRole)
+				admin.PUT("/roles/:id", rolesWrite, recentAuth, handlers.RBACHandler.UpdateRole)
+				admin.DELETE("/roles/:id", rolesWrite, recentAuth, handlers.RBACHandler.DeleteRole)
+				admin.GET("/permissions", rolesRead, handlers.RBACHandler.ListPermissions)
+
+				organizationsRead := apimiddleware.RequirePermission(organization.PermOrganizationsRead)
+				organizationsWrite := apimiddleware.RequirePermission(organization.PermOrganizationsWrite)
+
+				admin.GET("/organizations", organizationsRead, handlers.OrgHandler.ListOrganizations)
+				admin.POST("/organizations", organizationsWrite, recentAuth, handlers.OrgHandler.CreateOrganization)
+
+				membersRead := apimiddleware.RequirePermission(organization.PermMembersRead)
+				membersWrite := apimiddleware.RequirePermission(organization.PermMembersWrite)
+
+				admin.GET("/invitations", membersRead, handlers.InviteHandler.ListInvitations)
+				admin.POST("/invitations", membersWrite, recentAuth, handlers.InviteHandler.CreateInvitation)
+				admin.POST("/invitations/:id/resend", membersWrite, recentAuth, handlers.InviteHandler.ResendInvitation)
+				admin.DELETE("/invitations/:id", membersWrite, recentAuth, handlers.InviteHandler.RevokeInvitation)
+
+				auditRead := apimiddleware.RequirePermission(audit.PermAuditRead)
+
+				admin.GET("/audit-events", auditRead, handlers.AuditHandler.ListEvents)
+
+				scimManage := apimiddleware.RequirePermission(scim.PermManage)
+
+				admin.GET("/scim/tokens", scimManage, handlers.SCIMHandler.ListTokens)
+				admin.POST("/scim/tokens", scimManage, recentAuth, handlers.SCIMHandler.CreateToken)
+				admin.DELETE("/scim/tokens/:id", scimManage, recentAuth, handlers.SCIMHandler.RevokeToken)
+				admin.GET("/scim/groups", scimManage, handlers.SCIMHandler.AdminListGroups)
+				admin.PUT("/scim/groups/:id/roles", scimManage, rolesWrite, recentAuth, handlers.SCIMHandler.SetGroupRoles)
 			}
 
-			admin := api.Group("/admin").Use(authMiddleware)
+			provisioning := api.Group("/scim/v2").Use(apimiddleware.RequireSCIMToken(handlers.SCIMService))
 			{
-				admin.PUT("/users/:id/status", handlers.AuthHandler.UpdateUserStatus)
+				provisioning.GET("/ServiceProviderConfig", handlers.SCIMHandler.ServiceProviderConfig)
+				provisioning.GET("/Users", handlers.SCIMHandler.ListUsers)
+				provisioning.POST("/Users", handlers.SCIMHandler.CreateUser)
+				provisioning.GET("/Users/:id", handlers.SCIMHandler.GetUser)
+				provisioning.PUT("/Users/:id", handlers.SCIMHandler.ReplaceUser)
+				provisioning.PATCH("/Users/:id", handlers.SCIMHandler.PatchUser)
+				provisioning.DELETE("/Users/:id", handlers.SCIMHandler.DeleteUser)
+				provisioning.GET("/Groups", handlers.SCIMHandler.ListGroups)
+				provisioning.POST("/Groups", handlers.SCIMHandler.CreateGroup)
+				provisioning.GET("/Groups/:id", handlers.SCIMHandler.GetGroup)
+				provisioning.PUT("/Groups/:id", handlers.SCIMHandler.ReplaceGroup)
+				provisioning.PATCH("/Groups/:id", handlers.SCIMHandler.PatchGroup)
+				provisioning.DELETE("/Groups/:id", handlers.SCIMHandler.DeleteGroup)
 			}
 
 			api.GET("/health", func(c *gin.Context) {
@@ -0,0 +1,22 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	authdomain "github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/privacy"
+)
+
+// StartJobs runs the background jobs until ctx is cancelled.
+func (hc *HandlerContainer) StartJobs(ctx context.Context, cfg *config.Config) {
+	if cfg.SuspensionSweepIntervalSec > 0 {
+		interval := time.Duration(cfg.SuspensionSweepIntervalSec) * time.Second
+		go authdomain.NewSuspensionJob(hc.UserRepo, interval).Run(ctx)
+	}
+	if cfg.AccountDeletionSweepIntervalSec > 0 {
+		interval := time.Duration(cfg.AccountDeletionSweepIntervalSec) * time.Second
+		go privacy.NewDeletionJob(hc.PrivacyService, interval).Run(ctx)
+	}
+}
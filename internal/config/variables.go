@@ -4,6 +4,7 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
 )
@@ -30,6 +31,8 @@ type Config struct {
 	LoginRateWindowSec   int
 	ForgotRateLimit      int
 	ForgotRateWindowSec  int
+	LoginLockoutMax      int
+	LoginLockoutMinutes  int
 	RefreshRateLimit     int
 	RefreshRateWindowSec int
 	JWTIssuer            string
@@ -43,6 +46,57 @@ type Config struct {
 	TokenTTLHours        int
 	ResetTokenTTLMinutes int
 	RefreshTokenTTLDays  int
+	PasswordMaxAgeDays   int
+	EmailChangeTTLMin    int
+	EmailRevertTTLHours  int
+	InvitationTTLHours   int
+	ImpersonationTTLMin  int
+	AppPublicURL         string
+	SMTPHost             string
+	SMTPPort             string
+	SMTPUsername         string
+	SMTPPassword         string
+	MailFrom             string
+
+	SuspensionSweepIntervalSec int
+
+	AccountDeletionGraceDays        int
+	AccountDeletionSweepIntervalSec int
+
+	ReactivationTTLMin int
+	MagicLinkTTLMin    int
+
+	EmailOTPTTLMin      int
+	EmailOTPMaxAttempts int
+	StepUpTTLMin        int
+
+	ReauthMaxAgeSec    int
+	AdminAuthMaxAgeSec int
+	AdminMinACR        string
+
+	OIDCProvidersFile string
+	OIDCRedirectURL   string
+	OIDCStateTTLMin   int
+
+	LDAPBackendsFile string
+
+	SAMLProvidersFile    string
+	SAMLEntityID         string
+	SAMLACSURL           string
+	SAMLLoginRedirectURL string
+	SAMLRequestTTLMin    int
+	SAMLClockSkewSec     int
+
+	PersonalTokenDefaultTTLDays int
+	PersonalTokenMaxTTLDays     int
+
+	DeviceClientIDs       []string
+	DeviceCodeTTLMin      int
+	DevicePollIntervalSec int
+	DeviceVerificationURL string
+
+	DPoPProofMaxAgeSec int
+	DPoPClockSkewSec   int
 }
 
 func Load() *Config {
@@ -70,6 +124,8 @@ func Load() *Config {
 		LoginRateWindowSec:   getEnvInt("LOGIN_RATE_WINDOW_SEC", 60),
 		ForgotRateLimit:      getEnvInt("FORGOT_RATE_LIMIT", 5),
 		ForgotRateWindowSec:  getEnvInt("FORGOT_RATE_WINDOW_SEC", 300),
+		LoginLockoutMax:      getEnvInt("LOGIN_LOCKOUT_MAX_ATTEMPTS", 5),
+		LoginLockoutMinutes:  getEnvInt("LOGIN_LOCKOUT_MINUTES", 15),
 		RefreshRateLimit:     getEnvInt("REFRESH_RATE_LIMIT", 30),
 		RefreshRateWindowSec: getEnvInt("REFRESH_RATE_WINDOW_SEC", 60),
 		JWTIssuer:            getEnv("JWT_ISSUER", "chameleon-auth-api"),
@@ -83,6 +139,57 @@ func Load() *Config {
 		TokenTTLHours:        getEnvInt("TOKEN_TTL_HOURS", 24),
 		ResetTokenTTLMinutes: getEnvInt("RESET_TOKEN_TTL_MINUTES", 30),
 		RefreshTokenTTLDays:  getEnvInt("REFRESH_TOKEN_TTL_DAYS", 30),
+		PasswordMaxAgeDays:   getEnvInt("PASSWORD_MAX_AGE_DAYS", 0),
+		EmailChangeTTLMin:    getEnvInt("EMAIL_CHANGE_TTL_MINUTES", 60),
+		EmailRevertTTLHours:  getEnvInt("EMAIL_REVERT_TTL_HOURS", 72),
+		InvitationTTLHours:   getEnvInt("INVITATION_TTL_HOURS", 72),
+		ImpersonationTTLMin:  getEnvInt("IMPERSONATION_TTL_MINUTES", 15),
+		AppPublicURL:         getEnv("APP_PUBLIC_URL", "http://localhost:8081"),
+		SMTPHost:             os.Getenv("SMTP_HOST"),
+		SMTPPort:             getEnv("SMTP_PORT", "587"),
+		SMTPUsername:         os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:         os.Getenv("SMTP_PASSWORD"),
+		MailFrom:             getEnv("MAIL_FROM", "no-reply@chameleon.local"),
+
+		SuspensionSweepIntervalSec: getEnvInt("SUSPENSION_SWEEP_INTERVAL_SEC", 60),
+
+		AccountDeletionGraceDays:        getEnvInt("ACCOUNT_DELETION_GRACE_DAYS", 30),
+		AccountDeletionSweepIntervalSec: getEnvInt("ACCOUNT_DELETION_SWEEP_INTERVAL_SEC", 300),
+
+		ReactivationTTLMin: getEnvInt("REACTIVATION_TOKEN_TTL_MINUTES", 60),
+		MagicLinkTTLMin:    getEnvInt("MAGIC_LINK_TTL_MINUTES", 10),
+
+		EmailOTPTTLMin:      getEnvInt("EMAIL_OTP_TTL_MINUTES", 10),
+		EmailOTPMaxAttempts: getEnvInt("EMAIL_OTP_MAX_ATTEMPTS", 5),
+		StepUpTTLMin:        getEnvInt("STEP_UP_TTL_MINUTES", 5),
+
+		ReauthMaxAgeSec:    getEnvInt("REAUTH_MAX_AGE_SEC", 300),
+		AdminAuthMaxAgeSec: getEnvInt("ADMIN_AUTH_MAX_AGE_SEC", 900),
+		AdminMinACR:        getEnv("ADMIN_MIN_ACR", ""),
+
+		OIDCProvidersFile: getEnv("OIDC_PROVIDERS_FILE", ""),
+		OIDCRedirectURL:   getEnv("OIDC_REDIRECT_URL", ""),
+		OIDCStateTTLMin:   getEnvInt("OIDC_STATE_TTL_MINUTES", 10),
+
+		LDAPBackendsFile: getEnv("LDAP_BACKENDS_FILE", ""),
+
+		SAMLProvidersFile:    getEnv("SAML_PROVIDERS_FILE", ""),
+		SAMLEntityID:         getEnv("SAML_SP_ENTITY_ID", ""),
+		SAMLACSURL:           getEnv("SAML_ACS_URL", ""),
+		SAMLLoginRedirectURL: getEnv("SAML_LOGIN_REDIRECT_URL", ""),
+		SAMLRequestTTLMin:    getEnvInt("SAML_REQUEST_TTL_MINUTES", 10),
+		SAMLClockSkewSec:     getEnvInt("SAML_CLOCK_SKEW_SEC", 120),
+
+		PersonalTokenDefaultTTLDays: getEnvInt("PAT_DEFAULT_TTL_DAYS", 90),
+		PersonalTokenMaxTTLDays:     getEnvInt("PAT_MAX_TTL_DAYS", 365),
+
+		DeviceClientIDs:       getEnvList("DEVICE_CLIENT_IDS", []string{"chameleon-cli"}),
+		DeviceCodeTTLMin:      getEnvInt("DEVICE_CODE_TTL_MINUTES", 10),
+		DevicePollIntervalSec: getEnvInt("DEVICE_POLL_INTERVAL_SEC", 5),
+		DeviceVerificationURL: getEnv("DEVICE_VERIFICATION_URL", ""),
+
+		DPoPProofMaxAgeSec: getEnvInt("DPOP_PROOF_MAX_AGE_SEC", 60),
+		DPoPClockSkewSec:   getEnvInt("DPOP_CLOCK_SKEW_SEC", 30),
 	}
 
 	if cfg.JWTSecret == "" {
@@ -91,6 +198,22 @@ func Load() *Config {
 	if len(cfg.JWTSecret) < 32 {
 		log.Fatal("[FATAL] JWT_SECRET must be at least 32 characters")
 	}
+	if cfg.OIDCRedirectURL == "" {
+		cfg.OIDCRedirectURL = strings.TrimSuffix(cfg.AppPublicURL, "/") + "/login/oidc/callback"
+	}
+	samlBaseURL := strings.TrimSuffix(cfg.AppPublicURL, "/") + cfg.AppBasePath + "/api/v1/saml"
+	if cfg.SAMLEntityID == "" {
+		cfg.SAMLEntityID = samlBaseURL + "/metadata"
+	}
+	if cfg.SAMLACSURL == "" {
+		cfg.SAMLACSURL = samlBaseURL + "/acs"
+	}
+	if cfg.SAMLLoginRedirectURL == "" {
+		cfg.SAMLLoginRedirectURL = strings.TrimSuffix(cfg.AppPublicURL, "/") + "/login/saml/callback"
+	}
+	if cfg.DeviceVerificationURL == "" {
+		cfg.DeviceVerificationURL = strings.TrimSuffix(cfg.AppPublicURL, "/") + "/device"
+	}
 
 	return cfg
 }
@@ -110,6 +233,21 @@ func getEnvInt(key string, fallback int) int {
 	return fallback
 }
 
+// getEnvList reads a comma-separated list, skipping blank items.
+func getEnvList(key string, fallback []string) []string {
+	valueStr := os.Getenv(key)
+	if valueStr == "" {
+		return fallback
+	}
+	var values []string
+	for _, item := range strings.Split(valueStr, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
 func getEnvBool(key string, fallback bool) bool {
 	valueStr := os.Getenv(key)
 	if valueStr == "" {
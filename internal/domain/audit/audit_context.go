@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// RequestInfo identifies the HTTP request that triggered an event.
+type RequestInfo struct {
+	IPAddress string
+	UserAgent string
+	RequestID string
+}
+
+type requestKey struct{}
+
+type actorKey struct{}
+
+func WithRequest(ctx context.Context, info RequestInfo) context.Context {
+	return context.WithValue(ctx, requestKey{}, info)
+}
+
+func RequestFrom(ctx context.Context) RequestInfo {
+	info, _ := ctx.Value(requestKey{}).(RequestInfo)
+	return info
+}
+
+// WithActor records the authenticated user performing the request, used for
+// events whose service method does not receive it.
+func WithActor(ctx context.Context, actorID uuid.UUID) context.Context {
+	return context.WithValue(ctx, actorKey{}, actorID)
+}
+
+func ActorFrom(ctx context.Context) (uuid.UUID, bool) {
+	id, ok := ctx.Value(actorKey{}).(uuid.UUID)
+	return id, ok && id != uuid.Nil
+}
@@ -0,0 +1,270 @@
+package audit
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ActionLogin           = "auth.login"
+	ActionLogoutAll       = "auth.logout_all"
+	ActionAccountLocked   = "auth.account_locked"
+	ActionPasswordChanged = "user.password_changed"
+	ActionPasswordReset   = "user.password_reset"
+	ActionDeactivated     = "user.deactivated"
+	ActionStatusChanged   = "admin.user_status_changed"
+	ActionImpersonation   = "admin.impersonation"
+	ActionForceLogout     = "admin.force_logout"
+	ActionForceReset      = "admin.password_reset_sent"
+	ActionForceChange     = "admin.force_password_change"
+	ActionUnlocked        = "admin.account_unlocked"
+	ActionUsersExported   = "admin.users_exported"
+)
+
+const ActionReactivated = "user.reactivated"
+
+const (
+	ActionEmailOTPEnabled  = "user.email_otp_enabled"
+	ActionEmailOTPDisabled = "user.email_otp_disabled"
+	ActionStepUp           = "auth.step_up"
+	ActionReauthenticated  = "auth.reauthenticated"
+)
+
+const (
+	ActionIdentityLinked   = "user.identity_linked"
+	ActionIdentityUnlinked = "user.identity_unlinked"
+	ActionUserProvisioned  = "user.provisioned"
+	ActionAttributesSynced = "user.attributes_synced"
+	ActionEmailChanged     = "user.email_changed"
+)
+
+const (
+	ActionSCIMTokenCreated      = "admin.scim_token_created"
+	ActionSCIMTokenRevoked      = "admin.scim_token_revoked"
+	ActionSCIMGroupRolesChanged = "admin.scim_group_roles_changed"
+)
+
+const (
+	ActionPersonalTokenCreated = "user.personal_token_created"
+	ActionPersonalTokenRevoked = "user.personal_token_revoked"
+)
+
+const (
+	ActionDeviceApproved = "user.device_approved"
+	ActionDeviceDenied   = "user.device_denied"
+)
+
+// Account deletion actions. The anonymization redacts the personal data of
+// earlier events of the user, see Event.RedactedAt.
+const (
+	ActionDeletionRequested = "user.deletion_requested"
+	ActionDeletionCancelled = "user.deletion_cancelled"
+	ActionAnonymized        = "user.anonymized"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+const PermAuditRead = "audit:read"
+
+// GenesisHash is the prev_hash of the first event of the chain.
+var GenesisHash = strings.Repeat("0", 64)
+
+// Event is a row of the append-only audit log. Every event stores the hash of
+// the previous one (by Sequence), so removing or editing a row breaks the
+// chain from that point on.
+type Event struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Sequence       int64      `json:"sequence"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid" json:"organization_id"`
+	ActorID        *uuid.UUID `gorm:"type:uuid" json:"actor_id"`
+	TargetID       *uuid.UUID `gorm:"type:uuid" json:"target_id"`
+	Action         string     `json:"action"`
+	Outcome        string     `json:"outcome"`
+	IPAddress      string     `json:"ip_address"`
+	UserAgent      string     `json:"user_agent"`
+	RequestID      string     `json:"request_id"`
+	Metadata       Metadata   `gorm:"type:jsonb" json:"metadata"`
+	PrevHash       string     `json:"prev_hash"`
+	Hash           string     `json:"hash"`
+	CreatedAt      time.Time  `json:"created_at"`
+
+	// PIIHash commits to the personal data of the event (IP address, user
+	// agent and the PIIMetadataKeys of Metadata) salted with PIISalt, and Hash
+	// covers PIIHash in place of that data. The anonymization erases the data
+	// and the salt but keeps PIIHash, so the rest of a redacted event is still
+	// verified against Hash. Events recorded before the commitment existed
+	// have no PIIHash and hash the personal data directly.
+	PIISalt string `json:"-"`
+	PIIHash string `json:"pii_hash,omitempty"`
+
+	// RedactedAt is set when the personal data of the event was erased on the
+	// anonymization of a user.
+	RedactedAt *time.Time `json:"redacted_at,omitempty"`
+}
+
+func (Event) TableName() string {
+	return "audit_events"
+}
+
+// PIIMetadataKeys are the metadata keys holding personal data, erased with
+// the IP address and user agent on the anonymization.
+var PIIMetadataKeys = []string{"email"}
+
+// Seal sets the salt and the commitment of the personal data of a new event.
+func (e *Event) Seal() error {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return err
+	}
+	e.PIISalt = hex.EncodeToString(salt)
+	e.PIIHash = e.ComputePIIHash()
+	return nil
+}
+
+// ComputePIIHash returns the SHA-256 of the salt and the personal data of the
+// event.
+func (e *Event) ComputePIIHash() string {
+	personal := Metadata{}
+	for _, key := range PIIMetadataKeys {
+		if value, ok := e.Metadata[key]; ok {
+			personal[key] = value
+		}
+	}
+	payload, _ := json.Marshal(struct {
+		Salt      string   `json:"salt"`
+		IPAddress string   `json:"ip_address"`
+		UserAgent string   `json:"user_agent"`
+		Metadata  Metadata `json:"metadata"`
+	}{
+		Salt:      e.PIISalt,
+		IPAddress: e.IPAddress,
+		UserAgent: e.UserAgent,
+		Metadata:  personal,
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// ComputeHash returns the SHA-256 of the previous hash and every other field
+// of the event, with PIIHash standing for the personal data. CreatedAt must
+// already be truncated to the database precision (microseconds).
+func (e *Event) ComputeHash() string {
+	if e.PIIHash == "" {
+		return e.computeLegacyHash()
+	}
+
+	metadata := Metadata{}
+	for key, value := range e.Metadata {
+		if !slices.Contains(PIIMetadataKeys, key) {
+			metadata[key] = value
+		}
+	}
+	payload, _ := json.Marshal(struct {
+		Sequence       int64      `json:"sequence"`
+		PrevHash       string     `json:"prev_hash"`
+		ID             uuid.UUID  `json:"id"`
+		OrganizationID *uuid.UUID `json:"organization_id"`
+		ActorID        *uuid.UUID `json:"actor_id"`
+		TargetID       *uuid.UUID `json:"target_id"`
+		Action         string     `json:"action"`
+		Outcome        string     `json:"outcome"`
+		RequestID      string     `json:"request_id"`
+		Metadata       Metadata   `json:"metadata"`
+		PIIHash        string     `json:"pii_hash"`
+		CreatedAt      string     `json:"created_at"`
+	}{
+		Sequence:       e.Sequence,
+		PrevHash:       e.PrevHash,
+		ID:             e.ID,
+		OrganizationID: e.OrganizationID,
+		ActorID:        e.ActorID,
+		TargetID:       e.TargetID,
+		Action:         e.Action,
+		Outcome:        e.Outcome,
+		RequestID:      e.RequestID,
+		Metadata:       metadata,
+		PIIHash:        e.PIIHash,
+		CreatedAt:      e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// computeLegacyHash is the hash of the events without PIIHash, which covers
+// the personal data itself.
+func (e *Event) computeLegacyHash() string {
+	payload, _ := json.Marshal(struct {
+		Sequence       int64      `json:"sequence"`
+		PrevHash       string     `json:"prev_hash"`
+		ID             uuid.UUID  `json:"id"`
+		OrganizationID *uuid.UUID `json:"organization_id"`
+		ActorID        *uuid.UUID `json:"actor_id"`
+		TargetID       *uuid.UUID `json:"target_id"`
+		Action         string     `json:"action"`
+		Outcome        string     `json:"outcome"`
+		IPAddress      string     `json:"ip_address"`
+		UserAgent      string     `json:"user_agent"`
+		RequestID      string     `json:"request_id"`
+		Metadata       Metadata   `json:"metadata"`
+		CreatedAt      string     `json:"created_at"`
+	}{
+		Sequence:       e.Sequence,
+		PrevHash:       e.PrevHash,
+		ID:             e.ID,
+		OrganizationID: e.OrganizationID,
+		ActorID:        e.ActorID,
+		TargetID:       e.TargetID,
+		Action:         e.Action,
+		Outcome:        e.Outcome,
+		IPAddress:      e.IPAddress,
+		UserAgent:      e.UserAgent,
+		RequestID:      e.RequestID,
+		Metadata:       e.Metadata,
+		CreatedAt:      e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// Metadata holds string attributes of an event. Values are strings only, so
+// the JSONB round trip does not change the hashed representation.
+type Metadata map[string]string
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (m *Metadata) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*m = Metadata{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return errors.New("unsupported metadata type")
+	}
+	return json.Unmarshal(raw, m)
+}
@@ -0,0 +1,75 @@
+package audit
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Filter drives the admin listing, newest events first. Results are always
+// restricted to OrganizationID.
+type Filter struct {
+	OrganizationID uuid.UUID
+	ActorID        *uuid.UUID
+	TargetID       *uuid.UUID
+	Actions        []string
+	Outcome        string
+	RequestID      string
+	From           *time.Time
+	To             *time.Time
+	Limit          int
+	// BeforeSequence is the sequence of the last event of the previous page.
+	BeforeSequence int64
+}
+
+type Page struct {
+	Events     []Event
+	NextCursor string
+}
+
+func EncodeCursor(sequence int64) string {
+	return strconv.FormatInt(sequence, 10)
+}
+
+func DecodeCursor(value string) (int64, error) {
+	sequence, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || sequence <= 0 {
+		return 0, ErrInvalidCursor
+	}
+	return sequence, nil
+}
+
+const (
+	ProblemGap        = "gap"
+	ProblemBrokenLink = "broken_link"
+	ProblemModified   = "modified"
+	ProblemAnchor     = "anchor_mismatch"
+)
+
+// Anchor is the last sequence and hash of a previous verification, which must
+// still be present and unchanged.
+type Anchor struct {
+	Sequence int64
+	Hash     string
+}
+
+// Problem is an inconsistency found while verifying the chain.
+type Problem struct {
+	Sequence int64  `json:"sequence"`
+	Kind     string `json:"kind"`
+	Detail   string `json:"detail"`
+}
+
+// VerifyReport is the result of walking the whole chain. Removing events from
+// the end of the log cannot be detected from the log alone, so LastSequence
+// and LastHash should be kept elsewhere and given as the Anchor of later runs.
+type VerifyReport struct {
+	Valid        bool      `json:"valid"`
+	Checked      int64     `json:"checked"`
+	LastSequence int64     `json:"last_sequence"`
+	LastHash     string    `json:"last_hash"`
+	Problems     []Problem `json:"problems"`
+	Truncated    bool      `json:"truncated,omitempty"`
+	Redacted     int64     `json:"redacted"`
+}
@@ -0,0 +1,22 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IRepository interface {
+	// Append assigns the next sequence and the chain hashes to the event and
+	// stores it. Appends are serialized so the chain never forks.
+	Append(ctx context.Context, e *Event) error
+	List(ctx context.Context, filter Filter) ([]Event, error)
+	// Walk calls fn for every event in sequence order.
+	Walk(ctx context.Context, fn func(e *Event) error) error
+	// ListForUser returns every event of which the user is the actor or the
+	// target, in sequence order.
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]Event, error)
+	// Redact erases the personal data of the events of the user, including
+	// failed logins with the user's e-mail in the organization.
+	Redact(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, email string) (int64, error)
+}
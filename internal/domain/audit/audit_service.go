@@ -0,0 +1,195 @@
+package audit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize     = 50
+	maxReportedProblems = 100
+	maxUserAgentLength  = 512
+	maxRequestIDLength  = 64
+)
+
+type IService interface {
+	// Record appends the event, filling in the request, actor and tenant from
+	// the context. Failures are logged and never fail the audited action.
+	Record(ctx context.Context, e Event)
+	List(ctx context.Context, filter Filter) (*Page, error)
+	Verify(ctx context.Context, anchor *Anchor) (*VerifyReport, error)
+	ForUser(ctx context.Context, userID uuid.UUID) ([]Event, error)
+	Redact(ctx context.Context, userID uuid.UUID, email string) (int64, error)
+}
+
+type auditService struct {
+	repo IRepository
+}
+
+func NewAuditService(repo IRepository) IService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) Record(ctx context.Context, e Event) {
+	request := RequestFrom(ctx)
+	e.ID = uuid.New()
+	e.IPAddress = request.IPAddress
+	e.UserAgent = truncate(request.UserAgent, maxUserAgentLength)
+	e.RequestID = truncate(request.RequestID, maxRequestIDLength)
+	e.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+
+	if e.OrganizationID == nil {
+		organizationID := tenant.OrganizationID(ctx)
+		e.OrganizationID = &organizationID
+	}
+	if e.ActorID == nil {
+		if actorID, ok := ActorFrom(ctx); ok {
+			e.ActorID = &actorID
+		}
+	}
+	if e.Metadata == nil {
+		e.Metadata = Metadata{}
+	}
+	if err := e.Seal(); err != nil {
+		log.Printf("[ERROR] Failed to record audit event %s (%s): %v", e.Action, e.Outcome, err)
+		return
+	}
+
+	// The event is stored even when the request was canceled right after the
+	// audited action completed.
+	if err := s.repo.Append(context.WithoutCancel(ctx), &e); err != nil {
+		log.Printf("[ERROR] Failed to record audit event %s (%s): %v", e.Action, e.Outcome, err)
+	}
+}
+
+func (s *auditService) List(ctx context.Context, filter Filter) (*Page, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	filter.OrganizationID = tenant.OrganizationID(ctx)
+
+	// One extra row tells whether there is a next page.
+	pageSize := filter.Limit
+	filter.Limit++
+
+	events, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Events: events}
+	if len(events) > pageSize {
+		page.Events = events[:pageSize]
+		page.NextCursor = EncodeCursor(page.Events[pageSize-1].Sequence)
+	}
+	return page, nil
+}
+
+// Verify walks the chain from the first event and reports sequence gaps,
+// events whose prev_hash does not match the previous event, and events whose
+// stored hash no longer matches their content. The personal data of redacted
+// events is gone, so only the rest of the event is checked against the hash;
+// redacted events recorded before the personal data commitment can only be
+// checked for their place in the chain. When an anchor is given, its event
+// must still exist with the same hash.
+func (s *auditService) Verify(ctx context.Context, anchor *Anchor) (*VerifyReport, error) {
+	report := &VerifyReport{Problems: []Problem{}}
+	expected := int64(1)
+	prevHash := GenesisHash
+	anchorFound := false
+	sealed := false
+
+	addProblem := func(p Problem) {
+		if len(report.Problems) >= maxReportedProblems {
+			report.Truncated = true
+			return
+		}
+		report.Problems = append(report.Problems, p)
+	}
+
+	err := s.repo.Walk(ctx, func(e *Event) error {
+		report.Checked++
+
+		if e.Sequence != expected {
+			addProblem(Problem{
+				Sequence: e.Sequence,
+				Kind:     ProblemGap,
+				Detail:   fmt.Sprintf("expected sequence %d", expected),
+			})
+		}
+		if e.PrevHash != prevHash {
+			addProblem(Problem{
+				Sequence: e.Sequence,
+				Kind:     ProblemBrokenLink,
+				Detail:   "prev_hash does not match the hash of the previous event",
+			})
+		}
+		if e.RedactedAt != nil {
+			report.Redacted++
+		}
+		// Every event after the first one with a commitment has one.
+		if e.PIIHash != "" {
+			sealed = true
+		} else if sealed {
+			addProblem(Problem{
+				Sequence: e.Sequence,
+				Kind:     ProblemModified,
+				Detail:   "personal data commitment is missing",
+			})
+		}
+		legacyRedacted := e.RedactedAt != nil && e.PIIHash == ""
+		personalDataChanged := e.RedactedAt == nil && e.PIIHash != "" && e.ComputePIIHash() != e.PIIHash
+		if !legacyRedacted && (e.ComputeHash() != e.Hash || personalDataChanged) {
+			addProblem(Problem{
+				Sequence: e.Sequence,
+				Kind:     ProblemModified,
+				Detail:   "stored hash does not match the event content",
+			})
+		}
+
+		if anchor != nil && e.Sequence == anchor.Sequence {
+			anchorFound = anchor.Hash == "" || anchor.Hash == e.Hash
+		}
+
+		expected = e.Sequence + 1
+		prevHash = e.Hash
+		report.LastSequence = e.Sequence
+		report.LastHash = e.Hash
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if anchor != nil && !anchorFound {
+		addProblem(Problem{
+			Sequence: anchor.Sequence,
+			Kind:     ProblemAnchor,
+			Detail:   "event of a previous verification is missing or was changed",
+		})
+	}
+
+	report.Valid = len(report.Problems) == 0 && !report.Truncated
+	return report, nil
+}
+
+func (s *auditService) ForUser(ctx context.Context, userID uuid.UUID) ([]Event, error) {
+	return s.repo.ListForUser(ctx, userID)
+}
+
+func (s *auditService) Redact(ctx context.Context, userID uuid.UUID, email string) (int64, error) {
+	return s.repo.Redact(ctx, tenant.OrganizationID(ctx), userID, email)
+}
+
+func truncate(value string, max int) string {
+	if len(value) <= max {
+		return value
+	}
+	return strings.ToValidUTF8(value[:max], "")
+}
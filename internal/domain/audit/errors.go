@@ -0,0 +1,7 @@
+package audit
+
+import "errors"
+
+var (
+	ErrInvalidCursor = errors.New("invalid pagination cursor")
+)
@@ -0,0 +1,192 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+type adminService struct {
+	repo        user.IRepository
+	cacheRepo   ICacheRepository
+	orgRepo     organization.IRepository
+	auditLog    audit.IService
+	mailer      notification.IMailer
+	cfg         *config.Config
+	directories []directory.IBackend
+}
+
+func NewAdminService(repo user.IRepository, cacheRepo ICacheRepository, orgRepo organization.IRepository, auditLog audit.IService, mailer notification.IMailer, cfg *config.Config, directories []directory.IBackend) user.IAdminService {
+	return &adminService{
+		repo:        repo,
+		cacheRepo:   cacheRepo,
+		orgRepo:     orgRepo,
+		auditLog:    auditLog,
+		mailer:      mailer,
+		cfg:         cfg,
+		directories: directories,
+	}
+}
+
+func (s *adminService) ListUsers(ctx context.Context, filter user.ListFilter) (*user.Page, error) {
+	if filter.SortBy == "" {
+		filter.SortBy = user.SortByCreatedAt
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	if filter.After != nil && (filter.After.SortBy != filter.SortBy || filter.After.Descending != filter.Descending) {
+		return nil, ErrInvalidCursor
+	}
+
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+
+	users, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &user.Page{Users: users}
+	if len(users) > pageSize {
+		page.Users = users[:pageSize]
+		last := page.Users[pageSize-1]
+		page.NextCursor = user.Cursor{
+			SortBy:     filter.SortBy,
+			Descending: filter.Descending,
+			Value:      last.SortValue(filter.SortBy),
+			ID:         last.ID,
+		}.Encode()
+	}
+
+	return page, nil
+}
+
+func (s *adminService) GetUserDetails(ctx context.Context, userID uuid.UUID) (*user.Details, error) {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &user.Details{User: foundUser}
+
+	details.SessionCount, err = s.cacheRepo.CountUserSessions(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	details.FailedLoginAttempts, err = s.cacheRepo.GetFailedLogins(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	remaining, err := s.cacheRepo.GetAccountLock(ctx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+	if remaining > 0 {
+		lockedUntil := time.Now().Add(remaining).UTC()
+		details.Locked = true
+		details.LockedUntil = &lockedUntil
+	}
+
+	return details, nil
+}
+
+func (s *adminService) ForceLogout(ctx context.Context, userID uuid.UUID) error {
+	if err := revokeSessions(ctx, s.repo, s.cacheRepo, userID); err != nil {
+		return err
+	}
+
+	s.record(ctx, userID, audit.ActionForceLogout, nil)
+	return nil
+}
+
+func (s *adminService) SendPasswordReset(ctx context.Context, userID uuid.UUID, revoke bool) error {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	backend, err := userDirectory(ctx, s.orgRepo, s.directories, foundUser)
+	if err != nil {
+		return err
+	}
+	if backend != nil {
+		return ErrPasswordManagedByDirectory
+	}
+
+	resetToken := uuid.New().String()
+	ttl := time.Duration(s.cfg.ResetTokenTTLMinutes) * time.Minute
+	if err := s.cacheRepo.SaveResetToken(ctx, resetSubject(foundUser), resetToken, ttl); err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, notification.Message{
+		To:      foundUser.Email,
+		Subject: "Redefina sua senha",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nUm administrador solicitou a redefinição da senha da sua conta. Defina uma nova senha em:\n%s/reset-password?token=%s\n\nO link expira em %d minutos.",
+			foundUser.Name, s.cfg.AppPublicURL, resetToken, s.cfg.ResetTokenTTLMinutes,
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	if revoke {
+		if err := revokeSessions(ctx, s.repo, s.cacheRepo, userID); err != nil {
+			return err
+		}
+	}
+
+	s.record(ctx, userID, audit.ActionForceReset, audit.Metadata{"sessions_revoked": strconv.FormatBool(revoke)})
+	return nil
+}
+
+func (s *adminService) Unlock(ctx context.Context, userID uuid.UUID) error {
+	// The lockout lives in the cache only; the lookup keeps the action within
+	// the admin's organization.
+	if _, err := s.repo.FindByID(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.cacheRepo.UnlockAccount(ctx, userID.String()); err != nil {
+		return err
+	}
+
+	s.record(ctx, userID, audit.ActionUnlocked, nil)
+	return nil
+}
+
+func (s *adminService) StatusHistory(ctx context.Context, userID uuid.UUID) ([]user.StatusHistoryEntry, error) {
+	if _, err := s.repo.FindByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListStatusHistory(ctx, userID)
+}
+
+func (s *adminService) record(ctx context.Context, userID uuid.UUID, action string, metadata audit.Metadata) {
+	s.auditLog.Record(ctx, audit.Event{
+		TargetID: &userID,
+		Action:   action,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: metadata,
+	})
+}
@@ -5,10 +5,20 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/dpop"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
 	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
 	"github.com/felipedenardo/chameleon-common/pkg/base"
 	"github.com/golang-jwt/jwt/v5"
@@ -16,21 +26,74 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+const restrictedTokenTTL = 15 * time.Minute
+
+// Login methods, recorded in the audit log.
+const (
+	LoginMethodPassword  = "password"
+	LoginMethodMagicLink = "magic_link"
+	LoginMethodEmailOTP  = "password+email_otp"
+	LoginMethodOIDC      = "oidc"
+	LoginMethodLDAP      = "ldap"
+	LoginMethodSAML      = "saml"
+	LoginMethodDevice    = "device"
+)
+
+// loginMethodAMR maps each login method to the amr claim of its tokens. A
+// magic link is an e-mailed one-time secret. Device sign-ins keep the amr of
+// the session that approved them.
+var loginMethodAMR = map[string][]string{
+	LoginMethodPassword:  {AMRPassword},
+	LoginMethodMagicLink: {AMROTP},
+	LoginMethodEmailOTP:  {AMRPassword, AMROTP},
+	LoginMethodOIDC:      {AMRFederated},
+	LoginMethodLDAP:      {AMRPassword},
+	LoginMethodSAML:      {AMRFederated},
+}
+
 type authService struct {
 	repo      user.IRepository
 	cacheRepo ICacheRepository
+	rbacRepo  rbac.IRepository
+	orgRepo   organization.IRepository
+	auditLog  audit.IService
+	history   loginhistory.IService
+	mailer    notification.IMailer
 	cfg       *config.Config
+
+	identities  federation.IRepository
+	directories []directory.IBackend
+}
+
+func NewAuthService(repo user.IRepository, cacheRepo ICacheRepository, rbacRepo rbac.IRepository, orgRepo organization.IRepository, auditLog audit.IService, history loginhistory.IService, mailer notification.IMailer, cfg *config.Config, identities federation.IRepository, directories []directory.IBackend) user.IService {
+	return newAuthService(repo, cacheRepo, rbacRepo, orgRepo, auditLog, history, mailer, cfg, identities, directories)
 }
 
-func NewAuthService(repo user.IRepository, cacheRepo ICacheRepository, cfg *config.Config) user.IService {
+func newAuthService(repo user.IRepository, cacheRepo ICacheRepository, rbacRepo rbac.IRepository, orgRepo organization.IRepository, auditLog audit.IService, history loginhistory.IService, mailer notification.IMailer, cfg *config.Config, identities federation.IRepository, directories []directory.IBackend) *authService {
 	return &authService{
-		repo:      repo,
-		cacheRepo: cacheRepo,
-		cfg:       cfg,
+		repo:        repo,
+		cacheRepo:   cacheRepo,
+		rbacRepo:    rbacRepo,
+		orgRepo:     orgRepo,
+		auditLog:    auditLog,
+		history:     history,
+		mailer:      mailer,
+		cfg:         cfg,
+		identities:  identities,
+		directories: directories,
 	}
 }
 
-func (s *authService) Register(ctx context.Context, name, email, password string) (*user.User, error) {
+func (s *authService) Register(ctx context.Context, organizationSlug, name, email, password string) (*user.User, error) {
+	org, err := s.resolveOrganization(ctx, organizationSlug)
+	if err != nil {
+		return nil, err
+	}
+	if !org.AllowSelfSignup {
+		return nil, ErrSelfSignupDisabled
+	}
+	ctx = tenant.WithOrganization(ctx, org.ID)
+
 	email = normalizeEmail(email)
 	existing, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
@@ -45,56 +108,143 @@ func (s *authService) Register(ctx context.Context, name, email, password string
 		return nil, err
 	}
 
+	now := time.Now()
 	newUser := &user.User{
 		Model: base.Model{
 			ID: uuid.New(),
 		},
-		Name:         name,
-		Email:        email,
-		PasswordHash: string(hash),
-		Role:         user.RoleUser,
-		Status:       user.StatusActive,
+		Name:              name,
+		Email:             email,
+		PasswordHash:      string(hash),
+		Role:              user.RoleUser,
+		Status:            user.StatusActive,
+		PasswordChangedAt: &now,
 	}
 
 	if err := s.repo.Create(ctx, newUser); err != nil {
 		return nil, err
 	}
 
+	if err := s.rbacRepo.AddUserRole(ctx, newUser.ID, rbac.RoleUser); err != nil {
+		return nil, err
+	}
+
+	if err := s.orgRepo.AddMember(ctx, org.ID, newUser.ID, organization.RoleMember); err != nil {
+		return nil, err
+	}
+
 	return newUser, nil
 }
 
-func (s *authService) Login(ctx context.Context, email, password string) (string, string, *user.User, error) {
+func (s *authService) Login(ctx context.Context, organizationSlug, email, password string) (*user.LoginResult, error) {
+	org, err := s.resolveOrganization(ctx, organizationSlug)
+	if err != nil {
+		if errors.Is(err, organization.ErrOrganizationNotFound) {
+			s.recordLoginFailure(ctx, nil, email, "unknown_organization")
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	ctx = tenant.WithOrganization(ctx, org.ID)
+
 	email = normalizeEmail(email)
+	if backend := s.directoryFor(org, email); backend != nil {
+		return s.loginWithDirectory(ctx, backend, email, password)
+	}
+
 	foundUser, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
-		return "", "", nil, err
+		return nil, err
 	}
 
 	if foundUser == nil {
-		return "", "", nil, ErrInvalidCredentials
+		s.recordLoginFailure(ctx, nil, email, "unknown_user")
+		return nil, ErrInvalidCredentials
 	}
 
-	if foundUser.Status != "active" {
-		return "", "", nil, ErrAccountInactive
+	if err := s.checkAccountLock(ctx, foundUser); err != nil {
+		if errors.Is(err, ErrAccountLocked) {
+			s.recordLoginFailure(ctx, foundUser, email, "locked")
+		}
+		return nil, err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(foundUser.PasswordHash), []byte(password)); err != nil {
-		return "", "", nil, ErrInvalidCredentials
+	if err := comparePassword(foundUser.PasswordHash, password); err != nil {
+		s.recordLoginFailure(ctx, foundUser, email, "invalid_password")
+		s.registerFailedLogin(ctx, foundUser)
+		return nil, ErrInvalidCredentials
 	}
 
-	accessToken, err := s.createAccessToken(foundUser)
-	if err != nil {
-		return "", "", nil, err
+	if foundUser.EmailOTPEnabled {
+		return s.startLoginChallenge(ctx, foundUser)
 	}
 
-	refreshToken, err := s.createRefreshToken(foundUser)
-	if err != nil {
-		return "", "", nil, err
+	return s.completeLogin(ctx, foundUser, LoginMethodPassword)
+}
+
+// completeLogin issues the tokens of a user whose identity method already
+// verified, once the account status allows signing in.
+func (s *authService) completeLogin(ctx context.Context, foundUser *user.User, method string) (*user.LoginResult, error) {
+	return s.completeLoginWithContext(ctx, foundUser, method, newAuthContext(loginMethodAMR[method]...))
+}
+
+// completeLoginWithContext is completeLogin for sign-ins that carry the auth
+// context of an earlier authentication.
+func (s *authService) completeLoginWithContext(ctx context.Context, foundUser *user.User, method string, authn AuthContext) (*user.LoginResult, error) {
+	if err := s.checkAccountStatus(ctx, foundUser); err != nil {
+		var statusErr *AccountStatusError
+		if errors.As(err, &statusErr) {
+			s.recordLoginFailure(ctx, foundUser, foundUser.Email, string(foundUser.Status))
+		}
+		return nil, err
+	}
+	// Directory logins have just bound to the directory.
+	if method != LoginMethodLDAP {
+		if err := s.checkDirectoryAccount(ctx, foundUser); err != nil {
+			if errors.As(err, new(*AccountStatusError)) {
+				s.recordLoginFailure(ctx, foundUser, foundUser.Email, "directory_disabled")
+			}
+			return nil, err
+		}
 	}
 
-	refreshTTL := time.Duration(s.cfg.RefreshTokenTTLDays) * 24 * time.Hour
-	if err := s.cacheRepo.SaveRefreshToken(ctx, foundUser.ID.String(), refreshToken, refreshTTL); err != nil {
-		return "", "", nil, err
+	if err := s.cacheRepo.ClearFailedLogins(ctx, foundUser.ID.String()); err != nil {
+		log.Printf("[ERROR] Failed to clear failed logins for user %s: %v", foundUser.ID, err)
+	}
+
+	result := &user.LoginResult{User: foundUser}
+
+	if foundUser.DeletionScheduledFor != nil {
+		if err := s.repo.CancelDeletion(ctx, foundUser.ID); err != nil {
+			return nil, err
+		}
+		foundUser.DeletionScheduledFor = nil
+		result.DeletionCancelled = true
+		s.recordUserEvent(ctx, foundUser.ID, audit.ActionDeletionCancelled, audit.OutcomeSuccess, nil)
+	}
+
+	var err error
+	if s.passwordChangeRequired(foundUser) {
+		result.PasswordChangeRequired = true
+		result.AccessToken, err = s.createRestrictedAccessToken(foundUser)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		result.AccessToken, err = s.createAccessToken(ctx, foundUser, authn)
+		if err != nil {
+			return nil, err
+		}
+
+		result.RefreshToken, err = s.createRefreshToken(ctx, foundUser, authn)
+		if err != nil {
+			return nil, err
+		}
+
+		refreshTTL := time.Duration(s.cfg.RefreshTokenTTLDays) * 24 * time.Hour
+		if err := s.cacheRepo.SaveRefreshToken(ctx, foundUser.ID.String(), result.RefreshToken, refreshTTL); err != nil {
+			return nil, err
+		}
 	}
 
 	updateCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
@@ -103,7 +253,19 @@ func (s *authService) Login(ctx context.Context, email, password string) (string
 		log.Printf("[ERROR] Failed to update last_login_at for user %s: %v", foundUser.ID.String(), err)
 	}
 
-	return accessToken, refreshToken, foundUser, nil
+	s.history.RecordSuccess(ctx, foundUser)
+	s.auditLog.Record(ctx, audit.Event{
+		ActorID:  &foundUser.ID,
+		TargetID: &foundUser.ID,
+		Action:   audit.ActionLogin,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: audit.Metadata{
+			"method":                   method,
+			"password_change_required": strconv.FormatBool(result.PasswordChangeRequired),
+		},
+	})
+
+	return result, nil
 }
 
 func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword string, newPassword string, tokenString string) error {
@@ -112,10 +274,19 @@ func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, curr
 		return err
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(foundUser.PasswordHash), []byte(currentPassword)); err != nil {
+	backend, err := s.userDirectory(ctx, foundUser)
+	if err != nil {
+		return err
+	}
+	if backend != nil {
+		return ErrPasswordManagedByDirectory
+	}
+
+	if err := comparePassword(foundUser.PasswordHash, currentPassword); err != nil {
+		s.recordUserEvent(ctx, userID, audit.ActionPasswordChanged, audit.OutcomeFailure, audit.Metadata{"reason": "invalid_current_password"})
 		return ErrInvalidCurrentPassword
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(foundUser.PasswordHash), []byte(newPassword)); err == nil {
+	if err := comparePassword(foundUser.PasswordHash, newPassword); err == nil {
 		return ErrSamePassword
 	}
 
@@ -128,10 +299,11 @@ func (s *authService) ChangePassword(ctx context.Context, userID uuid.UUID, curr
 	if err != nil {
 		return err
 	}
+	s.recordUserEvent(ctx, userID, audit.ActionPasswordChanged, audit.OutcomeSuccess, nil)
 
-	err = s.repo.IncrementTokenVersion(ctx, userID)
+	err = revokeSessions(ctx, s.repo, s.cacheRepo, userID)
 	if err != nil {
-		log.Printf("[ERROR] Failed to increment token version for user %s: %v", userID, err)
+		log.Printf("[ERROR] Failed to revoke sessions of user %s: %v", userID, err)
 		// Non-blocking error, but should be logged.
 	}
 
@@ -180,13 +352,24 @@ func (s *authService) Logout(ctx context.Context, tokenString string, refreshTok
 }
 
 func (s *authService) LogoutAll(ctx context.Context, userID uuid.UUID, tokenString string) error {
-	if err := s.repo.IncrementTokenVersion(ctx, userID); err != nil {
+	if err := revokeSessions(ctx, s.repo, s.cacheRepo, userID); err != nil {
 		return err
 	}
+	s.recordUserEvent(ctx, userID, audit.ActionLogoutAll, audit.OutcomeSuccess, nil)
 	return s.invalidateToken(ctx, tokenString)
 }
 
-func (s *authService) ForgotPassword(ctx context.Context, email string) (string, error) {
+func (s *authService) ForgotPassword(ctx context.Context, organizationSlug, email string) (string, error) {
+	org, err := s.resolveOrganization(ctx, organizationSlug)
+	if err != nil {
+		if errors.Is(err, organization.ErrOrganizationNotFound) {
+			log.Printf("[INFO] Password recovery requested for unknown organization: %s", organizationSlug)
+			return "", nil
+		}
+		return "", err
+	}
+	ctx = tenant.WithOrganization(ctx, org.ID)
+
 	email = normalizeEmail(email)
 	foundUser, err := s.repo.FindByEmail(ctx, email)
 	if err != nil {
@@ -196,11 +379,15 @@ func (s *authService) ForgotPassword(ctx context.Context, email string) (string,
 		log.Printf("[INFO] Password recovery requested for non-existent email: %s", email)
 		return "", nil
 	}
+	if s.directoryFor(org, email) != nil {
+		log.Printf("[INFO] Password recovery requested for directory-managed email: %s", email)
+		return "", nil
+	}
 
 	resetToken := uuid.New().String()
 	ttl := time.Duration(s.cfg.ResetTokenTTLMinutes) * time.Minute
 
-	err = s.cacheRepo.SaveResetToken(ctx, foundUser.ID.String(), resetToken, ttl)
+	err = s.cacheRepo.SaveResetToken(ctx, resetSubject(foundUser), resetToken, ttl)
 	if err != nil {
 		log.Printf("[ERROR] Failed to save reset token to cache for user %s: %v", foundUser.ID, err)
 		return "", errors.New("internal error during token generation")
@@ -210,27 +397,50 @@ func (s *authService) ForgotPassword(ctx context.Context, email string) (string,
 }
 
 func (s *authService) ResetPassword(ctx context.Context, resetToken string, newPassword string) error {
-	userIDString, err := s.cacheRepo.VerifyAndConsumeResetToken(ctx, resetToken)
+	subject, err := s.cacheRepo.VerifyAndConsumeResetToken(ctx, resetToken)
 	if err != nil {
 		return ErrInvalidResetToken
 	}
 
-	userID, err := uuid.Parse(userIDString)
+	organizationID, userID, err := parseResetSubject(subject)
 	if err != nil {
 		return ErrInvalidUserID
 	}
+	ctx = tenant.WithOrganization(ctx, organizationID)
+
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	backend, err := s.userDirectory(ctx, foundUser)
+	if err != nil {
+		return err
+	}
+	if backend != nil {
+		return ErrPasswordManagedByDirectory
+	}
 
 	newHash, err := bcrypt.GenerateFromPassword([]byte(newPassword), s.cfg.BcryptCost)
 	if err != nil {
 		return err
 	}
 
-	err = s.repo.IncrementTokenVersion(ctx, userID)
+	err = revokeSessions(ctx, s.repo, s.cacheRepo, userID)
 	if err != nil {
-		log.Printf("[ERROR] Failed to increment token version for user %s: %v", userID, err)
+		log.Printf("[ERROR] Failed to revoke sessions of user %s: %v", userID, err)
+	}
+
+	if err := s.repo.UpdatePasswordHash(ctx, userID, string(newHash)); err != nil {
+		return err
 	}
 
-	return s.repo.UpdatePasswordHash(ctx, userID, string(newHash))
+	s.auditLog.Record(ctx, audit.Event{
+		ActorID:  &userID,
+		TargetID: &userID,
+		Action:   audit.ActionPasswordReset,
+		Outcome:  audit.OutcomeSuccess,
+	})
+	return nil
 }
 
 func (s *authService) DeactivateSelf(ctx context.Context, userID uuid.UUID, currentPassword string, tokenString string) error {
@@ -242,18 +452,26 @@ func (s *authService) DeactivateSelf(ctx context.Context, userID uuid.UUID, curr
 		return errors.New("user not found")
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(foundUser.PasswordHash), []byte(currentPassword)); err != nil {
-		return ErrInvalidCurrentPassword
+	if err := s.verifyPassword(ctx, foundUser, currentPassword); err != nil {
+		if errors.Is(err, ErrInvalidCurrentPassword) {
+			s.recordUserEvent(ctx, userID, audit.ActionDeactivated, audit.OutcomeFailure, audit.Metadata{"reason": "invalid_current_password"})
+		}
+		return err
 	}
 
-	err = s.repo.UpdateStatus(ctx, userID, string(user.StatusInactive))
+	err = s.repo.ChangeStatus(ctx, userID, user.StatusChange{
+		Status:    user.StatusInactive,
+		Source:    user.SourceSelf,
+		ChangedBy: &userID,
+	})
 	if err != nil {
 		return err
 	}
+	s.recordUserEvent(ctx, userID, audit.ActionDeactivated, audit.OutcomeSuccess, nil)
 
-	err = s.repo.IncrementTokenVersion(ctx, userID)
+	err = revokeSessions(ctx, s.repo, s.cacheRepo, userID)
 	if err != nil {
-		log.Printf("[ERROR] Failed to increment token version for user %s: %v", userID, err)
+		log.Printf("[ERROR] Failed to revoke sessions of user %s: %v", userID, err)
 	}
 
 	err = s.invalidateToken(ctx, tokenString)
@@ -264,8 +482,123 @@ func (s *authService) DeactivateSelf(ctx context.Context, userID uuid.UUID, curr
 	return err
 }
 
-func (s *authService) UpdateUserStatus(ctx context.Context, userID uuid.UUID, status user.Status) error {
-	return s.repo.UpdateStatus(ctx, userID, string(status))
+func (s *authService) RequestDeletion(ctx context.Context, userID uuid.UUID, currentPassword string) (time.Time, error) {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if err := s.verifyPassword(ctx, foundUser, currentPassword); err != nil {
+		if errors.Is(err, ErrInvalidCurrentPassword) {
+			s.recordUserEvent(ctx, userID, audit.ActionDeletionRequested, audit.OutcomeFailure, audit.Metadata{"reason": "invalid_current_password"})
+		}
+		return time.Time{}, err
+	}
+
+	scheduledFor := time.Now().UTC().Add(time.Duration(s.cfg.AccountDeletionGraceDays) * 24 * time.Hour)
+	if err := s.repo.ScheduleDeletion(ctx, userID, scheduledFor); err != nil {
+		return time.Time{}, err
+	}
+	s.recordUserEvent(ctx, userID, audit.ActionDeletionRequested, audit.OutcomeSuccess, audit.Metadata{
+		"scheduled_for": scheduledFor.Format(time.RFC3339),
+	})
+
+	if err := revokeSessions(ctx, s.repo, s.cacheRepo, userID); err != nil {
+		log.Printf("[ERROR] Failed to revoke sessions of user %s: %v", userID, err)
+	}
+	return scheduledFor, nil
+}
+
+// UpdateUserStatus always revokes the sessions of the user, so a deactivated
+// user's tokens stop working immediately and a reactivated user starts fresh.
+func (s *authService) UpdateUserStatus(ctx context.Context, userID uuid.UUID, change user.StatusChange) error {
+	switch change.Status {
+	case user.StatusSuspended:
+		if change.SuspendedUntil == nil || !change.SuspendedUntil.After(time.Now()) {
+			return ErrInvalidSuspension
+		}
+	case user.StatusBanned:
+		if strings.TrimSpace(change.Reason) == "" {
+			return ErrStatusReasonRequired
+		}
+	}
+	if change.Status != user.StatusSuspended {
+		change.SuspendedUntil = nil
+	}
+	change.Source = user.SourceAdmin
+
+	if err := s.repo.ChangeStatus(ctx, userID, change); err != nil {
+		return err
+	}
+	if err := revokeSessions(ctx, s.repo, s.cacheRepo, userID); err != nil {
+		return err
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		TargetID: &userID,
+		Action:   audit.ActionStatusChanged,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: statusChangeMetadata(change),
+	})
+	return nil
+}
+
+func statusChangeMetadata(change user.StatusChange) audit.Metadata {
+	metadata := audit.Metadata{"status": string(change.Status)}
+	if change.Reason != "" {
+		metadata["reason"] = change.Reason
+	}
+	if change.SuspendedUntil != nil {
+		metadata["suspended_until"] = change.SuspendedUntil.UTC().Format(time.RFC3339)
+	}
+	return metadata
+}
+
+// checkAccountStatus is called after the password was verified. An expired
+// suspension is lifted here so the user does not wait for the next sweep.
+func (s *authService) checkAccountStatus(ctx context.Context, u *user.User) error {
+	if u.SuspensionExpired(time.Now()) {
+		err := s.repo.ChangeStatus(ctx, u.ID, user.StatusChange{
+			Status: user.StatusActive,
+			Source: user.SourceSystem,
+			Reason: user.SuspensionExpiredReason,
+		})
+		if err != nil {
+			return err
+		}
+		u.Status = user.StatusActive
+		u.SuspendedUntil = nil
+		u.StatusReason = nil
+	}
+
+	switch u.Status {
+	case user.StatusActive:
+		return nil
+	case user.StatusSuspended:
+		return &AccountStatusError{Err: ErrAccountSuspended, SuspendedUntil: u.SuspendedUntil}
+	case user.StatusBanned:
+		return &AccountStatusError{Err: ErrAccountBanned}
+	case user.StatusPendingVerification:
+		return &AccountStatusError{Err: ErrAccountPendingVerification}
+	default:
+		return &AccountStatusError{Err: ErrAccountInactive, Reactivatable: u.SelfDeactivated()}
+	}
+}
+
+func (s *authService) ForcePasswordChange(ctx context.Context, userID uuid.UUID) error {
+	if err := s.repo.SetMustChangePassword(ctx, userID, true); err != nil {
+		return err
+	}
+	if err := revokeSessions(ctx, s.repo, s.cacheRepo, userID); err != nil {
+		return err
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		TargetID: &userID,
+		Action:   audit.ActionForceChange,
+		Outcome:  audit.OutcomeSuccess,
+	})
+	return nil
 }
 
 func (s *authService) Refresh(ctx context.Context, refreshToken string) (string, string, *user.User, error) {
@@ -288,6 +621,11 @@ func (s *authService) Refresh(ctx context.Context, refreshToken string) (string,
 		return "", "", nil, ErrInvalidRefreshToken
 	}
 
+	// A refresh token bound to a key is only accepted with a proof of it.
+	if jkt := boundThumbprint(claims); jkt != "" && jkt != dpop.Thumbprint(ctx) {
+		return "", "", nil, ErrInvalidRefreshToken
+	}
+
 	cacheUserID, err := s.cacheRepo.VerifyAndConsumeRefreshToken(ctx, refreshToken)
 	if err != nil || cacheUserID != userID {
 		return "", "", nil, ErrInvalidRefreshToken
@@ -298,6 +636,12 @@ func (s *authService) Refresh(ctx context.Context, refreshToken string) (string,
 		return "", "", nil, ErrInvalidRefreshToken
 	}
 
+	organizationID, err := organizationFromClaims(claims)
+	if err != nil {
+		return "", "", nil, ErrInvalidRefreshToken
+	}
+	ctx = tenant.WithOrganization(ctx, organizationID)
+
 	foundUser, err := s.repo.FindByID(ctx, parsedUserID)
 	if err != nil {
 		return "", "", nil, err
@@ -306,18 +650,29 @@ func (s *authService) Refresh(ctx context.Context, refreshToken string) (string,
 	if foundUser.Status != user.StatusActive {
 		return "", "", nil, ErrAccountInactive
 	}
+	if err := s.checkDirectoryAccount(ctx, foundUser); err != nil {
+		if errors.As(err, new(*AccountStatusError)) {
+			return "", "", nil, ErrAccountInactive
+		}
+		return "", "", nil, err
+	}
+
+	if s.passwordChangeRequired(foundUser) {
+		return "", "", nil, ErrPasswordChangeRequired
+	}
 
 	tokenVersionClaim, _ := claims["token_version"].(float64)
 	if int(tokenVersionClaim) != foundUser.TokenVersion {
 		return "", "", nil, ErrInvalidRefreshToken
 	}
 
-	accessToken, err := s.createAccessToken(foundUser)
+	authn := authContextFromClaims(claims)
+	accessToken, err := s.createAccessToken(ctx, foundUser, authn)
 	if err != nil {
 		return "", "", nil, err
 	}
 
-	newRefreshToken, err := s.createRefreshToken(foundUser)
+	newRefreshToken, err := s.createRefreshToken(ctx, foundUser, authn)
 	if err != nil {
 		return "", "", nil, err
 	}
@@ -352,25 +707,82 @@ func (s *authService) invalidateToken(ctx context.Context, tokenString string) e
 	return nil
 }
 
-func (s *authService) createAccessToken(u *user.User) (string, error) {
+func (s *authService) createAccessToken(ctx context.Context, u *user.User, authn AuthContext) (string, error) {
+	claims, err := s.accessTokenClaims(ctx, u, time.Duration(s.cfg.TokenTTLHours)*time.Hour)
+	if err != nil {
+		return "", err
+	}
+	authn.apply(claims)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(s.cfg.JWTSecret))
+}
+
+func (s *authService) accessTokenClaims(ctx context.Context, u *user.User, ttl time.Duration) (jwt.MapClaims, error) {
+	roleNames, permissions, err := s.userPermissions(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
 	claims := jwt.MapClaims{
 		"sub":           u.ID.String(),
+		"org_id":        u.OrganizationID.String(),
 		"role":          u.Role,
+		"roles":         roleNames,
+		"permissions":   permissions,
 		"name":          u.Name,
 		"token_version": u.TokenVersion,
-		"exp":           time.Now().Add(time.Duration(s.cfg.TokenTTLHours) * time.Hour).Unix(),
+		"exp":           time.Now().Add(ttl).Unix(),
 		"jti":           uuid.New().String(),
 		"typ":           "access",
 		"iss":           s.cfg.JWTIssuer,
 		"aud":           s.cfg.JWTAudience,
 	}
+	bindToKey(ctx, claims)
+	return claims, nil
+}
+
+// userPermissions returns the role names and the flattened permissions of
+// the user: the global roles plus the role in the user's organization.
+func (s *authService) userPermissions(ctx context.Context, u *user.User) ([]string, []string, error) {
+	roles, err := s.rbacRepo.GetUserRoles(ctx, u.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	memberRole, err := s.orgRepo.FindMemberRole(ctx, u.OrganizationID, u.ID)
+	if err != nil && !errors.Is(err, organization.ErrMemberNotFound) {
+		return nil, nil, err
+	}
+	if memberRole != nil {
+		roles = append(roles, *memberRole)
+	}
+	roleNames, permissions := flattenRoles(roles)
+	return roleNames, permissions, nil
+}
+
+// createRestrictedAccessToken issues a short-lived token flagged with
+// pwd_change_required, which only the change-password endpoint accepts.
+func (s *authService) createRestrictedAccessToken(u *user.User) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":                 u.ID.String(),
+		"org_id":              u.OrganizationID.String(),
+		"role":                u.Role,
+		"name":                u.Name,
+		"token_version":       u.TokenVersion,
+		"pwd_change_required": true,
+		"exp":                 time.Now().Add(restrictedTokenTTL).Unix(),
+		"jti":                 uuid.New().String(),
+		"typ":                 "access",
+		"iss":                 s.cfg.JWTIssuer,
+		"aud":                 s.cfg.JWTAudience,
+	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.cfg.JWTSecret))
 }
 
-func (s *authService) createRefreshToken(u *user.User) (string, error) {
+func (s *authService) createRefreshToken(ctx context.Context, u *user.User, authn AuthContext) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":           u.ID.String(),
+		"org_id":        u.OrganizationID.String(),
 		"token_version": u.TokenVersion,
 		"exp":           time.Now().Add(time.Duration(s.cfg.RefreshTokenTTLDays) * 24 * time.Hour).Unix(),
 		"jti":           uuid.New().String(),
@@ -378,10 +790,28 @@ func (s *authService) createRefreshToken(u *user.User) (string, error) {
 		"iss":           s.cfg.JWTIssuer,
 		"aud":           s.cfg.JWTAudience,
 	}
+	authn.apply(claims)
+	bindToKey(ctx, claims)
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	return token.SignedString([]byte(s.cfg.JWTSecret))
 }
 
+// bindToKey adds the confirmation claim (RFC 9449, section 6) when the
+// request proved possession of a DPoP key.
+func bindToKey(ctx context.Context, claims jwt.MapClaims) {
+	if jkt := dpop.Thumbprint(ctx); jkt != "" {
+		claims["cnf"] = map[string]interface{}{"jkt": jkt}
+	}
+}
+
+// boundThumbprint returns the thumbprint of the key the token is bound to, or
+// "" for bearer tokens.
+func boundThumbprint(claims jwt.MapClaims) string {
+	cnf, _ := claims["cnf"].(map[string]interface{})
+	jkt, _ := cnf["jkt"].(string)
+	return jkt
+}
+
 func (s *authService) parseAndValidateToken(tokenString string) (*jwt.Token, error) {
 	token, err := jwt.Parse(tokenString, func(t *jwt.Token) (interface{}, error) {
 		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -398,6 +828,160 @@ func (s *authService) parseAndValidateToken(tokenString string) (*jwt.Token, err
 	return token, nil
 }
 
+func (s *authService) checkAccountLock(ctx context.Context, u *user.User) error {
+	if s.cfg.LoginLockoutMax <= 0 {
+		return nil
+	}
+	remaining, err := s.cacheRepo.GetAccountLock(ctx, u.ID.String())
+	if err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return ErrAccountLocked
+	}
+	return nil
+}
+
+// registerFailedLogin locks the account for LoginLockoutMinutes once
+// LoginLockoutMax consecutive failures happen within that same window.
+func (s *authService) registerFailedLogin(ctx context.Context, u *user.User) {
+	if s.cfg.LoginLockoutMax <= 0 {
+		return
+	}
+
+	window := time.Duration(s.cfg.LoginLockoutMinutes) * time.Minute
+	attempts, err := s.cacheRepo.RegisterFailedLogin(ctx, u.ID.String(), window)
+	if err != nil {
+		log.Printf("[ERROR] Failed to register failed login for user %s: %v", u.ID, err)
+		return
+	}
+	if attempts < s.cfg.LoginLockoutMax {
+		return
+	}
+
+	if err := s.cacheRepo.LockAccount(ctx, u.ID.String(), window); err != nil {
+		log.Printf("[ERROR] Failed to lock account of user %s: %v", u.ID, err)
+		return
+	}
+	if err := s.cacheRepo.ClearFailedLogins(ctx, u.ID.String()); err != nil {
+		log.Printf("[ERROR] Failed to clear failed logins for user %s: %v", u.ID, err)
+	}
+	log.Printf("[WARN] Account of user %s locked after %d failed logins", u.ID, attempts)
+
+	s.auditLog.Record(ctx, audit.Event{
+		TargetID: &u.ID,
+		Action:   audit.ActionAccountLocked,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: audit.Metadata{"attempts": strconv.Itoa(attempts), "window": window.String()},
+	})
+}
+
+// recordUserEvent audits an action the user performed on their own account.
+func (s *authService) recordUserEvent(ctx context.Context, userID uuid.UUID, action string, outcome string, metadata audit.Metadata) {
+	s.auditLog.Record(ctx, audit.Event{
+		ActorID:  &userID,
+		TargetID: &userID,
+		Action:   action,
+		Outcome:  outcome,
+		Metadata: metadata,
+	})
+}
+
+// recordLoginFailure audits a rejected login and adds it to the login history
+// of the user, if any. The submitted e-mail is kept in the audit event since
+// the attempt may not match any user.
+func (s *authService) recordLoginFailure(ctx context.Context, target *user.User, email string, reason string) {
+	event := audit.Event{
+		Action:   audit.ActionLogin,
+		Outcome:  audit.OutcomeFailure,
+		Metadata: audit.Metadata{"email": normalizeEmail(email), "reason": reason},
+	}
+	if target != nil {
+		event.TargetID = &target.ID
+		s.history.RecordFailure(ctx, target, reason)
+	}
+	s.auditLog.Record(ctx, event)
+}
+
+func (s *authService) passwordChangeRequired(u *user.User) bool {
+	maxAge := time.Duration(s.cfg.PasswordMaxAgeDays) * 24 * time.Hour
+	return u.MustChangePassword || u.PasswordExpired(maxAge, time.Now())
+}
+
+// resolveOrganization returns the active organization with the given slug, or
+// the default organization when no slug is given.
+func (s *authService) resolveOrganization(ctx context.Context, slug string) (*organization.Organization, error) {
+	slug = strings.ToLower(strings.TrimSpace(slug))
+
+	var (
+		org *organization.Organization
+		err error
+	)
+	if slug == "" {
+		org, err = s.orgRepo.FindByID(ctx, tenant.DefaultOrganizationID)
+	} else {
+		org, err = s.orgRepo.FindBySlug(ctx, slug)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if org.Status != organization.StatusActive {
+		return nil, organization.ErrOrganizationNotFound
+	}
+	return org, nil
+}
+
+// organizationFromClaims reads the org_id claim. Tokens issued before
+// organizations existed carry none and belong to the default organization.
+func organizationFromClaims(claims jwt.MapClaims) (uuid.UUID, error) {
+	raw, _ := claims["org_id"].(string)
+	if raw == "" {
+		return tenant.DefaultOrganizationID, nil
+	}
+	return uuid.Parse(raw)
+}
+
+// resetSubject identifies the user of a reset token across organizations,
+// since the reset request itself carries no tenant.
+func resetSubject(u *user.User) string {
+	return u.OrganizationID.String() + ":" + u.ID.String()
+}
+
+func parseResetSubject(subject string) (uuid.UUID, uuid.UUID, error) {
+	rawOrg, rawUser, found := strings.Cut(subject, ":")
+	if !found {
+		userID, err := uuid.Parse(subject)
+		return tenant.DefaultOrganizationID, userID, err
+	}
+
+	organizationID, err := uuid.Parse(rawOrg)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	userID, err := uuid.Parse(rawUser)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return organizationID, userID, nil
+}
+
+func flattenRoles(roles []rbac.Role) ([]string, []string) {
+	roleNames := make([]string, 0, len(roles))
+	permissions := []string{}
+	seen := map[string]struct{}{}
+	for _, r := range roles {
+		roleNames = append(roleNames, r.Name)
+		for _, p := range r.Permissions {
+			if _, ok := seen[p.Name]; ok {
+				continue
+			}
+			seen[p.Name] = struct{}{}
+			permissions = append(permissions, p.Name)
+		}
+	}
+	return roleNames, permissions
+}
+
 func normalizeEmail(email string) string {
 	return strings.ToLower(strings.TrimSpace(email))
 }
@@ -0,0 +1,366 @@
+package auth
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultImportBatchSize = 500
+	maxImportBatchSize     = 1000
+	maxImportLineBytes     = 64 * 1024
+	exportFlushEvery       = 500
+)
+
+var (
+	csvImportColumns = []string{"name", "email", "role", "password_hash"}
+	csvExportColumns = []string{"id", "name", "email", "role", "status", "created_at", "last_login_at"}
+)
+
+type bulkService struct {
+	repo     user.IRepository
+	auditLog audit.IService
+}
+
+func NewBulkService(repo user.IRepository, auditLog audit.IService) user.IBulkService {
+	return &bulkService{repo: repo, auditLog: auditLog}
+}
+
+type importRow struct {
+	line   int
+	record user.ImportRecord
+	err    error
+}
+
+// Import reads the whole file, validating each row, and persists the valid
+// rows in batches, each batch in its own transaction. A batch that fails to
+// persist is reported row by row and does not stop the following batches.
+func (s *bulkService) Import(ctx context.Context, r io.Reader, opts user.ImportOptions) (*user.ImportReport, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultImportBatchSize
+	}
+	if batchSize > maxImportBatchSize {
+		batchSize = maxImportBatchSize
+	}
+
+	report := &user.ImportReport{DryRun: opts.DryRun, Errors: []user.ImportRowError{}}
+	seen := map[string]int{}
+	batch := make([]importRow, 0, batchSize)
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := s.importBatch(ctx, batch, opts.DryRun, report)
+		batch = batch[:0]
+		return err
+	}
+
+	err := readImportRows(r, opts.Format, func(row importRow) error {
+		report.Total++
+		if row.err == nil {
+			row.err = validateImportRecord(&row.record)
+		}
+		if row.err == nil {
+			if firstLine, dup := seen[row.record.Email]; dup {
+				row.err = fmt.Errorf("%w (line %d)", ErrDuplicateImportEmail, firstLine)
+			} else {
+				seen[row.record.Email] = row.line
+			}
+		}
+		if row.err != nil {
+			report.Failed++
+			report.Errors = append(report.Errors, user.ImportRowError{Line: row.line, Email: row.record.Email, Error: row.err.Error()})
+			return nil
+		}
+
+		batch = append(batch, row)
+		if len(batch) >= batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	return report, nil
+}
+
+func (s *bulkService) importBatch(ctx context.Context, batch []importRow, dryRun bool, report *user.ImportReport) error {
+	emails := make([]string, 0, len(batch))
+	for _, row := range batch {
+		emails = append(emails, row.record.Email)
+	}
+	existing, err := s.repo.FindExistingEmails(ctx, emails)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	users := make([]user.ImportedUser, 0, len(batch))
+	accepted := make([]importRow, 0, len(batch))
+	for _, row := range batch {
+		if _, taken := existing[row.record.Email]; taken {
+			report.Failed++
+			report.Errors = append(report.Errors, user.ImportRowError{Line: row.line, Email: row.record.Email, Error: ErrEmailAlreadyExists.Error()})
+			continue
+		}
+
+		u := &user.User{
+			Model: base.Model{
+				ID: uuid.New(),
+			},
+			Name:              row.record.Name,
+			Email:             row.record.Email,
+			PasswordHash:      row.record.PasswordHash,
+			Role:              user.RoleUser,
+			Status:            user.StatusActive,
+			PasswordChangedAt: &now,
+		}
+		users = append(users, user.ImportedUser{User: u, OrganizationRole: row.record.Role})
+		accepted = append(accepted, row)
+	}
+
+	if dryRun || len(users) == 0 {
+		report.Imported += len(users)
+		return nil
+	}
+
+	if err := s.repo.ImportBatch(ctx, users); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		for _, row := range accepted {
+			report.Failed++
+			report.Errors = append(report.Errors, user.ImportRowError{Line: row.line, Email: row.record.Email, Error: "batch rolled back: " + err.Error()})
+		}
+		return nil
+	}
+
+	report.Imported += len(users)
+	return nil
+}
+
+// Export streams the users and records the export, with the number of rows
+// written, in the audit log.
+func (s *bulkService) Export(ctx context.Context, w io.Writer, format user.BulkFormat, includePasswordHash bool) error {
+	written, err := s.export(ctx, w, format, includePasswordHash)
+
+	outcome := audit.OutcomeSuccess
+	if err != nil {
+		outcome = audit.OutcomeFailure
+	}
+	s.auditLog.Record(ctx, audit.Event{
+		Action:  audit.ActionUsersExported,
+		Outcome: outcome,
+		Metadata: audit.Metadata{
+			"format":        string(format),
+			"rows":          strconv.Itoa(written),
+			"password_hash": strconv.FormatBool(includePasswordHash),
+		},
+	})
+	return err
+}
+
+func (s *bulkService) export(ctx context.Context, w io.Writer, format user.BulkFormat, includePasswordHash bool) (int, error) {
+	written := 0
+	switch format {
+	case user.FormatJSONL:
+		encoder := json.NewEncoder(w)
+		err := s.repo.StreamExport(ctx, includePasswordHash, func(record user.ExportRecord) error {
+			if err := encoder.Encode(record); err != nil {
+				return err
+			}
+			written++
+			return nil
+		})
+		return written, err
+
+	case user.FormatCSV:
+		writer := csv.NewWriter(w)
+		header := csvExportColumns
+		if includePasswordHash {
+			header = append(append([]string{}, csvExportColumns...), "password_hash")
+		}
+		if err := writer.Write(header); err != nil {
+			return 0, err
+		}
+
+		err := s.repo.StreamExport(ctx, includePasswordHash, func(record user.ExportRecord) error {
+			lastLogin := ""
+			if record.LastLoginAt != nil {
+				lastLogin = record.LastLoginAt.UTC().Format(time.RFC3339)
+			}
+			fields := []string{
+				record.ID.String(),
+				record.Name,
+				record.Email,
+				record.Role,
+				record.Status,
+				record.CreatedAt.UTC().Format(time.RFC3339),
+				lastLogin,
+			}
+			if includePasswordHash {
+				fields = append(fields, record.PasswordHash)
+			}
+			if err := writer.Write(fields); err != nil {
+				return err
+			}
+
+			written++
+			if written%exportFlushEvery == 0 {
+				writer.Flush()
+				return writer.Error()
+			}
+			return nil
+		})
+		if err != nil {
+			return written, err
+		}
+		writer.Flush()
+		return written, writer.Error()
+
+	default:
+		return 0, ErrUnsupportedFormat
+	}
+}
+
+// readImportRows decodes the file and calls fn for every data row. Malformed
+// rows are passed with err set; only unreadable files abort the import.
+func readImportRows(r io.Reader, format user.BulkFormat, fn func(importRow) error) error {
+	switch format {
+	case user.FormatCSV:
+		return readCSVRows(r, fn)
+	case user.FormatJSONL:
+		return readJSONLRows(r, fn)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+func readCSVRows(r io.Reader, fn func(importRow) error) error {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return ErrInvalidImportFile
+	}
+	index := map[string]int{}
+	for i, column := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))] = i
+	}
+	if _, ok := index["name"]; !ok {
+		return ErrInvalidImportFile
+	}
+	if _, ok := index["email"]; !ok {
+		return ErrInvalidImportFile
+	}
+
+	field := func(fields []string, column string) string {
+		i, ok := index[column]
+		if !ok || i >= len(fields) {
+			return ""
+		}
+		return fields[i]
+	}
+
+	for {
+		fields, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		line, _ := reader.FieldPos(0)
+
+		row := importRow{line: line}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) || !errors.Is(parseErr.Err, csv.ErrFieldCount) {
+				return ErrInvalidImportFile
+			}
+			row.line = parseErr.Line
+			row.err = ErrInvalidImportRow
+		}
+		row.record = user.ImportRecord{
+			Name:         field(fields, csvImportColumns[0]),
+			Email:        field(fields, csvImportColumns[1]),
+			Role:         field(fields, csvImportColumns[2]),
+			PasswordHash: field(fields, csvImportColumns[3]),
+		}
+
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+}
+
+func readJSONLRows(r io.Reader, fn func(importRow) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 4096), maxImportLineBytes)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := strings.TrimSpace(scanner.Text())
+		if raw == "" {
+			continue
+		}
+
+		row := importRow{line: line}
+		if err := json.Unmarshal([]byte(raw), &row.record); err != nil {
+			row.err = ErrInvalidImportRow
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ErrInvalidImportFile
+	}
+	return nil
+}
+
+func validateImportRecord(record *user.ImportRecord) error {
+	record.Name = strings.TrimSpace(record.Name)
+	record.Email = normalizeEmail(record.Email)
+	record.Role = strings.ToLower(strings.TrimSpace(record.Role))
+	record.PasswordHash = strings.TrimSpace(record.PasswordHash)
+
+	if n := len([]rune(record.Name)); n < 3 || n > 100 {
+		return ErrInvalidName
+	}
+	if addr, err := mail.ParseAddress(record.Email); err != nil || addr.Address != record.Email {
+		return ErrInvalidEmail
+	}
+	switch record.Role {
+	case "":
+		record.Role = organization.RoleMember
+	case organization.RoleAdmin, organization.RoleMember:
+	default:
+		return organization.ErrInvalidMemberRole
+	}
+	if record.PasswordHash != "" && !isSupportedPasswordHash(record.PasswordHash) {
+		return ErrUnsupportedHash
+	}
+	return nil
+}
@@ -3,8 +3,68 @@ package auth
 import (
 	"context"
 	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/saml"
+	"github.com/google/uuid"
 )
 
+// EmailChangeTicket is the state of a pending e-mail change, stored under both
+// the confirmation token (sent to the new address) and the revert token (sent
+// to the old address).
+type EmailChangeTicket struct {
+	ID             string    `json:"id"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         string    `json:"user_id"`
+	OldEmail       string    `json:"old_email"`
+	NewEmail       string    `json:"new_email"`
+}
+
+// MagicLinkTicket is stored under the hash of a magic link token. NonceHash
+// binds the link to the browser that requested it.
+type MagicLinkTicket struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	NonceHash      string    `json:"nonce_hash"`
+}
+
+// OTPChallenge is a pending e-mail one-time code. CodeHash is keyed by the
+// challenge ID, so a code only matches the challenge it was sent for.
+type OTPChallenge struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	Purpose        string    `json:"purpose"`
+	CodeHash       string    `json:"code_hash"`
+}
+
+// SAMLLoginTicket is stored under the one-time code the assertion consumer
+// service hands to the browser. RelayStateHash binds the code to the browser
+// that started the login.
+type SAMLLoginTicket struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	RelayStateHash string    `json:"relay_state_hash"`
+}
+
+// DeviceAuthorizationTicket is a pending device authorization grant, stored
+// under the device code and found by the user code for the approval.
+type DeviceAuthorizationTicket struct {
+	ClientID  string    `json:"client_id"`
+	UserCode  string    `json:"user_code"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// DeviceDecision is the answer of the user to a device authorization. An
+// approval keeps the auth context of the session that approved it, which the
+// tokens of the device carry, as refreshed tokens do.
+type DeviceDecision struct {
+	Approved       bool      `json:"approved"`
+	OrganizationID uuid.UUID `json:"organization_id"`
+	UserID         uuid.UUID `json:"user_id"`
+	AuthTime       int64     `json:"auth_time,omitempty"`
+	AMR            []string  `json:"amr,omitempty"`
+}
+
 type ICacheRepository interface {
 	BlacklistToken(ctx context.Context, jti string, ttl time.Duration) error
 	IsTokenBlacklisted(ctx context.Context, jti string) (bool, error)
@@ -14,4 +74,54 @@ type ICacheRepository interface {
 	VerifyAndConsumeRefreshToken(ctx context.Context, refreshToken string) (userID string, err error)
 	GetUserTokenVersion(ctx context.Context, key string) (int, error)
 	SetTokenVersion(ctx context.Context, key string, version int, expiration time.Duration) error
+	SaveEmailChangeTicket(ctx context.Context, confirmToken string, revertToken string, ticket EmailChangeTicket, confirmTTL time.Duration, revertTTL time.Duration) error
+	VerifyAndConsumeEmailChangeToken(ctx context.Context, confirmToken string) (*EmailChangeTicket, error)
+	VerifyAndConsumeEmailRevertToken(ctx context.Context, revertToken string) (*EmailChangeTicket, error)
+	ConsumePendingEmailChange(ctx context.Context, userID string) (ticketID string, err error)
+	CountUserSessions(ctx context.Context, userID string) (int64, error)
+	RegisterFailedLogin(ctx context.Context, userID string, window time.Duration) (attempts int, err error)
+	ClearFailedLogins(ctx context.Context, userID string) error
+	GetFailedLogins(ctx context.Context, userID string) (int, error)
+	LockAccount(ctx context.Context, userID string, ttl time.Duration) error
+	GetAccountLock(ctx context.Context, userID string) (remaining time.Duration, err error)
+	UnlockAccount(ctx context.Context, userID string) error
+	RevokeUserSessions(ctx context.Context, userID string) error
+	// PurgeUser removes every key kept for the user: sessions, token
+	// version, lockout state and pending e-mail change.
+	PurgeUser(ctx context.Context, userID string) error
+	SaveReactivationToken(ctx context.Context, subject string, reactivationToken string, ttl time.Duration) error
+	VerifyAndConsumeReactivationToken(ctx context.Context, reactivationToken string) (subject string, err error)
+	SaveMagicLink(ctx context.Context, token string, ticket MagicLinkTicket, ttl time.Duration) error
+	VerifyAndConsumeMagicLink(ctx context.Context, token string) (*MagicLinkTicket, error)
+	SaveOTPChallenge(ctx context.Context, challengeID string, challenge OTPChallenge, ttl time.Duration) error
+	// AttemptOTPChallenge counts one more attempt against the challenge and
+	// returns it with the attempts made so far.
+	AttemptOTPChallenge(ctx context.Context, challengeID string) (*OTPChallenge, int, error)
+	DeleteOTPChallenge(ctx context.Context, challengeID string) error
+	SaveStepUpToken(ctx context.Context, userID string, stepUpToken string, ttl time.Duration) error
+	VerifyAndConsumeStepUpToken(ctx context.Context, stepUpToken string) (userID string, err error)
+	SaveFederationState(ctx context.Context, state string, loginState federation.LoginState, ttl time.Duration) error
+	ConsumeFederationState(ctx context.Context, state string) (*federation.LoginState, error)
+	SaveSAMLRequest(ctx context.Context, relayState string, request saml.PendingRequest, ttl time.Duration) error
+	ConsumeSAMLRequest(ctx context.Context, relayState string) (*saml.PendingRequest, error)
+	// ClaimSAMLAssertion records the assertion of the issuer as used until
+	// ttl elapses. It returns false when the assertion was already used.
+	ClaimSAMLAssertion(ctx context.Context, issuer string, assertionID string, ttl time.Duration) (bool, error)
+	SaveSAMLLogin(ctx context.Context, code string, ticket SAMLLoginTicket, ttl time.Duration) error
+	ConsumeSAMLLogin(ctx context.Context, code string) (*SAMLLoginTicket, error)
+	// SaveDeviceAuthorization stores the grant under the device code and
+	// reserves its user code. It returns false when the user code is taken.
+	SaveDeviceAuthorization(ctx context.Context, deviceCode string, ticket DeviceAuthorizationTicket, interval time.Duration, ttl time.Duration) (bool, error)
+	FindDeviceAuthorization(ctx context.Context, userCode string) (*DeviceAuthorizationTicket, error)
+	// DecideDeviceAuthorization records the decision once and releases the
+	// user code. It returns ErrInvalidUserCode when the code is unknown.
+	DecideDeviceAuthorization(ctx context.Context, userCode string, decision DeviceDecision) error
+	// PollDeviceAuthorization counts a poll of the client: polling again
+	// within the interval returns ErrSlowDown and widens the interval. Once
+	// decided, the grant is consumed and the decision returned; until then it
+	// returns ErrAuthorizationPending.
+	PollDeviceAuthorization(ctx context.Context, deviceCode string, clientID string, slowDownStep time.Duration) (*DeviceDecision, error)
+	// ClaimDPoPProof records the proof of the key as used until ttl elapses.
+	// It returns false when the proof was already used.
+	ClaimDPoPProof(ctx context.Context, jkt string, jti string, ttl time.Duration) (bool, error)
 }
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// GrantTypeDeviceCode is the grant of the token endpoint polled by devices
+// (RFC 8628).
+const GrantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+// User codes are 8 characters of an alphabet without vowels and look-alike
+// characters (RFC 8628, section 6.1), shown as XXXX-XXXX.
+const (
+	userCodeAlphabet = "BCDFGHJKLMNPQRSTVWXZ"
+	userCodeLength   = 8
+)
+
+// deviceSlowDownStep widens the polling interval of a device that polls too
+// often (RFC 8628, section 3.5).
+const deviceSlowDownStep = 5 * time.Second
+
+// userCodeRetries bounds the attempts to draw a user code not in use.
+const userCodeRetries = 3
+
+// StartDeviceAuthorization opens a grant for an allowed client. The device
+// shows the user code and polls with the device code; nothing is tied to an
+// organization until a user approves it.
+func (s *authService) StartDeviceAuthorization(ctx context.Context, clientID string) (*user.DeviceAuthorization, error) {
+	if !slices.Contains(s.cfg.DeviceClientIDs, clientID) {
+		return nil, ErrUnknownClient
+	}
+
+	deviceCode, err := randomToken()
+	if err != nil {
+		return nil, err
+	}
+	ttl := time.Duration(s.cfg.DeviceCodeTTLMin) * time.Minute
+	interval := time.Duration(s.cfg.DevicePollIntervalSec) * time.Second
+
+	for range userCodeRetries {
+		userCode, err := newUserCode()
+		if err != nil {
+			return nil, err
+		}
+		ticket := DeviceAuthorizationTicket{
+			ClientID:  clientID,
+			UserCode:  userCode,
+			ExpiresAt: time.Now().Add(ttl),
+		}
+		saved, err := s.cacheRepo.SaveDeviceAuthorization(ctx, deviceCode, ticket, interval, ttl)
+		if err != nil {
+			return nil, err
+		}
+		if saved {
+			return &user.DeviceAuthorization{
+				DeviceCode:      deviceCode,
+				UserCode:        formatUserCode(userCode),
+				VerificationURI: s.cfg.DeviceVerificationURL,
+				ExpiresIn:       ttl,
+				Interval:        interval,
+			}, nil
+		}
+	}
+	return nil, errors.New("no free device user code")
+}
+
+func (s *authService) FindDeviceRequest(ctx context.Context, userCode string) (*user.DeviceRequest, error) {
+	ticket, err := s.cacheRepo.FindDeviceAuthorization(ctx, normalizeUserCode(userCode))
+	if err != nil {
+		return nil, err
+	}
+	return &user.DeviceRequest{
+		ClientID:  ticket.ClientID,
+		UserCode:  formatUserCode(ticket.UserCode),
+		ExpiresAt: ticket.ExpiresAt,
+	}, nil
+}
+
+// DecideDeviceAuthorization records the decision of the user once; the user
+// code cannot be used again either way.
+func (s *authService) DecideDeviceAuthorization(ctx context.Context, userID uuid.UUID, tokenString string, userCode string, approve bool) error {
+	userCode = normalizeUserCode(userCode)
+	ticket, err := s.cacheRepo.FindDeviceAuthorization(ctx, userCode)
+	if err != nil {
+		return err
+	}
+
+	decision := DeviceDecision{
+		Approved:       approve,
+		OrganizationID: tenant.OrganizationID(ctx),
+		UserID:         userID,
+	}
+	if approve {
+		token, err := s.parseAndValidateToken(tokenString)
+		if err != nil {
+			return err
+		}
+		claims, _ := token.Claims.(jwt.MapClaims)
+		authn := authContextFromClaims(claims)
+		if !authn.Time.IsZero() {
+			decision.AuthTime = authn.Time.Unix()
+			decision.AMR = authn.Methods
+		}
+	}
+	if err := s.cacheRepo.DecideDeviceAuthorization(ctx, userCode, decision); err != nil {
+		return err
+	}
+
+	action := audit.ActionDeviceDenied
+	if approve {
+		action = audit.ActionDeviceApproved
+	}
+	s.recordUserEvent(ctx, userID, action, audit.OutcomeSuccess, audit.Metadata{"client_id": ticket.ClientID})
+	return nil
+}
+
+// PollDeviceAuthorization issues the tokens of the approving user once, with
+// the same account checks as a login.
+func (s *authService) PollDeviceAuthorization(ctx context.Context, clientID string, deviceCode string) (*user.LoginResult, error) {
+	decision, err := s.cacheRepo.PollDeviceAuthorization(ctx, deviceCode, clientID, deviceSlowDownStep)
+	if err != nil {
+		return nil, err
+	}
+	if !decision.Approved {
+		return nil, ErrDeviceAccessDenied
+	}
+	ctx = tenant.WithOrganization(ctx, decision.OrganizationID)
+
+	foundUser, err := s.repo.FindByID(ctx, decision.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrDeviceAccessDenied
+		}
+		return nil, err
+	}
+	if s.passwordChangeRequired(foundUser) {
+		return nil, ErrPasswordChangeRequired
+	}
+
+	authn := AuthContext{}
+	if decision.AuthTime != 0 {
+		authn = AuthContext{Time: time.Unix(decision.AuthTime, 0), Methods: decision.AMR}
+	}
+	return s.completeLoginWithContext(ctx, foundUser, LoginMethodDevice, authn)
+}
+
+func newUserCode() (string, error) {
+	code := make([]byte, userCodeLength)
+	alphabetSize := big.NewInt(int64(len(userCodeAlphabet)))
+	for i := range code {
+		n, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code[i] = userCodeAlphabet[n.Int64()]
+	}
+	return string(code), nil
+}
+
+// normalizeUserCode accepts the code as typed: any case, with or without the
+// separator.
+func normalizeUserCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(code))
+}
+
+func formatUserCode(code string) string {
+	if len(code) != userCodeLength {
+		return code
+	}
+	return code[:userCodeLength/2] + "-" + code[userCodeLength/2:]
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+)
+
+// loginWithDirectory authenticates a login of an organization whose
+// credentials live in a directory. The local password is never consulted and
+// a directory outage fails the login instead of falling back to it, so
+// disabling an account in the directory always takes effect.
+func (s *authService) loginWithDirectory(ctx context.Context, backend directory.IBackend, email string, password string) (*user.LoginResult, error) {
+	localUser, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	// Checked before binding, so a locked account does not count failed
+	// binds against the lockout policy of the directory.
+	if localUser != nil {
+		if err := s.checkAccountLock(ctx, localUser); err != nil {
+			if errors.Is(err, ErrAccountLocked) {
+				s.recordLoginFailure(ctx, localUser, email, "locked")
+			}
+			return nil, err
+		}
+	}
+
+	entry, err := backend.Authenticate(ctx, email, password)
+	if err != nil {
+		if errors.Is(err, directory.ErrInvalidCredentials) {
+			s.recordLoginFailure(ctx, localUser, email, "invalid_password")
+			if localUser != nil {
+				s.registerFailedLogin(ctx, localUser)
+			}
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	settings := backend.Settings()
+	// The directory is the authority on the accounts of its organization, so
+	// its entries link to users with the same e-mail.
+	profile := externalProfile{
+		Source:     settings.IdentityProvider(),
+		Subject:    entry.ID,
+		Name:       entry.Name,
+		Email:      entry.Email,
+		Groups:     entry.Groups,
+		GroupRoles: settings.GroupRoles,
+	}
+	if profile.Email == "" {
+		profile.Email = email
+	}
+
+	foundUser, err := s.externalUser(ctx, profile, true, settings.JITProvisioning)
+	if err != nil {
+		if errors.Is(err, errExternalAccountNotFound) {
+			s.recordLoginFailure(ctx, nil, email, "unknown_user")
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+	if localUser == nil || localUser.ID != foundUser.ID {
+		if err := s.checkAccountLock(ctx, foundUser); err != nil {
+			if errors.Is(err, ErrAccountLocked) {
+				s.recordLoginFailure(ctx, foundUser, email, "locked")
+			}
+			return nil, err
+		}
+	}
+
+	if err := s.syncExternalUser(ctx, foundUser, profile); err != nil {
+		return nil, err
+	}
+
+	if foundUser.EmailOTPEnabled {
+		return s.startLoginChallenge(ctx, foundUser)
+	}
+	return s.completeLogin(ctx, foundUser, LoginMethodLDAP)
+}
+
+// verifyPassword checks the current password of u against the directory that
+// handles the account, or against the local hash.
+func (s *authService) verifyPassword(ctx context.Context, u *user.User, password string) error {
+	backend, err := s.userDirectory(ctx, u)
+	if err != nil {
+		return err
+	}
+	if backend == nil {
+		if err := comparePassword(u.PasswordHash, password); err != nil {
+			return ErrInvalidCurrentPassword
+		}
+		return nil
+	}
+
+	if _, err := backend.Authenticate(ctx, u.Email, password); err != nil {
+		if errors.Is(err, directory.ErrInvalidCredentials) {
+			return ErrInvalidCurrentPassword
+		}
+		return err
+	}
+	return nil
+}
+
+// checkDirectoryAccount confirms with the directory that handles the account
+// of u, if any, that its entry still exists and is enabled. Sign-ins that do
+// not bind to the directory call it before issuing tokens; as for directory
+// logins, an outage fails them.
+func (s *authService) checkDirectoryAccount(ctx context.Context, u *user.User) error {
+	backend, err := s.userDirectory(ctx, u)
+	if err != nil || backend == nil {
+		return err
+	}
+
+	if _, err := backend.Lookup(ctx, u.Email); err != nil {
+		if errors.Is(err, directory.ErrEntryNotFound) {
+			return &AccountStatusError{Err: ErrAccountInactive}
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *authService) userDirectory(ctx context.Context, u *user.User) (directory.IBackend, error) {
+	return userDirectory(ctx, s.orgRepo, s.directories, u)
+}
+
+func (s *authService) directoryFor(org *organization.Organization, email string) directory.IBackend {
+	return directoryFor(s.directories, org, email)
+}
+
+// userDirectory returns the backend that handles the account of u, if any.
+func userDirectory(ctx context.Context, orgRepo organization.IRepository, directories []directory.IBackend, u *user.User) (directory.IBackend, error) {
+	if len(directories) == 0 {
+		return nil, nil
+	}
+	org, err := orgRepo.FindByID(ctx, u.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	return directoryFor(directories, org, u.Email), nil
+}
+
+// directoryFor returns the first backend configured for the organization and
+// the domain of email.
+func directoryFor(directories []directory.IBackend, org *organization.Organization, email string) directory.IBackend {
+	for _, backend := range directories {
+		settings := backend.Settings()
+		sameOrganization := strings.EqualFold(settings.Organization, org.Slug) ||
+			(settings.Organization == "" && org.ID == tenant.DefaultOrganizationID)
+		if sameOrganization && settings.HandlesEmail(email) {
+			return backend
+		}
+	}
+	return nil
+}
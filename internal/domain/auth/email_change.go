@@ -0,0 +1,163 @@
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/google/uuid"
+)
+
+func (s *profileService) RequestEmailChange(ctx context.Context, userID uuid.UUID, currentPassword string, newEmail string) error {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// The directory owns the e-mail of its accounts: every login copies it
+	// back, and the local password it would be confirmed with is not used.
+	backend, err := userDirectory(ctx, s.orgRepo, s.directories, foundUser)
+	if err != nil {
+		return err
+	}
+	if backend != nil {
+		return ErrEmailManagedByDirectory
+	}
+
+	if err := comparePassword(foundUser.PasswordHash, currentPassword); err != nil {
+		return ErrInvalidCurrentPassword
+	}
+
+	newEmail = normalizeEmail(newEmail)
+	if newEmail == normalizeEmail(foundUser.Email) {
+		return ErrSameEmail
+	}
+
+	existing, err := s.repo.FindByEmail(ctx, newEmail)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return ErrEmailAlreadyExists
+	}
+
+	ticket := EmailChangeTicket{
+		ID:             uuid.New().String(),
+		OrganizationID: foundUser.OrganizationID,
+		UserID:         foundUser.ID.String(),
+		OldEmail:       foundUser.Email,
+		NewEmail:       newEmail,
+	}
+	confirmToken := uuid.New().String()
+	revertToken := uuid.New().String()
+	confirmTTL := time.Duration(s.cfg.EmailChangeTTLMin) * time.Minute
+	revertTTL := time.Duration(s.cfg.EmailRevertTTLHours) * time.Hour
+
+	if err := s.cacheRepo.SaveEmailChangeTicket(ctx, confirmToken, revertToken, ticket, confirmTTL, revertTTL); err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, notification.Message{
+		To:      newEmail,
+		Subject: "Confirme seu novo e-mail",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nPara confirmar a troca do e-mail da sua conta, acesse:\n%s/email/confirm?token=%s\n\nO link expira em %d minutos.",
+			foundUser.Name, s.cfg.AppPublicURL, confirmToken, s.cfg.EmailChangeTTLMin,
+		),
+	})
+	if err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, notification.Message{
+		To:      foundUser.Email,
+		Subject: "Solicitação de troca de e-mail",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nFoi solicitada a troca do e-mail da sua conta para %s.\nSe não foi você, cancele ou desfaça a troca em:\n%s/email/revert?token=%s\n\nO link é válido por %d horas.",
+			foundUser.Name, newEmail, s.cfg.AppPublicURL, revertToken, s.cfg.EmailRevertTTLHours,
+		),
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to send email change notice to old address of user %s: %v", foundUser.ID, err)
+	}
+
+	return nil
+}
+
+func (s *profileService) ConfirmEmailChange(ctx context.Context, confirmToken string) error {
+	ticket, err := s.cacheRepo.VerifyAndConsumeEmailChangeToken(ctx, confirmToken)
+	if err != nil {
+		return ErrInvalidEmailChange
+	}
+	ctx = tenant.WithOrganization(ctx, ticket.OrganizationID)
+
+	// Only the latest request of the user can be confirmed, and a revert
+	// issued before the confirmation removes the pending marker.
+	pendingID, err := s.cacheRepo.ConsumePendingEmailChange(ctx, ticket.UserID)
+	if err != nil {
+		return err
+	}
+	if pendingID != ticket.ID {
+		return ErrInvalidEmailChange
+	}
+
+	userID, err := uuid.Parse(ticket.UserID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if normalizeEmail(foundUser.Email) != normalizeEmail(ticket.OldEmail) {
+		return ErrInvalidEmailChange
+	}
+
+	if err := s.repo.UpdateEmail(ctx, userID, ticket.NewEmail); err != nil {
+		return err
+	}
+
+	return revokeSessions(ctx, s.repo, s.cacheRepo, userID)
+}
+
+// RevertEmailChange cancels a pending change or, when it was already
+// confirmed, restores the old address. Either way every session is revoked and
+// a password change is required, since the request may not have been the
+// account owner's.
+func (s *profileService) RevertEmailChange(ctx context.Context, revertToken string) error {
+	ticket, err := s.cacheRepo.VerifyAndConsumeEmailRevertToken(ctx, revertToken)
+	if err != nil {
+		return ErrInvalidEmailChange
+	}
+	ctx = tenant.WithOrganization(ctx, ticket.OrganizationID)
+
+	userID, err := uuid.Parse(ticket.UserID)
+	if err != nil {
+		return ErrInvalidUserID
+	}
+
+	if _, err := s.cacheRepo.ConsumePendingEmailChange(ctx, ticket.UserID); err != nil {
+		return err
+	}
+
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if normalizeEmail(foundUser.Email) == normalizeEmail(ticket.NewEmail) {
+		if err := s.repo.UpdateEmail(ctx, userID, ticket.OldEmail); err != nil {
+			return err
+		}
+	}
+
+	if err := s.repo.SetMustChangePassword(ctx, userID, true); err != nil {
+		return err
+	}
+
+	return revokeSessions(ctx, s.repo, s.cacheRepo, userID)
+}
@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+	"github.com/google/uuid"
+)
+
+// TestRequestEmailChangeRefusesDirectoryAccounts: the directory owns the
+// e-mail of its accounts, so the change is refused before the password is
+// checked against the unused local hash.
+func TestRequestEmailChangeRefusesDirectoryAccounts(t *testing.T) {
+	users := &fakeUserRepo{users: map[uuid.UUID]*user.User{}}
+	backend := &fakeDirectory{settings: directory.Settings{Name: "corp"}}
+	s := NewProfileService(users, &fakeCacheRepo{}, &fakeOrganizationRepo{}, nil, &config.Config{}, []directory.IBackend{backend})
+	ctx := tenant.WithOrganization(context.Background(), tenant.DefaultOrganizationID)
+
+	u := &user.User{Model: base.Model{ID: uuid.New()}, OrganizationID: tenant.DefaultOrganizationID, Email: "alice@example.com", Status: user.StatusActive}
+	users.users[u.ID] = u
+
+	err := s.RequestEmailChange(ctx, u.ID, "alice-secret", "alice@other.example.com")
+	if !errors.Is(err, ErrEmailManagedByDirectory) {
+		t.Fatalf("RequestEmailChange error = %v, want ErrEmailManagedByDirectory", err)
+	}
+}
@@ -0,0 +1,234 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+const (
+	OTPPurposeLogin  = "login"
+	OTPPurposeStepUp = "step_up"
+)
+
+const otpCodeDigits = 6
+
+// startLoginChallenge replaces the tokens of a password login with a code
+// mailed to the user. Inactive accounts get the status error right away, so
+// they are not sent codes they cannot use.
+func (s *authService) startLoginChallenge(ctx context.Context, foundUser *user.User) (*user.LoginResult, error) {
+	if err := s.checkAccountStatus(ctx, foundUser); err != nil {
+		var statusErr *AccountStatusError
+		if errors.As(err, &statusErr) {
+			s.recordLoginFailure(ctx, foundUser, foundUser.Email, string(foundUser.Status))
+		}
+		return nil, err
+	}
+
+	challengeID, err := s.issueOTPChallenge(ctx, foundUser, OTPPurposeLogin)
+	if err != nil {
+		return nil, err
+	}
+	return &user.LoginResult{User: foundUser, OTPRequired: true, OTPChallenge: challengeID}, nil
+}
+
+// LoginWithEmailOTP completes a password login of a user with e-mail OTP
+// enabled. Wrong codes count as failed logins toward the account lockout.
+func (s *authService) LoginWithEmailOTP(ctx context.Context, challengeID, code string) (*user.LoginResult, error) {
+	challenge, err := s.verifyOTP(ctx, challengeID, code, OTPPurposeLogin)
+	if challenge == nil {
+		return nil, err
+	}
+	ctx = tenant.WithOrganization(ctx, challenge.OrganizationID)
+
+	foundUser, findErr := s.repo.FindByID(ctx, challenge.UserID)
+	if findErr != nil {
+		if errors.Is(findErr, ErrUserNotFound) {
+			return nil, ErrInvalidOTP
+		}
+		return nil, findErr
+	}
+
+	if err != nil {
+		s.recordLoginFailure(ctx, foundUser, foundUser.Email, "invalid_otp")
+		s.registerFailedLogin(ctx, foundUser)
+		return nil, err
+	}
+
+	if err := s.checkAccountLock(ctx, foundUser); err != nil {
+		if errors.Is(err, ErrAccountLocked) {
+			s.recordLoginFailure(ctx, foundUser, foundUser.Email, "locked")
+		}
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, foundUser, LoginMethodEmailOTP)
+}
+
+func (s *authService) StartStepUp(ctx context.Context, userID uuid.UUID) (string, error) {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return s.issueOTPChallenge(ctx, foundUser, OTPPurposeStepUp)
+}
+
+// VerifyStepUp exchanges the code of a step-up challenge for a single-use
+// token accepted by one sensitive operation within the step-up TTL.
+func (s *authService) VerifyStepUp(ctx context.Context, userID uuid.UUID, challengeID, code string) (string, error) {
+	challenge, err := s.verifyOTP(ctx, challengeID, code, OTPPurposeStepUp)
+	if challenge != nil && challenge.UserID != userID {
+		return "", ErrInvalidOTP
+	}
+	if err != nil {
+		s.recordUserEvent(ctx, userID, audit.ActionStepUp, audit.OutcomeFailure, audit.Metadata{"reason": err.Error()})
+		return "", err
+	}
+
+	stepUpToken, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	ttl := time.Duration(s.cfg.StepUpTTLMin) * time.Minute
+	if err := s.cacheRepo.SaveStepUpToken(ctx, userID.String(), stepUpToken, ttl); err != nil {
+		return "", err
+	}
+
+	s.recordUserEvent(ctx, userID, audit.ActionStepUp, audit.OutcomeSuccess, nil)
+	return stepUpToken, nil
+}
+
+// RequireStepUp consumes the step-up token of users with e-mail OTP enabled.
+// For the other users the current password asked by the operation suffices.
+func (s *authService) RequireStepUp(ctx context.Context, userID uuid.UUID, stepUpToken string) error {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !foundUser.EmailOTPEnabled {
+		return nil
+	}
+	return s.consumeStepUp(ctx, userID, stepUpToken)
+}
+
+// EnableEmailOTP always asks for a step-up token, which proves that the user
+// receives the codes sent to the account e-mail.
+func (s *authService) EnableEmailOTP(ctx context.Context, userID uuid.UUID, stepUpToken string) error {
+	if err := s.consumeStepUp(ctx, userID, stepUpToken); err != nil {
+		return err
+	}
+	if err := s.repo.SetEmailOTPEnabled(ctx, userID, true); err != nil {
+		return err
+	}
+
+	s.recordUserEvent(ctx, userID, audit.ActionEmailOTPEnabled, audit.OutcomeSuccess, nil)
+	return nil
+}
+
+func (s *authService) DisableEmailOTP(ctx context.Context, userID uuid.UUID) error {
+	if err := s.repo.SetEmailOTPEnabled(ctx, userID, false); err != nil {
+		return err
+	}
+
+	s.recordUserEvent(ctx, userID, audit.ActionEmailOTPDisabled, audit.OutcomeSuccess, nil)
+	return nil
+}
+
+func (s *authService) issueOTPChallenge(ctx context.Context, u *user.User, purpose string) (string, error) {
+	challengeID, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	code, err := randomOTPCode()
+	if err != nil {
+		return "", err
+	}
+
+	challenge := OTPChallenge{
+		OrganizationID: u.OrganizationID,
+		UserID:         u.ID,
+		Purpose:        purpose,
+		CodeHash:       s.otpCodeHash(challengeID, code),
+	}
+	ttl := time.Duration(s.cfg.EmailOTPTTLMin) * time.Minute
+	if err := s.cacheRepo.SaveOTPChallenge(ctx, challengeID, challenge, ttl); err != nil {
+		return "", err
+	}
+
+	err = s.mailer.Send(ctx, notification.Message{
+		To:      u.Email,
+		Subject: "Seu código de verificação",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nSeu código de verificação é: %s\n\nEle expira em %d minutos. Se não foi você, troque sua senha.",
+			u.Name, code, s.cfg.EmailOTPTTLMin,
+		),
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to send one-time code to user %s: %v", u.ID, err)
+		return "", err
+	}
+	return challengeID, nil
+}
+
+// verifyOTP counts the attempt before comparing the code and drops the
+// challenge once it is used or out of attempts. The challenge is returned
+// whenever it existed, even if the code did not match.
+func (s *authService) verifyOTP(ctx context.Context, challengeID, code, purpose string) (*OTPChallenge, error) {
+	challenge, attempts, err := s.cacheRepo.AttemptOTPChallenge(ctx, challengeID)
+	if err != nil || challenge.Purpose != purpose {
+		return nil, ErrInvalidOTP
+	}
+
+	if hmac.Equal([]byte(s.otpCodeHash(challengeID, code)), []byte(challenge.CodeHash)) {
+		if err := s.cacheRepo.DeleteOTPChallenge(ctx, challengeID); err != nil {
+			return nil, err
+		}
+		return challenge, nil
+	}
+
+	if attempts >= s.cfg.EmailOTPMaxAttempts {
+		if err := s.cacheRepo.DeleteOTPChallenge(ctx, challengeID); err != nil {
+			log.Printf("[ERROR] Failed to drop one-time code challenge of user %s: %v", challenge.UserID, err)
+		}
+		return challenge, ErrOTPAttemptsExceeded
+	}
+	return challenge, ErrInvalidOTP
+}
+
+func (s *authService) consumeStepUp(ctx context.Context, userID uuid.UUID, stepUpToken string) error {
+	if stepUpToken == "" {
+		return ErrStepUpRequired
+	}
+	tokenUserID, err := s.cacheRepo.VerifyAndConsumeStepUpToken(ctx, stepUpToken)
+	if err != nil || tokenUserID != userID.String() {
+		return ErrStepUpRequired
+	}
+	return nil
+}
+
+func (s *authService) otpCodeHash(challengeID, code string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.JWTSecret))
+	mac.Write([]byte("email-otp:" + challengeID + ":" + code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func randomOTPCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1_000_000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%0*d", otpCodeDigits, n.Int64()), nil
+}
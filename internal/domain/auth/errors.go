@@ -1,15 +1,90 @@
 package auth
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrUserNotFound           = errors.New("user not found")
 	ErrInvalidCredentials     = errors.New("invalid credentials")
-	ErrAccountInactive        = errors.New("account is inactive or suspended")
+	ErrAccountInactive        = errors.New("account is inactive")
+	ErrAccountLocked          = errors.New("account is temporarily locked")
 	ErrEmailAlreadyExists     = errors.New("email already exists")
 	ErrInvalidCurrentPassword = errors.New("invalid current password")
 	ErrSamePassword           = errors.New("new password cannot be the same as the current password")
 	ErrInvalidResetToken      = errors.New("invalid or expired reset token")
 	ErrInvalidUserID          = errors.New("invalid user ID associated with token")
 	ErrInvalidRefreshToken    = errors.New("invalid or expired refresh token")
+	ErrPasswordChangeRequired = errors.New("password change required")
+	ErrProfileModified        = errors.New("profile was modified by another request")
+	ErrInvalidLocale          = errors.New("invalid locale")
+	ErrInvalidTimezone        = errors.New("invalid timezone")
+	ErrMetadataTooLarge       = errors.New("metadata exceeds the allowed size")
+	ErrSameEmail              = errors.New("new email cannot be the same as the current email")
+	ErrInvalidEmailChange     = errors.New("invalid or expired email change token")
+	ErrInvalidCursor          = errors.New("invalid pagination cursor")
+	ErrSelfSignupDisabled     = errors.New("self sign-up is disabled for this organization")
+	ErrUnsupportedFormat      = errors.New("unsupported file format")
+	ErrInvalidImportFile      = errors.New("import file could not be read")
+	ErrInvalidImportRow       = errors.New("malformed row")
+	ErrInvalidName            = errors.New("name must have between 3 and 100 characters")
+	ErrInvalidEmail           = errors.New("invalid email")
+	ErrUnsupportedHash        = errors.New("password hash must be bcrypt or argon2id")
+	ErrDuplicateImportEmail   = errors.New("email repeated in the file")
+	ErrInvalidActorToken      = errors.New("invalid or expired actor token")
+	ErrImpersonationForbidden = errors.New("impersonation is not allowed")
+)
+
+// Account status errors are only returned once the password was verified, so
+// they tell nothing to callers without valid credentials.
+var (
+	ErrAccountSuspended           = errors.New("account is suspended")
+	ErrAccountBanned              = errors.New("account is banned")
+	ErrAccountPendingVerification = errors.New("account is pending verification")
+	ErrInvalidSuspension          = errors.New("suspension requires an end date in the future")
+	ErrStatusReasonRequired       = errors.New("banning requires a reason")
+)
+
+var ErrInvalidReactivationToken = errors.New("invalid or expired reactivation token")
+
+var ErrInvalidMagicLink = errors.New("invalid or expired magic link")
+
+var (
+	ErrInvalidOTP          = errors.New("invalid or expired one-time code")
+	ErrOTPAttemptsExceeded = errors.New("too many invalid one-time code attempts")
+	ErrStepUpRequired      = errors.New("step-up verification required")
 )
+
+var (
+	ErrPasswordManagedByDirectory = errors.New("password is managed by the organization directory")
+	ErrEmailManagedByDirectory    = errors.New("email is managed by the organization directory")
+)
+
+// Device authorization grant errors. The token endpoint answers the polling
+// ones with the error codes of RFC 8628, section 3.5.
+var (
+	ErrUnknownClient        = errors.New("unknown client")
+	ErrInvalidUserCode      = errors.New("invalid or expired user code")
+	ErrAuthorizationPending = errors.New("authorization pending")
+	ErrSlowDown             = errors.New("device polled too often")
+	ErrDeviceAccessDenied   = errors.New("device authorization denied")
+	ErrDeviceCodeExpired    = errors.New("device code expired")
+)
+
+// AccountStatusError wraps the account status error returned by Login with
+// the end of the suspension, if any, and whether the user may reactivate the
+// account by e-mail.
+type AccountStatusError struct {
+	Err            error
+	SuspendedUntil *time.Time
+	Reactivatable  bool
+}
+
+func (e *AccountStatusError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AccountStatusError) Unwrap() error {
+	return e.Err
+}
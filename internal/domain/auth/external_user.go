@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+	"github.com/google/uuid"
+)
+
+// externalProfile is a user as described by an identity source the
+// organization trusts with its accounts, such as a directory or a SAML
+// identity provider. Source is the provider of its identities.
+type externalProfile struct {
+	Source     string
+	Subject    string
+	Name       string
+	Email      string
+	Groups     []string
+	GroupRoles rbac.GroupRoles
+}
+
+var (
+	errExternalAccountNotFound = errors.New("no account for the external identity")
+	errExternalLinkRequired    = errors.New("an account with the email of the external identity exists")
+)
+
+// externalUser returns the user linked to the profile. On the first sign-in
+// it links the user with the same e-mail, or provisions one, as allowed.
+func (s *authService) externalUser(ctx context.Context, p externalProfile, linkExisting bool, provision bool) (*user.User, error) {
+	identity, err := s.identities.FindBySubject(ctx, p.Source, p.Subject)
+	if err == nil {
+		if err := s.identities.UpdateLastLogin(ctx, identity.ID); err != nil {
+			log.Printf("[ERROR] Failed to update last login of identity %s: %v", identity.ID, err)
+		}
+		return s.repo.FindByID(ctx, identity.UserID)
+	}
+	if !errors.Is(err, federation.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	email := normalizeEmail(p.Email)
+	if email == "" {
+		return nil, errExternalAccountNotFound
+	}
+	foundUser, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	source := "email"
+	if foundUser != nil && !linkExisting {
+		return nil, errExternalLinkRequired
+	}
+	if foundUser == nil {
+		if !provision {
+			return nil, errExternalAccountNotFound
+		}
+		foundUser, err = s.provisionExternalUser(ctx, p, email)
+		if err != nil {
+			return nil, err
+		}
+		source = "provisioning"
+	}
+
+	if err := s.linkExternalIdentity(ctx, foundUser, p, source); err != nil {
+		return nil, err
+	}
+	return foundUser, nil
+}
+
+func (s *authService) provisionExternalUser(ctx context.Context, p externalProfile, email string) (*user.User, error) {
+	name := strings.TrimSpace(p.Name)
+	if len(name) < 3 || len(name) > 100 {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	now := time.Now()
+	newUser := &user.User{
+		Model: base.Model{
+			ID: uuid.New(),
+		},
+		Name:              name,
+		Email:             email,
+		Role:              user.RoleUser,
+		Status:            user.StatusActive,
+		PasswordChangedAt: &now,
+	}
+	if err := s.repo.Create(ctx, newUser); err != nil {
+		return nil, err
+	}
+	if err := s.rbacRepo.AddUserRole(ctx, newUser.ID, rbac.RoleUser); err != nil {
+		return nil, err
+	}
+	if err := s.orgRepo.AddMember(ctx, newUser.OrganizationID, newUser.ID, organization.RoleMember); err != nil {
+		return nil, err
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		TargetID: &newUser.ID,
+		Action:   audit.ActionUserProvisioned,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: audit.Metadata{"provider": p.Source},
+	})
+	return newUser, nil
+}
+
+// linkExternalIdentity links the subject of the profile to u. source tells
+// what allowed the link: provisioning or a matching e-mail.
+func (s *authService) linkExternalIdentity(ctx context.Context, u *user.User, p externalProfile, source string) error {
+	now := time.Now()
+	err := s.identities.Create(ctx, &federation.Identity{
+		ID:             uuid.New(),
+		OrganizationID: u.OrganizationID,
+		UserID:         u.ID,
+		Provider:       p.Source,
+		Subject:        p.Subject,
+		Email:          normalizeEmail(p.Email),
+		LastLoginAt:    &now,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordUserEvent(ctx, u.ID, audit.ActionIdentityLinked, audit.OutcomeSuccess, audit.Metadata{
+		"provider": p.Source,
+		"source":   source,
+	})
+	return nil
+}
+
+// syncExternalUser copies the name, e-mail and group roles of the profile to
+// the user.
+func (s *authService) syncExternalUser(ctx context.Context, u *user.User, p externalProfile) error {
+	var changed []string
+
+	name := strings.TrimSpace(p.Name)
+	if len(name) >= 3 && len(name) <= 100 && name != u.Name {
+		u.Name = name
+		if err := s.repo.UpdateProfile(ctx, u, nil); err != nil {
+			return err
+		}
+		changed = append(changed, "name")
+	}
+
+	email := normalizeEmail(p.Email)
+	if email != "" && email != u.Email {
+		err := s.repo.UpdateEmail(ctx, u.ID, email)
+		switch {
+		case err == nil:
+			u.Email = email
+			changed = append(changed, "email")
+		case errors.Is(err, ErrEmailAlreadyExists):
+			log.Printf("[WARN] %s e-mail %s of user %s belongs to another user", p.Source, email, u.ID)
+		default:
+			return err
+		}
+	}
+
+	rolesChanged, err := s.syncGroupRoles(ctx, u, p)
+	if err != nil {
+		return err
+	}
+	if rolesChanged {
+		changed = append(changed, "roles")
+	}
+
+	if len(changed) > 0 {
+		s.recordUserEvent(ctx, u.ID, audit.ActionAttributesSynced, audit.OutcomeSuccess, audit.Metadata{
+			"provider": p.Source,
+			"fields":   strings.Join(changed, ","),
+		})
+	}
+	return nil
+}
+
+// syncGroupRoles replaces the roles of the user with the roles mapped to its
+// groups. Without a mapping the roles are managed locally.
+func (s *authService) syncGroupRoles(ctx context.Context, u *user.User, p externalProfile) (bool, error) {
+	if len(p.GroupRoles) == 0 {
+		return false, nil
+	}
+
+	names, matched := p.GroupRoles.RolesFor(p.Groups)
+	if !matched {
+		names = []string{rbac.RoleUser}
+	}
+
+	current, err := s.rbacRepo.GetUserRoles(ctx, u.ID)
+	if err != nil {
+		return false, err
+	}
+	currentNames := make([]string, 0, len(current))
+	for _, role := range current {
+		currentNames = append(currentNames, role.Name)
+	}
+	slices.Sort(currentNames)
+	if slices.Equal(currentNames, names) {
+		return false, nil
+	}
+
+	roles, err := s.rbacRepo.FindRolesByNames(ctx, names)
+	if err != nil {
+		return false, err
+	}
+	if len(roles) != len(names) {
+		log.Printf("[WARN] %s maps groups to unknown roles: %v", p.Source, names)
+	}
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if err := s.rbacRepo.SetUserRoles(ctx, u.ID, roleIDs); err != nil {
+		return false, err
+	}
+	return true, nil
+}
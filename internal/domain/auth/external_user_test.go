@@ -0,0 +1,166 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/scim"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// TestExternalUserProvisioning covers the first sign-in through a directory
+// or a SAML identity provider with JIT provisioning.
+func TestExternalUserProvisioning(t *testing.T) {
+	f := newTestAuthService(t)
+	orgID := uuid.New()
+	ctx := tenant.WithOrganization(context.Background(), orgID)
+
+	profile := externalProfile{
+		Source:  "ldap:corp",
+		Subject: "5f0c2b8e-4f1e-4a4b-9f0d-8c1d2e3f4a51",
+		Name:    "Alice Example",
+		Email:   "Alice@Example.com",
+	}
+
+	u, err := f.externalUser(ctx, profile, true, true)
+	if err != nil {
+		t.Fatalf("externalUser: %v", err)
+	}
+	if u.PasswordChangedAt == nil {
+		t.Error("provisioned user has no password_changed_at")
+	}
+	if u.Email != "alice@example.com" || u.Name != "Alice Example" || u.OrganizationID != orgID || u.Status != user.StatusActive {
+		t.Errorf("user = %+v, want an active Alice in the organization", u)
+	}
+	if !slices.Equal(f.rbac.roles[u.ID], []string{rbac.RoleUser}) || f.orgs.members[u.ID] != organization.RoleMember {
+		t.Errorf("roles = %v, membership = %q, want the default role and membership", f.rbac.roles[u.ID], f.orgs.members[u.ID])
+	}
+	if want := []string{audit.ActionUserProvisioned, audit.ActionIdentityLinked}; !slices.Equal(f.auditLog.actions(), want) {
+		t.Errorf("audit actions = %v, want %v", f.auditLog.actions(), want)
+	}
+
+	// The next sign-in finds the user through the linked identity.
+	again, err := f.externalUser(ctx, profile, true, true)
+	if err != nil {
+		t.Fatalf("externalUser on the second sign-in: %v", err)
+	}
+	if again.ID != u.ID || len(f.users.users) != 1 {
+		t.Errorf("second sign-in returned %s with %d users, want the provisioned user", again.ID, len(f.users.users))
+	}
+}
+
+func TestExternalUserWithoutProvisioning(t *testing.T) {
+	f := newTestAuthService(t)
+	ctx := tenant.WithOrganization(context.Background(), uuid.New())
+
+	_, err := f.externalUser(ctx, externalProfile{Source: "saml:corp", Subject: "bob", Email: "bob@example.com"}, true, false)
+	if !errors.Is(err, errExternalAccountNotFound) {
+		t.Fatalf("externalUser error = %v, want errExternalAccountNotFound", err)
+	}
+	if len(f.users.users) != 0 {
+		t.Errorf("users = %d, want none provisioned", len(f.users.users))
+	}
+}
+
+// fakeSCIMRepo keeps the SCIM links of the users of a fakeUserRepo.
+type fakeSCIMRepo struct {
+	scim.IRepository
+	users *fakeUserRepo
+	links map[uuid.UUID]*string
+}
+
+func (r *fakeSCIMRepo) ListUsers(ctx context.Context, q scim.Query) ([]scim.User, int64, error) {
+	return nil, 0, nil
+}
+
+func (r *fakeSCIMRepo) SaveUserLink(ctx context.Context, link *scim.UserLink) error {
+	r.links[link.UserID] = link.ExternalID
+	return nil
+}
+
+func (r *fakeSCIMRepo) FindUser(ctx context.Context, id uuid.UUID) (*scim.User, error) {
+	u, ok := r.users.users[id]
+	if !ok {
+		return nil, scim.ErrUserNotFound
+	}
+	return &scim.User{User: *u, ExternalID: r.links[id]}, nil
+}
+
+func TestSCIMCreateUser(t *testing.T) {
+	f := newTestAuthService(t)
+	s := &scimService{authService: f.authService}
+	s.scimRepo = &fakeSCIMRepo{users: f.users, links: map[uuid.UUID]*string{}}
+	ctx := tenant.WithOrganization(context.Background(), uuid.New())
+
+	externalID := "00u1"
+	created, err := s.CreateUser(ctx, scim.UserAttributes{UserName: "carol@example.com", ExternalID: &externalID, Name: "Carol Example", Active: true})
+	if err != nil {
+		t.Fatalf("CreateUser: %v", err)
+	}
+	if created.PasswordChangedAt == nil {
+		t.Error("provisioned user has no password_changed_at")
+	}
+	if created.Email != "carol@example.com" || created.ExternalID == nil || *created.ExternalID != externalID {
+		t.Errorf("user = %+v, want carol linked to %s", created, externalID)
+	}
+}
+
+// fakeDirectory accepts a single login and password.
+type fakeDirectory struct {
+	settings directory.Settings
+	login    string
+	password string
+	entry    directory.Entry
+}
+
+func (d *fakeDirectory) Settings() directory.Settings {
+	return d.settings
+}
+
+func (d *fakeDirectory) Authenticate(ctx context.Context, login string, password string) (*directory.Entry, error) {
+	if login != d.login || password != d.password {
+		return nil, directory.ErrInvalidCredentials
+	}
+	entry := d.entry
+	return &entry, nil
+}
+
+func (d *fakeDirectory) Lookup(ctx context.Context, login string) (*directory.Entry, error) {
+	if login != d.login {
+		return nil, directory.ErrEntryNotFound
+	}
+	entry := d.entry
+	return &entry, nil
+}
+
+// TestLoginWithDirectoryProvisioning is the first sign-in of a directory user
+// with JIT provisioning.
+func TestLoginWithDirectoryProvisioning(t *testing.T) {
+	f := newTestAuthService(t)
+	ctx := tenant.WithOrganization(context.Background(), uuid.New())
+	backend := &fakeDirectory{
+		settings: directory.Settings{Name: "corp", JITProvisioning: true},
+		login:    "alice@example.com",
+		password: "alice-secret",
+		entry:    directory.Entry{ID: "alice-uuid", DN: "uid=alice,dc=example,dc=com", Email: "alice@example.com", Name: "Alice Example"},
+	}
+
+	result, err := f.loginWithDirectory(ctx, backend, "alice@example.com", "alice-secret")
+	if err != nil {
+		t.Fatalf("loginWithDirectory: %v", err)
+	}
+	if result.User == nil || result.User.PasswordChangedAt == nil || result.AccessToken == "" {
+		t.Fatalf("result = %+v, want a provisioned user with tokens", result)
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].Provider != "ldap:corp" {
+		t.Errorf("identities = %+v, want the directory entry linked", f.identities.identities)
+	}
+}
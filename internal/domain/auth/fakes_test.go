@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// The fakes embed the interface they stand in for, so a call the test does
+// not expect panics instead of silently succeeding.
+
+// fakeUserRepo keeps users in memory. Every path that creates a user must set
+// PasswordChangedAt, so Create refuses users without it.
+type fakeUserRepo struct {
+	user.IRepository
+	users map[uuid.UUID]*user.User
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, u *user.User) error {
+	if u.PasswordChangedAt == nil {
+		return errors.New("password_changed_at is not set")
+	}
+	u.OrganizationID = tenant.OrganizationID(ctx)
+	r.users[u.ID] = u
+	return nil
+}
+
+func (r *fakeUserRepo) FindByEmail(ctx context.Context, email string) (*user.User, error) {
+	for _, u := range r.users {
+		if u.Email == email && u.OrganizationID == tenant.OrganizationID(ctx) {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) FindByID(ctx context.Context, id uuid.UUID) (*user.User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *fakeUserRepo) UpdateLastLoginAt(ctx context.Context, userID uuid.UUID) error {
+	return nil
+}
+
+// fakeRBACRepo keeps the role names of each user; permissions maps a role
+// name to its permissions.
+type fakeRBACRepo struct {
+	rbac.IRepository
+	roles       map[uuid.UUID][]string
+	permissions map[string][]string
+}
+
+func (r *fakeRBACRepo) AddUserRole(ctx context.Context, userID uuid.UUID, roleName string) error {
+	r.roles[userID] = append(r.roles[userID], roleName)
+	return nil
+}
+
+func (r *fakeRBACRepo) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]rbac.Role, error) {
+	roles := make([]rbac.Role, 0, len(r.roles[userID]))
+	for _, name := range r.roles[userID] {
+		role := rbac.Role{Name: name}
+		for _, permission := range r.permissions[name] {
+			role.Permissions = append(role.Permissions, rbac.Permission{Name: permission})
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
+
+type fakeOrganizationRepo struct {
+	organization.IRepository
+	members map[uuid.UUID]string
+}
+
+func (r *fakeOrganizationRepo) AddMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, role string) error {
+	r.members[userID] = role
+	return nil
+}
+
+// FindByID returns an organization without a slug, which only directories
+// of the default organization handle.
+func (r *fakeOrganizationRepo) FindByID(ctx context.Context, id uuid.UUID) (*organization.Organization, error) {
+	return &organization.Organization{ID: id}, nil
+}
+
+// FindMemberRole returns the organization role without permissions; tests
+// grant permissions through the global roles.
+func (r *fakeOrganizationRepo) FindMemberRole(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (*rbac.Role, error) {
+	role, ok := r.members[userID]
+	if !ok {
+		return nil, organization.ErrMemberNotFound
+	}
+	return &rbac.Role{Name: role}, nil
+}
+
+type fakeIdentityRepo struct {
+	federation.IRepository
+	identities []federation.Identity
+}
+
+func (r *fakeIdentityRepo) FindBySubject(ctx context.Context, provider string, subject string) (*federation.Identity, error) {
+	for _, i := range r.identities {
+		if i.Provider == provider && i.Subject == subject {
+			return &i, nil
+		}
+	}
+	return nil, federation.ErrIdentityNotFound
+}
+
+func (r *fakeIdentityRepo) Create(ctx context.Context, identity *federation.Identity) error {
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *fakeIdentityRepo) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (r *fakeIdentityRepo) ListForUser(ctx context.Context, userID uuid.UUID) ([]federation.Identity, error) {
+	var identities []federation.Identity
+	for _, i := range r.identities {
+		if i.UserID == userID {
+			identities = append(identities, i)
+		}
+	}
+	return identities, nil
+}
+
+type fakeAuditLog struct {
+	audit.IService
+	events []audit.Event
+}
+
+func (l *fakeAuditLog) Record(ctx context.Context, e audit.Event) {
+	l.events = append(l.events, e)
+}
+
+func (l *fakeAuditLog) actions() []string {
+	actions := make([]string, 0, len(l.events))
+	for _, e := range l.events {
+		actions = append(actions, e.Action)
+	}
+	return actions
+}
+
+// fakeCacheRepo keeps the refresh tokens of the sessions issued.
+type fakeCacheRepo struct {
+	ICacheRepository
+	refreshTokens map[string][]string
+}
+
+func (c *fakeCacheRepo) SaveRefreshToken(ctx context.Context, userID string, refreshToken string, ttl time.Duration) error {
+	c.refreshTokens[userID] = append(c.refreshTokens[userID], refreshToken)
+	return nil
+}
+
+func (c *fakeCacheRepo) ClearFailedLogins(ctx context.Context, userID string) error {
+	return nil
+}
+
+func (c *fakeCacheRepo) IsTokenBlacklisted(ctx context.Context, jti string) (bool, error) {
+	return false, nil
+}
+
+type fakeLoginHistory struct {
+	loginhistory.IService
+}
+
+func (h fakeLoginHistory) RecordSuccess(ctx context.Context, u *user.User) {}
+
+func (h fakeLoginHistory) RecordFailure(ctx context.Context, u *user.User, reason string) {}
+
+// testAuthService wires an authService to the fakes.
+type testAuthService struct {
+	*authService
+	users      *fakeUserRepo
+	cache      *fakeCacheRepo
+	rbac       *fakeRBACRepo
+	orgs       *fakeOrganizationRepo
+	identities *fakeIdentityRepo
+	auditLog   *fakeAuditLog
+}
+
+func newTestAuthService(t *testing.T) *testAuthService {
+	t.Helper()
+	f := &testAuthService{
+		users:      &fakeUserRepo{users: map[uuid.UUID]*user.User{}},
+		cache:      &fakeCacheRepo{refreshTokens: map[string][]string{}},
+		rbac:       &fakeRBACRepo{roles: map[uuid.UUID][]string{}, permissions: map[string][]string{}},
+		orgs:       &fakeOrganizationRepo{members: map[uuid.UUID]string{}},
+		identities: &fakeIdentityRepo{},
+		auditLog:   &fakeAuditLog{},
+	}
+	cfg := &config.Config{
+		JWTSecret:           "test-secret",
+		JWTIssuer:           "chameleon-auth-test",
+		JWTAudience:         "chameleon-test",
+		TokenTTLHours:       1,
+		RefreshTokenTTLDays: 1,
+	}
+	f.authService = newAuthService(f.users, f.cache, f.rbac, f.orgs, f.auditLog, fakeLoginHistory{}, nil, cfg, f.identities, nil)
+	return f
+}
@@ -0,0 +1,333 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+	"github.com/google/uuid"
+)
+
+// federationService signs users in through upstream OIDC providers and issues
+// the same tokens as the password login.
+type federationService struct {
+	*authService
+	client    federation.IOIDCClient
+	providers []federation.Provider
+}
+
+func NewFederationService(repo user.IRepository, cacheRepo ICacheRepository, rbacRepo rbac.IRepository, orgRepo organization.IRepository, auditLog audit.IService, history loginhistory.IService, mailer notification.IMailer, cfg *config.Config, identities federation.IRepository, directories []directory.IBackend, client federation.IOIDCClient, providers []federation.Provider) federation.IService {
+	return &federationService{
+		authService: newAuthService(repo, cacheRepo, rbacRepo, orgRepo, auditLog, history, mailer, cfg, identities, directories),
+		client:      client,
+		providers:   providers,
+	}
+}
+
+func (s *federationService) Providers() []federation.ProviderInfo {
+	infos := make([]federation.ProviderInfo, 0, len(s.providers))
+	for _, p := range s.providers {
+		infos = append(infos, federation.ProviderInfo{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	return infos
+}
+
+func (s *federationService) StartLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+	return s.authorize(ctx, provider, nil)
+}
+
+func (s *federationService) StartLink(ctx context.Context, userID uuid.UUID, providerName string) (string, string, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	org, err := s.resolveOrganization(ctx, provider.Organization)
+	if err != nil {
+		return "", "", err
+	}
+	if org.ID != tenant.OrganizationID(ctx) {
+		return "", "", federation.ErrOrganizationMismatch
+	}
+	return s.authorize(ctx, provider, &userID)
+}
+
+func (s *federationService) CompleteLogin(ctx context.Context, state string, code string) (*user.LoginResult, error) {
+	loginState, err := s.cacheRepo.ConsumeFederationState(ctx, state)
+	if err != nil {
+		return nil, federation.ErrInvalidState
+	}
+	provider, err := s.provider(loginState.Provider)
+	if err != nil {
+		return nil, federation.ErrInvalidState
+	}
+
+	claims, err := s.client.Exchange(ctx, provider, code, loginState.CodeVerifier, s.cfg.OIDCRedirectURL, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := s.resolveOrganization(ctx, provider.Organization)
+	if err != nil {
+		return nil, err
+	}
+	ctx = tenant.WithOrganization(ctx, org.ID)
+
+	var foundUser *user.User
+	if loginState.LinkUserID != nil {
+		foundUser, err = s.linkIdentity(ctx, provider, claims, *loginState.LinkUserID)
+	} else {
+		foundUser, err = s.federatedUser(ctx, provider, claims)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAccountLock(ctx, foundUser); err != nil {
+		if errors.Is(err, ErrAccountLocked) {
+			s.recordLoginFailure(ctx, foundUser, foundUser.Email, "locked")
+		}
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, foundUser, LoginMethodOIDC)
+}
+
+func (s *federationService) ListIdentities(ctx context.Context, userID uuid.UUID) ([]federation.Identity, error) {
+	return s.identities.ListForUser(ctx, userID)
+}
+
+// Unlink keeps at least one way to sign in: users provisioned by a provider
+// have no password until they reset it.
+func (s *federationService) Unlink(ctx context.Context, userID uuid.UUID, identityID uuid.UUID) error {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	identities, err := s.identities.ListForUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	index := slices.IndexFunc(identities, func(i federation.Identity) bool { return i.ID == identityID })
+	if index < 0 {
+		return federation.ErrIdentityNotFound
+	}
+	if foundUser.PasswordHash == "" && len(identities) == 1 {
+		return federation.ErrLastLoginMethod
+	}
+
+	if err := s.identities.Delete(ctx, userID, identityID); err != nil {
+		return err
+	}
+
+	s.recordUserEvent(ctx, userID, audit.ActionIdentityUnlinked, audit.OutcomeSuccess, audit.Metadata{"provider": identities[index].Provider})
+	return nil
+}
+
+// federatedUser returns the user linked to the subject. On the first sign-in
+// it links the user with the same verified e-mail, or provisions one, as the
+// provider allows.
+func (s *federationService) federatedUser(ctx context.Context, provider federation.Provider, claims *federation.Claims) (*user.User, error) {
+	identity, err := s.identities.FindBySubject(ctx, provider.Name, claims.Subject)
+	if err == nil {
+		if err := s.identities.UpdateLastLogin(ctx, identity.ID); err != nil {
+			log.Printf("[ERROR] Failed to update last login of identity %s: %v", identity.ID, err)
+		}
+		return s.repo.FindByID(ctx, identity.UserID)
+	}
+	if !errors.Is(err, federation.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	email := normalizeEmail(claims.Email)
+	if email == "" || !claims.EmailVerified {
+		return nil, federation.ErrEmailNotVerified
+	}
+	if !provider.AllowsEmail(email) {
+		return nil, federation.ErrDomainNotAllowed
+	}
+
+	existing, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		if !provider.LinkExistingAccounts {
+			return nil, federation.ErrAccountLinkRequired
+		}
+		if err := s.createIdentity(ctx, provider, claims, existing, "email"); err != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+
+	if !provider.JITProvisioning {
+		return nil, federation.ErrAccountNotFound
+	}
+	return s.provisionUser(ctx, provider, claims, email)
+}
+
+func (s *federationService) linkIdentity(ctx context.Context, provider federation.Provider, claims *federation.Claims, userID uuid.UUID) (*user.User, error) {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, federation.ErrOrganizationMismatch
+		}
+		return nil, err
+	}
+
+	identity, err := s.identities.FindBySubject(ctx, provider.Name, claims.Subject)
+	if err == nil {
+		if identity.UserID != userID {
+			return nil, federation.ErrIdentityAlreadyLinked
+		}
+		return foundUser, nil
+	}
+	if !errors.Is(err, federation.ErrIdentityNotFound) {
+		return nil, err
+	}
+
+	if err := s.createIdentity(ctx, provider, claims, foundUser, "user"); err != nil {
+		return nil, err
+	}
+	return foundUser, nil
+}
+
+func (s *federationService) provisionUser(ctx context.Context, provider federation.Provider, claims *federation.Claims, email string) (*user.User, error) {
+	name := strings.TrimSpace(claims.Name)
+	if len(name) < 3 {
+		name, _, _ = strings.Cut(email, "@")
+	}
+
+	now := time.Now()
+	newUser := &user.User{
+		Model: base.Model{
+			ID: uuid.New(),
+		},
+		Name:              name,
+		Email:             email,
+		Role:              user.RoleUser,
+		Status:            user.StatusActive,
+		PasswordChangedAt: &now,
+	}
+	if err := s.repo.Create(ctx, newUser); err != nil {
+		return nil, err
+	}
+	if err := s.rbacRepo.AddUserRole(ctx, newUser.ID, rbac.RoleUser); err != nil {
+		return nil, err
+	}
+	if err := s.orgRepo.AddMember(ctx, newUser.OrganizationID, newUser.ID, organization.RoleMember); err != nil {
+		return nil, err
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		TargetID: &newUser.ID,
+		Action:   audit.ActionUserProvisioned,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: audit.Metadata{"provider": provider.Name},
+	})
+
+	if err := s.createIdentity(ctx, provider, claims, newUser, "provisioning"); err != nil {
+		return nil, err
+	}
+	return newUser, nil
+}
+
+// createIdentity links the subject to u. source tells what allowed the link:
+// provisioning, a matching e-mail or the user itself.
+func (s *federationService) createIdentity(ctx context.Context, provider federation.Provider, claims *federation.Claims, u *user.User, source string) error {
+	identities, err := s.identities.ListForUser(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+	if slices.ContainsFunc(identities, func(i federation.Identity) bool { return i.Provider == provider.Name }) {
+		return federation.ErrProviderAlreadyLinked
+	}
+
+	now := time.Now()
+	identity := &federation.Identity{
+		ID:             uuid.New(),
+		OrganizationID: u.OrganizationID,
+		UserID:         u.ID,
+		Provider:       provider.Name,
+		Subject:        claims.Subject,
+		Email:          normalizeEmail(claims.Email),
+		LastLoginAt:    &now,
+	}
+	if err := s.identities.Create(ctx, identity); err != nil {
+		return err
+	}
+
+	s.recordUserEvent(ctx, u.ID, audit.ActionIdentityLinked, audit.OutcomeSuccess, audit.Metadata{
+		"provider": provider.Name,
+		"source":   source,
+	})
+	return nil
+}
+
+// authorize stores the state, nonce and PKCE verifier of the flow and returns
+// the authorization URL of the provider.
+func (s *federationService) authorize(ctx context.Context, provider federation.Provider, linkUserID *uuid.UUID) (string, string, error) {
+	var values [3]string
+	for i := range values {
+		value, err := randomToken()
+		if err != nil {
+			return "", "", err
+		}
+		values[i] = value
+	}
+	state, nonce, codeVerifier := values[0], values[1], values[2]
+
+	loginState := federation.LoginState{
+		Provider:     provider.Name,
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		LinkUserID:   linkUserID,
+	}
+	ttl := time.Duration(s.cfg.OIDCStateTTLMin) * time.Minute
+	if err := s.cacheRepo.SaveFederationState(ctx, state, loginState, ttl); err != nil {
+		return "", "", err
+	}
+
+	challenge := sha256.Sum256([]byte(codeVerifier))
+	authorizationURL, err := s.client.AuthorizationURL(ctx, provider, federation.AuthorizationRequest{
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: base64.RawURLEncoding.EncodeToString(challenge[:]),
+		RedirectURI:   s.cfg.OIDCRedirectURL,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return authorizationURL, state, nil
+}
+
+func (s *federationService) provider(name string) (federation.Provider, error) {
+	for _, p := range s.providers {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return federation.Provider{}, federation.ErrProviderNotFound
+}
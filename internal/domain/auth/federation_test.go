@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/google/uuid"
+)
+
+func TestFederationProvisionUser(t *testing.T) {
+	f := newTestAuthService(t)
+	s := &federationService{authService: f.authService}
+	orgID := uuid.New()
+	ctx := tenant.WithOrganization(context.Background(), orgID)
+
+	provider := federation.Provider{Name: "corp"}
+	claims := &federation.Claims{Subject: "sub-42", Email: "Ana@Example.com", EmailVerified: true, Name: "Ana Souza"}
+
+	u, err := s.provisionUser(ctx, provider, claims, "ana@example.com")
+	if err != nil {
+		t.Fatalf("provisionUser: %v", err)
+	}
+
+	if u.PasswordChangedAt == nil {
+		t.Error("provisioned user has no password_changed_at")
+	}
+	if u.Name != "Ana Souza" || u.Email != "ana@example.com" || u.OrganizationID != orgID || u.PasswordHash != "" {
+		t.Errorf("user = %+v, want a passwordless Ana in the organization", u)
+	}
+	if !slices.Equal(f.rbac.roles[u.ID], []string{rbac.RoleUser}) {
+		t.Errorf("roles = %v, want the default user role", f.rbac.roles[u.ID])
+	}
+	if f.orgs.members[u.ID] != organization.RoleMember {
+		t.Errorf("membership = %q, want %q", f.orgs.members[u.ID], organization.RoleMember)
+	}
+	if len(f.identities.identities) != 1 || f.identities.identities[0].Subject != "sub-42" || f.identities.identities[0].UserID != u.ID {
+		t.Errorf("identities = %+v, want sub-42 linked to the user", f.identities.identities)
+	}
+	if want := []string{audit.ActionUserProvisioned, audit.ActionIdentityLinked}; !slices.Equal(f.auditLog.actions(), want) {
+		t.Errorf("audit actions = %v, want %v", f.auditLog.actions(), want)
+	}
+}
+
+func TestFederationProvisionUserNameFallback(t *testing.T) {
+	f := newTestAuthService(t)
+	s := &federationService{authService: f.authService}
+	ctx := tenant.WithOrganization(context.Background(), uuid.New())
+
+	u, err := s.provisionUser(ctx, federation.Provider{Name: "corp"}, &federation.Claims{Subject: "sub-7", Email: "jo@example.com"}, "jo@example.com")
+	if err != nil {
+		t.Fatalf("provisionUser: %v", err)
+	}
+	if u.Name != "jo" {
+		t.Errorf("name = %q, want the local part of the e-mail", u.Name)
+	}
+}
@@ -0,0 +1,191 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/dpop"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// Token exchange (RFC 8693) identifiers accepted by the token endpoint.
+const (
+	GrantTypeTokenExchange = "urn:ietf:params:oauth:grant-type:token-exchange"
+	TokenTypeAccessToken   = "urn:ietf:params:oauth:token-type:access_token"
+	// TokenTypeUserID is the subject_token_type of an impersonation, whose
+	// subject_token is the ID of the target user.
+	TokenTypeUserID = "urn:chameleon:params:oauth:token-type:user_id"
+)
+
+// Impersonate exchanges the access token of an admin holding the
+// users:impersonate permission for a short-lived access token of another user
+// of the same organization. The token carries an act claim naming the admin,
+// has no refresh token, and every attempt is audited.
+func (s *authService) Impersonate(ctx context.Context, actorToken string, targetID uuid.UUID) (*user.ImpersonationResult, error) {
+	actor, err := s.authenticateActor(ctx, actorToken)
+	if err != nil {
+		s.recordImpersonation(ctx, nil, targetID, audit.OutcomeFailure, audit.Metadata{"reason": err.Error()})
+		return nil, err
+	}
+	ctx = tenant.WithOrganization(ctx, actor.OrganizationID)
+
+	target, err := s.impersonationTarget(ctx, actor, targetID)
+	if err != nil {
+		s.recordImpersonation(ctx, &actor.ID, targetID, audit.OutcomeFailure, audit.Metadata{"reason": err.Error()})
+		return nil, err
+	}
+
+	ttl := time.Duration(s.cfg.ImpersonationTTLMin) * time.Minute
+	claims, err := s.accessTokenClaims(ctx, target, ttl)
+	if err != nil {
+		return nil, err
+	}
+	claims["act"] = map[string]string{"sub": actor.ID.String(), "name": actor.Name}
+
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(s.cfg.JWTSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	s.recordImpersonation(ctx, &actor.ID, targetID, audit.OutcomeSuccess, audit.Metadata{
+		"jti":        claims["jti"].(string),
+		"expires_in": ttl.String(),
+	})
+
+	return &user.ImpersonationResult{
+		AccessToken: accessToken,
+		ExpiresIn:   ttl,
+		User:        target,
+		ActorID:     actor.ID,
+	}, nil
+}
+
+// authenticateActor validates the actor token as the common AuthMiddleware
+// would, and requires the users:impersonate permission. Restricted and
+// impersonation tokens are not accepted.
+func (s *authService) authenticateActor(ctx context.Context, actorToken string) (*user.User, error) {
+	token, err := s.parseAndValidateToken(actorToken)
+	if err != nil {
+		return nil, ErrInvalidActorToken
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, ErrInvalidActorToken
+	}
+
+	if typ, _ := claims["typ"].(string); typ != "access" {
+		return nil, ErrInvalidActorToken
+	}
+	if restricted, _ := claims["pwd_change_required"].(bool); restricted {
+		return nil, ErrInvalidActorToken
+	}
+	if _, impersonating := claims["act"]; impersonating {
+		return nil, ErrImpersonationForbidden
+	}
+	// A bound actor token needs a proof of its key at the token endpoint.
+	if jkt := boundThumbprint(claims); jkt != "" && jkt != dpop.Thumbprint(ctx) {
+		return nil, ErrInvalidActorToken
+	}
+
+	jti, _ := claims["jti"].(string)
+	blacklisted, err := s.cacheRepo.IsTokenBlacklisted(ctx, jti)
+	if err != nil {
+		return nil, err
+	}
+	if jti == "" || blacklisted {
+		return nil, ErrInvalidActorToken
+	}
+
+	granted, _ := claims["permissions"].([]interface{})
+	if !slices.Contains(granted, interface{}(rbac.PermUsersImpersonate)) {
+		return nil, ErrImpersonationForbidden
+	}
+
+	actorID, err := uuid.Parse(stringClaim(claims, "sub"))
+	if err != nil {
+		return nil, ErrInvalidActorToken
+	}
+	organizationID, err := organizationFromClaims(claims)
+	if err != nil {
+		return nil, ErrInvalidActorToken
+	}
+
+	actor, err := s.repo.FindByID(tenant.WithOrganization(ctx, organizationID), actorID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrInvalidActorToken
+		}
+		return nil, err
+	}
+
+	tokenVersion, _ := claims["token_version"].(float64)
+	if actor.Status != user.StatusActive || int(tokenVersion) != actor.TokenVersion {
+		return nil, ErrInvalidActorToken
+	}
+	return actor, nil
+}
+
+// impersonationTarget returns the active user of the actor's organization to
+// impersonate. The token of the target carries the target's permissions, so
+// the actor must currently hold every one of them; users who may impersonate
+// others cannot be impersonated at all.
+func (s *authService) impersonationTarget(ctx context.Context, actor *user.User, targetID uuid.UUID) (*user.User, error) {
+	if targetID == actor.ID {
+		return nil, ErrImpersonationForbidden
+	}
+
+	target, err := s.repo.FindByID(ctx, targetID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status != user.StatusActive {
+		return nil, ErrAccountInactive
+	}
+	if err := s.checkDirectoryAccount(ctx, target); err != nil {
+		if errors.As(err, new(*AccountStatusError)) {
+			return nil, ErrAccountInactive
+		}
+		return nil, err
+	}
+
+	_, permissions, err := s.userPermissions(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(permissions, rbac.PermUsersImpersonate) {
+		return nil, ErrImpersonationForbidden
+	}
+	_, actorPermissions, err := s.userPermissions(ctx, actor)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(actorPermissions, permission) {
+			return nil, fmt.Errorf("%w: target holds %s, which the actor lacks", ErrImpersonationForbidden, permission)
+		}
+	}
+	return target, nil
+}
+
+func (s *authService) recordImpersonation(ctx context.Context, actorID *uuid.UUID, targetID uuid.UUID, outcome string, metadata audit.Metadata) {
+	s.auditLog.Record(ctx, audit.Event{
+		ActorID:  actorID,
+		TargetID: &targetID,
+		Action:   audit.ActionImpersonation,
+		Outcome:  outcome,
+		Metadata: metadata,
+	})
+}
+
+func stringClaim(claims jwt.MapClaims, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// newImpersonationTest returns a service with a support agent holding
+// users:impersonate and users:read, and the actor token of the agent.
+func newImpersonationTest(t *testing.T) (*testAuthService, context.Context, string) {
+	t.Helper()
+	f := newTestAuthService(t)
+	f.cfg.ImpersonationTTLMin = 15
+	f.rbac.permissions = map[string][]string{
+		"support":  {rbac.PermUsersImpersonate, rbac.PermUsersRead},
+		"reader":   {rbac.PermUsersRead},
+		"security": {rbac.PermUsersRead, rbac.PermRolesWrite},
+		"admin":    {rbac.PermUsersImpersonate, rbac.PermUsersRead, rbac.PermRolesWrite},
+	}
+	ctx := tenant.WithOrganization(context.Background(), uuid.New())
+
+	actor := f.addUser(t, ctx, "support")
+	token, err := f.createAccessToken(ctx, actor, newAuthContext("pwd"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f, ctx, token
+}
+
+// addUser creates an active user holding role.
+func (f *testAuthService) addUser(t *testing.T, ctx context.Context, role string) *user.User {
+	t.Helper()
+	now := time.Now()
+	u := &user.User{
+		Model:             base.Model{ID: uuid.New()},
+		Name:              role + " user",
+		Email:             role + "@example.com",
+		Status:            user.StatusActive,
+		PasswordChangedAt: &now,
+	}
+	if err := f.users.Create(ctx, u); err != nil {
+		t.Fatal(err)
+	}
+	f.rbac.roles[u.ID] = []string{role}
+	return u
+}
+
+func TestImpersonate(t *testing.T) {
+	f, ctx, actorToken := newImpersonationTest(t)
+	target := f.addUser(t, ctx, "reader")
+
+	result, err := f.Impersonate(ctx, actorToken, target.ID)
+	if err != nil {
+		t.Fatalf("Impersonate: %v", err)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(result.AccessToken, claims); err != nil {
+		t.Fatal(err)
+	}
+	if claims["sub"] != target.ID.String() || claims["act"] == nil {
+		t.Errorf("claims = %v, want the target with an act claim", claims)
+	}
+}
+
+func TestImpersonateRefusesPrivilegedTargets(t *testing.T) {
+	tests := []struct {
+		name string
+		role string
+	}{
+		// security holds roles:write, which the agent lacks.
+		{name: "permission the actor lacks", role: "security"},
+		{name: "target may impersonate", role: "admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, ctx, actorToken := newImpersonationTest(t)
+			target := f.addUser(t, ctx, tt.role)
+
+			_, err := f.Impersonate(ctx, actorToken, target.ID)
+			if !errors.Is(err, ErrImpersonationForbidden) {
+				t.Fatalf("Impersonate error = %v, want ErrImpersonationForbidden", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+)
+
+const magicLinkSignatureContext = "magic-link:"
+
+// RequestMagicLink always returns a fresh nonce, so the response does not tell
+// whether the account exists.
+func (s *authService) RequestMagicLink(ctx context.Context, organizationSlug, email string) (string, error) {
+	nonce, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+
+	org, err := s.resolveOrganization(ctx, organizationSlug)
+	if err != nil {
+		if errors.Is(err, organization.ErrOrganizationNotFound) {
+			return nonce, nil
+		}
+		return "", err
+	}
+	ctx = tenant.WithOrganization(ctx, org.ID)
+
+	foundUser, err := s.repo.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return "", err
+	}
+	if foundUser == nil {
+		log.Printf("[INFO] Magic link requested for non-existent email: %s", normalizeEmail(email))
+		return nonce, nil
+	}
+
+	token, err := s.signedMagicLinkToken()
+	if err != nil {
+		return "", err
+	}
+
+	ticket := MagicLinkTicket{
+		OrganizationID: foundUser.OrganizationID,
+		UserID:         foundUser.ID,
+		NonceHash:      hashNonce(nonce),
+	}
+	ttl := time.Duration(s.cfg.MagicLinkTTLMin) * time.Minute
+	if err := s.cacheRepo.SaveMagicLink(ctx, token, ticket, ttl); err != nil {
+		return "", err
+	}
+
+	err = s.mailer.Send(ctx, notification.Message{
+		To:      foundUser.Email,
+		Subject: "Seu link de acesso",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nPara entrar na sua conta, acesse o link abaixo no mesmo navegador em que ele foi solicitado:\n%s/login/magic-link?token=%s\n\nO link expira em %d minutos e só pode ser usado uma vez. Se não foi você, ignore este e-mail.",
+			foundUser.Name, s.cfg.AppPublicURL, url.QueryEscape(token), s.cfg.MagicLinkTTLMin,
+		),
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to send magic link to user %s: %v", foundUser.ID, err)
+	}
+	return nonce, nil
+}
+
+// LoginWithMagicLink consumes the link even when the nonce does not match, so
+// a forwarded link is burned by the first attempt from another browser.
+func (s *authService) LoginWithMagicLink(ctx context.Context, token, nonce string) (*user.LoginResult, error) {
+	if !s.validMagicLinkSignature(token) {
+		return nil, ErrInvalidMagicLink
+	}
+
+	ticket, err := s.cacheRepo.VerifyAndConsumeMagicLink(ctx, token)
+	if err != nil {
+		return nil, ErrInvalidMagicLink
+	}
+	ctx = tenant.WithOrganization(ctx, ticket.OrganizationID)
+
+	foundUser, err := s.repo.FindByID(ctx, ticket.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, ErrInvalidMagicLink
+		}
+		return nil, err
+	}
+
+	if nonce == "" || subtle.ConstantTimeCompare([]byte(hashNonce(nonce)), []byte(ticket.NonceHash)) != 1 {
+		s.recordLoginFailure(ctx, foundUser, foundUser.Email, "magic_link_nonce_mismatch")
+		return nil, ErrInvalidMagicLink
+	}
+
+	if err := s.checkAccountLock(ctx, foundUser); err != nil {
+		if errors.Is(err, ErrAccountLocked) {
+			s.recordLoginFailure(ctx, foundUser, foundUser.Email, "locked")
+		}
+		return nil, err
+	}
+
+	// The link already proves control of the mailbox an e-mail OTP would be
+	// sent to, so no code is asked here.
+	return s.completeLogin(ctx, foundUser, LoginMethodMagicLink)
+}
+
+// signedMagicLinkToken returns a random value and its HMAC, so forged tokens
+// are rejected before reaching the cache.
+func (s *authService) signedMagicLinkToken() (string, error) {
+	value, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	return value + "." + s.magicLinkSignature(value), nil
+}
+
+func (s *authService) validMagicLinkSignature(token string) bool {
+	value, signature, found := strings.Cut(token, ".")
+	if !found || value == "" {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(s.magicLinkSignature(value)))
+}
+
+func (s *authService) magicLinkSignature(value string) string {
+	mac := hmac.New(sha256.New, []byte(s.cfg.JWTSecret))
+	mac.Write([]byte(magicLinkSignatureContext + value))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func randomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashNonce(nonce string) string {
+	sum := sha256.Sum256([]byte(nonce))
+	return hex.EncodeToString(sum[:])
+}
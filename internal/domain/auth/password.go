@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const argon2idPrefix = "$argon2id$"
+
+// Bounds of the argon2id hashes accepted. Hashes are checked on every login
+// attempt for the e-mail, before any authentication, so their cost must stay
+// within what a login may spend. Memory is in KiB.
+const (
+	maxArgon2Memory  = 256 * 1024
+	maxArgon2Time    = 10
+	maxArgon2Threads = 16
+	minArgon2SaltLen = 8
+	minArgon2KeyLen  = 16
+	maxArgon2KeyLen  = 64
+	maxBcryptCost    = 15
+)
+
+var errPasswordMismatch = errors.New("password does not match")
+
+// comparePassword checks the password against the stored hash: bcrypt, as
+// produced by this service, or argon2id in the PHC string format
+// ($argon2id$v=19$m=65536,t=3,p=4$<salt>$<hash>), which imported users may
+// carry from their previous identity provider.
+func comparePassword(hash string, password string) error {
+	if !strings.HasPrefix(hash, argon2idPrefix) {
+		return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	}
+
+	params, salt, key, err := parseArgon2id(hash)
+	if err != nil {
+		return err
+	}
+	derived := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.threads, uint32(len(key)))
+	if subtle.ConstantTimeCompare(derived, key) != 1 {
+		return errPasswordMismatch
+	}
+	return nil
+}
+
+// isSupportedPasswordHash reports whether an imported hash can be verified
+// by comparePassword.
+func isSupportedPasswordHash(hash string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		_, _, _, err := parseArgon2id(hash)
+		return err == nil
+	}
+	cost, err := bcrypt.Cost([]byte(hash))
+	return err == nil && cost <= maxBcryptCost
+}
+
+type argon2Params struct {
+	memory  uint32
+	time    uint32
+	threads uint8
+}
+
+func parseArgon2id(hash string) (argon2Params, []byte, []byte, error) {
+	var params argon2Params
+
+	parts := strings.Split(hash, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, errors.New("malformed argon2id hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, errors.New("unsupported argon2id version")
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.threads); err != nil {
+		return params, nil, nil, errors.New("malformed argon2id parameters")
+	}
+	if params.memory == 0 || params.time == 0 || params.threads == 0 {
+		return params, nil, nil, errors.New("malformed argon2id parameters")
+	}
+	if params.memory > maxArgon2Memory || params.time > maxArgon2Time || params.threads > maxArgon2Threads {
+		return params, nil, nil, errors.New("argon2id parameters out of range")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) < minArgon2SaltLen {
+		return params, nil, nil, errors.New("malformed argon2id salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) < minArgon2KeyLen || len(key) > maxArgon2KeyLen {
+		return params, nil, nil, errors.New("malformed argon2id key")
+	}
+	return params, salt, key, nil
+}
@@ -0,0 +1,183 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/personaltoken"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// personalTokenPrefixLength is how much of a token is kept to identify it:
+// TokenPrefix and 8 random characters.
+const personalTokenPrefixLength = len(personaltoken.TokenPrefix) + 8
+
+// personalTokenService issues the long-lived tokens users create for scripts.
+// A token never grants more than the user holds when it is used: its
+// permissions are its scopes the user still has, and it stops working when
+// the account is no longer active, is scheduled for deletion or has its
+// sessions revoked.
+type personalTokenService struct {
+	*authService
+	tokenRepo personaltoken.IRepository
+}
+
+func NewPersonalTokenService(repo user.IRepository, cacheRepo ICacheRepository, rbacRepo rbac.IRepository, orgRepo organization.IRepository, auditLog audit.IService, cfg *config.Config, tokenRepo personaltoken.IRepository) personaltoken.IService {
+	return &personalTokenService{
+		authService: newAuthService(repo, cacheRepo, rbacRepo, orgRepo, auditLog, nil, nil, cfg, nil, nil),
+		tokenRepo:   tokenRepo,
+	}
+}
+
+func (s *personalTokenService) Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresInDays int) (*personaltoken.Token, string, error) {
+	if expiresInDays == 0 {
+		expiresInDays = s.cfg.PersonalTokenDefaultTTLDays
+	}
+	if expiresInDays < 1 || expiresInDays > s.cfg.PersonalTokenMaxTTLDays {
+		return nil, "", personaltoken.ErrInvalidExpiry
+	}
+
+	owner, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	_, permissions, err := s.userPermissions(ctx, owner)
+	if err != nil {
+		return nil, "", err
+	}
+	scopes = normalizeScopes(scopes)
+	for _, scope := range scopes {
+		if !slices.Contains(permissions, scope) {
+			return nil, "", personaltoken.ErrInvalidScope
+		}
+	}
+
+	count, err := s.tokenRepo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= personaltoken.MaxTokensPerUser {
+		return nil, "", personaltoken.ErrTooManyTokens
+	}
+
+	secret, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	rawToken := personaltoken.TokenPrefix + secret
+
+	token := &personaltoken.Token{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Name:         strings.TrimSpace(name),
+		Prefix:       rawToken[:personalTokenPrefixLength],
+		TokenHash:    hashNonce(rawToken),
+		Scopes:       strings.Join(scopes, " "),
+		TokenVersion: owner.TokenVersion,
+		ExpiresAt:    time.Now().AddDate(0, 0, expiresInDays),
+	}
+	if err := s.tokenRepo.Create(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	s.recordUserEvent(ctx, userID, audit.ActionPersonalTokenCreated, audit.OutcomeSuccess, audit.Metadata{
+		"token_id": token.ID.String(),
+		"name":     token.Name,
+		"scopes":   token.Scopes,
+	})
+	return token, rawToken, nil
+}
+
+func (s *personalTokenService) List(ctx context.Context, userID uuid.UUID) ([]personaltoken.Token, error) {
+	owner, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	tokens, err := s.tokenRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tokens {
+		tokens[i].Revoked = tokens[i].TokenVersion != owner.TokenVersion
+	}
+	return tokens, nil
+}
+
+func (s *personalTokenService) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	if err := s.tokenRepo.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+
+	s.recordUserEvent(ctx, userID, audit.ActionPersonalTokenRevoked, audit.OutcomeSuccess, audit.Metadata{"token_id": id.String()})
+	return nil
+}
+
+func (s *personalTokenService) Authenticate(ctx context.Context, rawToken string) (*personaltoken.Principal, error) {
+	if !strings.HasPrefix(rawToken, personaltoken.TokenPrefix) {
+		return nil, personaltoken.ErrInvalidToken
+	}
+
+	token, err := s.tokenRepo.FindByHash(ctx, hashNonce(rawToken))
+	if err != nil {
+		return nil, err
+	}
+	if token.Expired(time.Now()) {
+		return nil, personaltoken.ErrInvalidToken
+	}
+	ctx = tenant.WithOrganization(ctx, token.OrganizationID)
+
+	owner, err := s.repo.FindByID(ctx, token.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, personaltoken.ErrInvalidToken
+		}
+		return nil, err
+	}
+	if token.TokenVersion != owner.TokenVersion {
+		return nil, personaltoken.ErrInvalidToken
+	}
+	if owner.Status != user.StatusActive && !owner.SuspensionExpired(time.Now()) {
+		return nil, personaltoken.ErrInvalidToken
+	}
+	if owner.DeletionScheduledFor != nil || s.passwordChangeRequired(owner) {
+		return nil, personaltoken.ErrInvalidToken
+	}
+
+	_, permissions, err := s.userPermissions(ctx, owner)
+	if err != nil {
+		return nil, err
+	}
+	granted := make([]string, 0, len(permissions))
+	for _, scope := range token.ScopeList() {
+		if slices.Contains(permissions, scope) {
+			granted = append(granted, scope)
+		}
+	}
+
+	if err := s.tokenRepo.Touch(ctx, token.ID); err != nil {
+		log.Printf("[ERROR] Failed to record use of personal access token %s: %v", token.ID, err)
+	}
+	return &personaltoken.Principal{Token: token, User: owner, Permissions: granted}, nil
+}
+
+// normalizeScopes trims the scopes and drops blanks and duplicates.
+func normalizeScopes(scopes []string) []string {
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope != "" && !slices.Contains(normalized, scope) {
+			normalized = append(normalized, scope)
+		}
+	}
+	return normalized
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+const maxMetadataBytes = 8 * 1024
+
+var localePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+type profileService struct {
+	repo        user.IRepository
+	cacheRepo   ICacheRepository
+	orgRepo     organization.IRepository
+	mailer      notification.IMailer
+	cfg         *config.Config
+	directories []directory.IBackend
+}
+
+func NewProfileService(repo user.IRepository, cacheRepo ICacheRepository, orgRepo organization.IRepository, mailer notification.IMailer, cfg *config.Config, directories []directory.IBackend) user.IProfileService {
+	return &profileService{
+		repo:        repo,
+		cacheRepo:   cacheRepo,
+		orgRepo:     orgRepo,
+		mailer:      mailer,
+		cfg:         cfg,
+		directories: directories,
+	}
+}
+
+func (s *profileService) GetProfile(ctx context.Context, userID uuid.UUID) (*user.User, error) {
+	return s.repo.FindByID(ctx, userID)
+}
+
+func (s *profileService) UpdateProfile(ctx context.Context, userID uuid.UUID, update user.ProfileUpdate, expectedRevision *time.Time) (*user.User, error) {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if expectedRevision != nil && !foundUser.Revision().Equal(*expectedRevision) {
+		return nil, ErrProfileModified
+	}
+
+	if update.Name != nil {
+		foundUser.Name = strings.TrimSpace(*update.Name)
+	}
+
+	if update.Locale != nil {
+		locale := strings.TrimSpace(*update.Locale)
+		if locale != "" && !localePattern.MatchString(locale) {
+			return nil, ErrInvalidLocale
+		}
+		foundUser.Locale = optionalString(locale)
+	}
+
+	if update.Timezone != nil {
+		timezone := strings.TrimSpace(*update.Timezone)
+		if timezone != "" {
+			if _, err := time.LoadLocation(timezone); err != nil {
+				return nil, ErrInvalidTimezone
+			}
+		}
+		foundUser.Timezone = optionalString(timezone)
+	}
+
+	if update.Metadata != nil {
+		merged := user.Metadata{}
+		for k, v := range foundUser.Metadata {
+			merged[k] = v
+		}
+		for k, v := range update.Metadata {
+			if v == nil {
+				delete(merged, k)
+				continue
+			}
+			merged[k] = v
+		}
+
+		encoded, err := json.Marshal(merged)
+		if err != nil {
+			return nil, err
+		}
+		if len(encoded) > maxMetadataBytes {
+			return nil, ErrMetadataTooLarge
+		}
+		foundUser.Metadata = merged
+	}
+
+	if err := s.repo.UpdateProfile(ctx, foundUser, expectedRevision); err != nil {
+		return nil, err
+	}
+
+	return foundUser, nil
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
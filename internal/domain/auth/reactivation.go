@@ -0,0 +1,94 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+func (s *authService) RequestReactivation(ctx context.Context, organizationSlug, email string) error {
+	org, err := s.resolveOrganization(ctx, organizationSlug)
+	if err != nil {
+		if errors.Is(err, organization.ErrOrganizationNotFound) {
+			return nil
+		}
+		return err
+	}
+	ctx = tenant.WithOrganization(ctx, org.ID)
+
+	foundUser, err := s.repo.FindByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return err
+	}
+	// Accounts deactivated by an admin, suspended or banned need an admin.
+	if foundUser == nil || !foundUser.SelfDeactivated() {
+		return nil
+	}
+
+	reactivationToken := uuid.New().String()
+	ttl := time.Duration(s.cfg.ReactivationTTLMin) * time.Minute
+	if err := s.cacheRepo.SaveReactivationToken(ctx, resetSubject(foundUser), reactivationToken, ttl); err != nil {
+		return err
+	}
+
+	err = s.mailer.Send(ctx, notification.Message{
+		To:      foundUser.Email,
+		Subject: "Reative sua conta",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nRecebemos um pedido para reativar a sua conta. Para reativá-la, acesse:\n%s/reactivate?token=%s\n\nO link expira em %d minutos. Se não foi você, ignore este e-mail.",
+			foundUser.Name, s.cfg.AppPublicURL, reactivationToken, s.cfg.ReactivationTTLMin,
+		),
+	})
+	if err != nil {
+		// Failing the request would tell the caller that the account exists.
+		log.Printf("[ERROR] Failed to send reactivation email to user %s: %v", foundUser.ID, err)
+	}
+	return nil
+}
+
+func (s *authService) Reactivate(ctx context.Context, reactivationToken string) error {
+	subject, err := s.cacheRepo.VerifyAndConsumeReactivationToken(ctx, reactivationToken)
+	if err != nil {
+		return ErrInvalidReactivationToken
+	}
+
+	organizationID, userID, err := parseResetSubject(subject)
+	if err != nil {
+		return ErrInvalidReactivationToken
+	}
+	ctx = tenant.WithOrganization(ctx, organizationID)
+
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return ErrInvalidReactivationToken
+		}
+		return err
+	}
+	// An admin may have changed the status after the link was sent.
+	if !foundUser.SelfDeactivated() {
+		return ErrInvalidReactivationToken
+	}
+
+	err = s.repo.ChangeStatus(ctx, userID, user.StatusChange{
+		Status:    user.StatusActive,
+		Source:    user.SourceSelf,
+		Reason:    "reactivated by e-mail",
+		ChangedBy: &userID,
+	})
+	if err != nil {
+		return err
+	}
+
+	s.recordUserEvent(ctx, userID, audit.ActionReactivated, audit.OutcomeSuccess, nil)
+	return nil
+}
@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// Reauthenticate checks the password again, and the code of a step-up
+// challenge when given, then replaces the tokens of the current session with
+// tokens carrying the new auth_time, amr and acr.
+func (s *authService) Reauthenticate(ctx context.Context, userID uuid.UUID, req user.Reauthentication) (*user.LoginResult, error) {
+	foundUser, err := s.repo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAccountLock(ctx, foundUser); err != nil {
+		return nil, err
+	}
+
+	if err := s.verifyPassword(ctx, foundUser, req.Password); err != nil {
+		if errors.Is(err, ErrInvalidCurrentPassword) {
+			s.recordUserEvent(ctx, userID, audit.ActionReauthenticated, audit.OutcomeFailure, audit.Metadata{"reason": "invalid_current_password"})
+			s.registerFailedLogin(ctx, foundUser)
+		}
+		return nil, err
+	}
+
+	methods := []string{AMRPassword}
+	if req.OTPChallenge != "" {
+		challenge, err := s.verifyOTP(ctx, req.OTPChallenge, req.OTPCode, OTPPurposeStepUp)
+		if challenge != nil && challenge.UserID != userID {
+			err = ErrInvalidOTP
+		}
+		if err != nil {
+			s.recordUserEvent(ctx, userID, audit.ActionReauthenticated, audit.OutcomeFailure, audit.Metadata{"reason": "invalid_otp"})
+			s.registerFailedLogin(ctx, foundUser)
+			return nil, err
+		}
+		methods = append(methods, AMROTP)
+	}
+
+	if req.RefreshToken != "" {
+		tokenUserID, err := s.cacheRepo.VerifyAndConsumeRefreshToken(ctx, req.RefreshToken)
+		if err != nil || tokenUserID != userID.String() {
+			return nil, ErrInvalidRefreshToken
+		}
+	}
+
+	authn := newAuthContext(methods...)
+	result := &user.LoginResult{User: foundUser}
+
+	result.AccessToken, err = s.createAccessToken(ctx, foundUser, authn)
+	if err != nil {
+		return nil, err
+	}
+	result.RefreshToken, err = s.createRefreshToken(ctx, foundUser, authn)
+	if err != nil {
+		return nil, err
+	}
+	refreshTTL := time.Duration(s.cfg.RefreshTokenTTLDays) * 24 * time.Hour
+	if err := s.cacheRepo.SaveRefreshToken(ctx, userID.String(), result.RefreshToken, refreshTTL); err != nil {
+		return nil, err
+	}
+
+	if err := s.invalidateToken(ctx, req.AccessToken); err != nil {
+		log.Printf("[ERROR] Failed to invalidate the access token replaced by re-authentication of user %s: %v", userID, err)
+	}
+	if err := s.cacheRepo.ClearFailedLogins(ctx, userID.String()); err != nil {
+		log.Printf("[ERROR] Failed to clear failed logins for user %s: %v", userID, err)
+	}
+
+	s.recordUserEvent(ctx, userID, audit.ActionReauthenticated, audit.OutcomeSuccess, audit.Metadata{"acr": authn.ACR()})
+	return result, nil
+}
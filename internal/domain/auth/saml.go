@@ -0,0 +1,200 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/saml"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+)
+
+// samlLoginCodeTTL only has to cover the redirect from the assertion consumer
+// service to the frontend and its call to CompleteLogin.
+const samlLoginCodeTTL = 2 * time.Minute
+
+// samlService signs users in through SAML 2.0 identity providers and issues
+// the same tokens as the password login.
+type samlService struct {
+	*authService
+	sp        saml.IServiceProvider
+	providers []saml.IdentityProvider
+}
+
+func NewSAMLService(repo user.IRepository, cacheRepo ICacheRepository, rbacRepo rbac.IRepository, orgRepo organization.IRepository, auditLog audit.IService, history loginhistory.IService, mailer notification.IMailer, cfg *config.Config, identities federation.IRepository, directories []directory.IBackend, sp saml.IServiceProvider, providers []saml.IdentityProvider) saml.IService {
+	return &samlService{
+		authService: newAuthService(repo, cacheRepo, rbacRepo, orgRepo, auditLog, history, mailer, cfg, identities, directories),
+		sp:          sp,
+		providers:   providers,
+	}
+}
+
+func (s *samlService) Providers() []saml.ProviderInfo {
+	infos := make([]saml.ProviderInfo, 0, len(s.providers))
+	for _, p := range s.providers {
+		infos = append(infos, saml.ProviderInfo{Name: p.Name, DisplayName: p.DisplayName})
+	}
+	return infos
+}
+
+func (s *samlService) Metadata() ([]byte, error) {
+	return s.sp.Metadata()
+}
+
+func (s *samlService) StartLogin(ctx context.Context, providerName string) (string, string, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return "", "", err
+	}
+
+	relayState, err := randomToken()
+	if err != nil {
+		return "", "", err
+	}
+	redirectURL, requestID, err := s.sp.AuthnRequestURL(provider, relayState)
+	if err != nil {
+		return "", "", err
+	}
+
+	request := saml.PendingRequest{Provider: provider.Name, RequestID: requestID}
+	ttl := time.Duration(s.cfg.SAMLRequestTTLMin) * time.Minute
+	if err := s.cacheRepo.SaveSAMLRequest(ctx, relayState, request, ttl); err != nil {
+		return "", "", err
+	}
+	return redirectURL, relayState, nil
+}
+
+// ConsumeResponse accepts each request and each assertion once: the pending
+// request is consumed before the response is read, and the ID of the
+// assertion is kept until it expires.
+func (s *samlService) ConsumeResponse(ctx context.Context, samlResponse string, relayState string) (string, error) {
+	request, err := s.cacheRepo.ConsumeSAMLRequest(ctx, relayState)
+	if err != nil {
+		return "", saml.ErrInvalidRelayState
+	}
+	provider, err := s.provider(request.Provider)
+	if err != nil {
+		return "", saml.ErrInvalidRelayState
+	}
+
+	assertion, err := s.sp.ParseResponse(provider, samlResponse, request.RequestID)
+	if err != nil {
+		if errors.Is(err, saml.ErrInvalidResponse) {
+			log.Printf("[WARN] Rejected SAML response of provider %s: %v", provider.Name, err)
+		}
+		return "", err
+	}
+
+	ttl := time.Until(assertion.NotOnOrAfter) + time.Duration(s.cfg.SAMLClockSkewSec)*time.Second
+	firstUse, err := s.cacheRepo.ClaimSAMLAssertion(ctx, provider.EntityID, assertion.ID, ttl)
+	if err != nil {
+		return "", err
+	}
+	if !firstUse {
+		log.Printf("[WARN] Replayed SAML assertion %s of provider %s", assertion.ID, provider.Name)
+		return "", saml.ErrAssertionReplayed
+	}
+
+	org, err := s.resolveOrganization(ctx, provider.Organization)
+	if err != nil {
+		return "", err
+	}
+	ctx = tenant.WithOrganization(ctx, org.ID)
+
+	profile := externalProfile{
+		Source:     provider.IdentityProvider(),
+		Subject:    assertion.Subject(provider.Attributes),
+		Name:       assertion.Value(provider.Attributes.Name),
+		Email:      normalizeEmail(assertion.Email(provider.Attributes)),
+		Groups:     assertion.Values(provider.Attributes.Groups),
+		GroupRoles: provider.GroupRoles,
+	}
+	if profile.Subject == "" || profile.Email == "" {
+		log.Printf("[WARN] SAML assertion %s of provider %s lacks the subject or the e-mail", assertion.ID, provider.Name)
+		return "", saml.ErrMissingAttributes
+	}
+	if !provider.AllowsEmail(profile.Email) {
+		s.recordLoginFailure(ctx, nil, profile.Email, "saml_domain_not_allowed")
+		return "", saml.ErrDomainNotAllowed
+	}
+
+	foundUser, err := s.externalUser(ctx, profile, provider.LinkExistingAccounts, provider.JITProvisioning)
+	if err != nil {
+		switch {
+		case errors.Is(err, errExternalAccountNotFound):
+			s.recordLoginFailure(ctx, nil, profile.Email, "unknown_user")
+			return "", saml.ErrAccountNotFound
+		case errors.Is(err, errExternalLinkRequired):
+			s.recordLoginFailure(ctx, nil, profile.Email, "saml_link_required")
+			return "", saml.ErrAccountLinkRequired
+		}
+		return "", err
+	}
+	if err := s.syncExternalUser(ctx, foundUser, profile); err != nil {
+		return "", err
+	}
+
+	code, err := randomToken()
+	if err != nil {
+		return "", err
+	}
+	ticket := SAMLLoginTicket{
+		OrganizationID: foundUser.OrganizationID,
+		UserID:         foundUser.ID,
+		RelayStateHash: hashNonce(relayState),
+	}
+	if err := s.cacheRepo.SaveSAMLLogin(ctx, code, ticket, samlLoginCodeTTL); err != nil {
+		return "", err
+	}
+	return code, nil
+}
+
+func (s *samlService) CompleteLogin(ctx context.Context, code string, relayState string) (*user.LoginResult, error) {
+	ticket, err := s.cacheRepo.ConsumeSAMLLogin(ctx, code)
+	if err != nil {
+		return nil, saml.ErrInvalidLoginCode
+	}
+	ctx = tenant.WithOrganization(ctx, ticket.OrganizationID)
+
+	foundUser, err := s.repo.FindByID(ctx, ticket.UserID)
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, saml.ErrInvalidLoginCode
+		}
+		return nil, err
+	}
+
+	if relayState == "" || subtle.ConstantTimeCompare([]byte(hashNonce(relayState)), []byte(ticket.RelayStateHash)) != 1 {
+		s.recordLoginFailure(ctx, foundUser, foundUser.Email, "saml_relay_state_mismatch")
+		return nil, saml.ErrInvalidLoginCode
+	}
+
+	if err := s.checkAccountLock(ctx, foundUser); err != nil {
+		if errors.Is(err, ErrAccountLocked) {
+			s.recordLoginFailure(ctx, foundUser, foundUser.Email, "locked")
+		}
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, foundUser, LoginMethodSAML)
+}
+
+func (s *samlService) provider(name string) (saml.IdentityProvider, error) {
+	for _, p := range s.providers {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+	return saml.IdentityProvider{}, saml.ErrProviderNotFound
+}
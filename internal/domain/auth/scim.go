@@ -0,0 +1,564 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/scim"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// scimProvider is the source of the users and audit events of SCIM
+// provisioning.
+const scimProvider = "scim"
+
+// scimTokenPrefixLength is how much of a token is kept to identify it:
+// TokenPrefix and 7 random characters.
+const scimTokenPrefixLength = len(scim.TokenPrefix) + 7
+
+// scimService maps the SCIM users and groups of the identity provider onto
+// the accounts and status model of the organization.
+type scimService struct {
+	*authService
+	scimRepo scim.IRepository
+}
+
+func NewSCIMService(repo user.IRepository, cacheRepo ICacheRepository, rbacRepo rbac.IRepository, orgRepo organization.IRepository, auditLog audit.IService, cfg *config.Config, scimRepo scim.IRepository) scim.IService {
+	return &scimService{
+		authService: newAuthService(repo, cacheRepo, rbacRepo, orgRepo, auditLog, nil, nil, cfg, nil, nil),
+		scimRepo:    scimRepo,
+	}
+}
+
+func (s *scimService) CreateToken(ctx context.Context, name string, createdBy uuid.UUID) (*scim.Token, string, error) {
+	secret, err := randomToken()
+	if err != nil {
+		return nil, "", err
+	}
+	rawToken := scim.TokenPrefix + secret
+
+	token := &scim.Token{
+		ID:        uuid.New(),
+		Name:      strings.TrimSpace(name),
+		Prefix:    rawToken[:scimTokenPrefixLength],
+		TokenHash: hashNonce(rawToken),
+		CreatedBy: &createdBy,
+	}
+	if err := s.scimRepo.CreateToken(ctx, token); err != nil {
+		return nil, "", err
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		Action:   audit.ActionSCIMTokenCreated,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: audit.Metadata{"token_id": token.ID.String(), "name": token.Name},
+	})
+	return token, rawToken, nil
+}
+
+func (s *scimService) ListTokens(ctx context.Context) ([]scim.Token, error) {
+	return s.scimRepo.ListTokens(ctx)
+}
+
+func (s *scimService) RevokeToken(ctx context.Context, id uuid.UUID) error {
+	if err := s.scimRepo.DeleteToken(ctx, id); err != nil {
+		return err
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		Action:   audit.ActionSCIMTokenRevoked,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: audit.Metadata{"token_id": id.String()},
+	})
+	return nil
+}
+
+func (s *scimService) Authenticate(ctx context.Context, rawToken string) (*scim.Token, error) {
+	if !strings.HasPrefix(rawToken, scim.TokenPrefix) {
+		return nil, scim.ErrInvalidToken
+	}
+
+	token, err := s.scimRepo.FindTokenByHash(ctx, hashNonce(rawToken))
+	if err != nil {
+		return nil, err
+	}
+	if err := s.scimRepo.TouchToken(ctx, token.ID); err != nil {
+		log.Printf("[ERROR] Failed to record use of SCIM token %s: %v", token.ID, err)
+	}
+	return token, nil
+}
+
+func (s *scimService) ListUsers(ctx context.Context, q scim.Query) ([]scim.User, int64, error) {
+	return s.scimRepo.ListUsers(ctx, q)
+}
+
+func (s *scimService) GetUser(ctx context.Context, id uuid.UUID) (*scim.User, error) {
+	return s.scimRepo.FindUser(ctx, id)
+}
+
+// CreateUser provisions the account without a password: the user signs in
+// through the identity provider or sets one with the password reset.
+func (s *scimService) CreateUser(ctx context.Context, attrs scim.UserAttributes) (*scim.User, error) {
+	email, err := scimEmail(attrs.UserName)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkExternalID(ctx, attrs.ExternalID, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		restored, err := s.restoreUser(ctx, existing)
+		if err != nil {
+			return nil, err
+		}
+		return s.applyUser(ctx, restored, attrs)
+	}
+
+	newUser, err := s.provisionExternalUser(ctx, externalProfile{Source: scimProvider, Name: attrs.Name}, email)
+	if err != nil {
+		if errors.Is(err, ErrEmailAlreadyExists) {
+			return nil, scim.ErrUserNameTaken
+		}
+		return nil, err
+	}
+	if err := s.scimRepo.SaveUserLink(ctx, &scim.UserLink{UserID: newUser.ID, ExternalID: attrs.ExternalID}); err != nil {
+		return nil, err
+	}
+	if err := s.setActive(ctx, newUser, attrs.Active); err != nil {
+		return nil, err
+	}
+	return s.scimRepo.FindUser(ctx, newUser.ID)
+}
+
+func (s *scimService) ReplaceUser(ctx context.Context, id uuid.UUID, attrs scim.UserAttributes) (*scim.User, error) {
+	current, err := s.scimRepo.FindUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyUser(ctx, current, attrs)
+}
+
+func (s *scimService) PatchUser(ctx context.Context, id uuid.UUID, ops []scim.PatchOperation) (*scim.User, error) {
+	current, err := s.scimRepo.FindUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The patch starts from active unless the identity provider deactivated
+	// the user, so patching other attributes of a suspended user keeps the
+	// suspension.
+	attrs := scim.UserAttributes{
+		UserName:   current.Email,
+		ExternalID: current.ExternalID,
+		Name:       current.Name,
+		Active:     !current.Deprovisioned(),
+	}
+	if err := scim.ApplyUserPatch(&attrs, ops); err != nil {
+		return nil, err
+	}
+	return s.applyUser(ctx, current, attrs)
+}
+
+func (s *scimService) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	current, err := s.scimRepo.FindUser(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.setActive(ctx, &current.User, false); err != nil {
+		return err
+	}
+
+	scheduledFor := time.Now().UTC().Add(time.Duration(s.cfg.AccountDeletionGraceDays) * 24 * time.Hour)
+	if err := s.repo.ScheduleDeletion(ctx, id, scheduledFor); err != nil {
+		return err
+	}
+	s.recordProvisioningEvent(ctx, id, audit.ActionDeletionRequested, audit.Metadata{
+		"scheduled_for": scheduledFor.Format(time.RFC3339),
+	})
+	return nil
+}
+
+// restoreUser cancels the deletion of a user the identity provider deleted
+// and now creates again. Any other account with the e-mail is a conflict.
+func (s *scimService) restoreUser(ctx context.Context, existing *user.User) (*scim.User, error) {
+	if existing.DeletionScheduledFor == nil {
+		return nil, scim.ErrUserNameTaken
+	}
+	linked, err := s.scimRepo.IsLinked(ctx, existing.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked {
+		return nil, scim.ErrUserNameTaken
+	}
+
+	if err := s.repo.CancelDeletion(ctx, existing.ID); err != nil {
+		return nil, err
+	}
+	s.recordProvisioningEvent(ctx, existing.ID, audit.ActionDeletionCancelled, nil)
+	return s.scimRepo.FindUser(ctx, existing.ID)
+}
+
+// applyUser stores the attributes of the identity provider. The first change
+// of a user created elsewhere makes it managed by the identity provider.
+func (s *scimService) applyUser(ctx context.Context, current *scim.User, attrs scim.UserAttributes) (*scim.User, error) {
+	email, err := scimEmail(attrs.UserName)
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimSpace(attrs.Name)
+	if name != "" && (len(name) < 3 || len(name) > 100) {
+		return nil, fmt.Errorf("%w: name must have 3 to 100 characters", scim.ErrInvalidValue)
+	}
+
+	var changed []string
+	if name != "" && name != current.Name {
+		current.Name = name
+		if err := s.repo.UpdateProfile(ctx, &current.User, nil); err != nil {
+			return nil, err
+		}
+		changed = append(changed, "name")
+	}
+
+	if email != current.Email {
+		if err := s.repo.UpdateEmail(ctx, current.ID, email); err != nil {
+			if errors.Is(err, ErrEmailAlreadyExists) {
+				return nil, scim.ErrUserNameTaken
+			}
+			return nil, err
+		}
+		current.Email = email
+		changed = append(changed, "email")
+
+		// As in a confirmed e-mail change, the sessions opened under the old
+		// address do not carry over to the new one.
+		if err := revokeSessions(ctx, s.repo, s.cacheRepo, current.ID); err != nil {
+			return nil, err
+		}
+		s.recordProvisioningEvent(ctx, current.ID, audit.ActionEmailChanged, audit.Metadata{"email": email})
+	}
+
+	linked, err := s.scimRepo.IsLinked(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !linked || deref(attrs.ExternalID) != deref(current.ExternalID) {
+		if err := s.checkExternalID(ctx, attrs.ExternalID, current.ID); err != nil {
+			return nil, err
+		}
+		if err := s.scimRepo.SaveUserLink(ctx, &scim.UserLink{UserID: current.ID, ExternalID: attrs.ExternalID}); err != nil {
+			return nil, err
+		}
+		changed = append(changed, "external_id")
+	}
+
+	if len(changed) > 0 {
+		s.recordProvisioningEvent(ctx, current.ID, audit.ActionAttributesSynced, audit.Metadata{
+			"fields": strings.Join(changed, ","),
+		})
+	}
+
+	if err := s.setActive(ctx, &current.User, attrs.Active); err != nil {
+		return nil, err
+	}
+	return s.scimRepo.FindUser(ctx, current.ID)
+}
+
+// setActive maps the active attribute onto the status model. Deactivating
+// revokes every session of the user. Only users the identity provider
+// deactivated are reactivated: suspensions, bans and self-deactivations are
+// kept.
+func (s *scimService) setActive(ctx context.Context, u *user.User, active bool) error {
+	var status user.Status
+	switch {
+	case !active && u.Status != user.StatusInactive && u.Status != user.StatusBanned:
+		status = user.StatusInactive
+	case active && u.Status == user.StatusInactive && u.StatusSource == user.SourceProvisioning:
+		status = user.StatusActive
+	default:
+		return nil
+	}
+
+	change := user.StatusChange{Status: status, Source: user.SourceProvisioning}
+	if err := s.repo.ChangeStatus(ctx, u.ID, change); err != nil {
+		return err
+	}
+	if err := revokeSessions(ctx, s.repo, s.cacheRepo, u.ID); err != nil {
+		return err
+	}
+	u.Status = status
+	u.StatusSource = user.SourceProvisioning
+
+	s.recordProvisioningEvent(ctx, u.ID, audit.ActionStatusChanged, statusChangeMetadata(change))
+	return nil
+}
+
+// checkExternalID rejects an externalId already given to another user.
+func (s *scimService) checkExternalID(ctx context.Context, externalID *string, userID uuid.UUID) error {
+	if externalID == nil {
+		return nil
+	}
+
+	users, _, err := s.scimRepo.ListUsers(ctx, scim.Query{
+		Filter: &scim.Filter{Attribute: "externalid", Value: *externalID},
+		Count:  1,
+	})
+	if err != nil {
+		return err
+	}
+	if len(users) > 0 && users[0].ID != userID {
+		return scim.ErrExternalIDTaken
+	}
+	return nil
+}
+
+func (s *scimService) ListGroups(ctx context.Context, q scim.Query) ([]scim.Group, int64, error) {
+	return s.scimRepo.ListGroups(ctx, q)
+}
+
+func (s *scimService) GetGroup(ctx context.Context, id uuid.UUID) (*scim.Group, error) {
+	return s.scimRepo.FindGroup(ctx, id)
+}
+
+func (s *scimService) CreateGroup(ctx context.Context, attrs scim.GroupAttributes) (*scim.Group, error) {
+	if err := s.validateGroup(ctx, &attrs, attrs.Members); err != nil {
+		return nil, err
+	}
+
+	group := &scim.Group{
+		ID:          uuid.New(),
+		DisplayName: attrs.DisplayName,
+		ExternalID:  attrs.ExternalID,
+	}
+	if err := s.scimRepo.CreateGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	if err := s.scimRepo.AddMembers(ctx, group.ID, attrs.Members); err != nil {
+		return nil, err
+	}
+	if err := s.syncUserRoles(ctx, attrs.Members); err != nil {
+		return nil, err
+	}
+	return s.scimRepo.FindGroup(ctx, group.ID)
+}
+
+func (s *scimService) ReplaceGroup(ctx context.Context, id uuid.UUID, attrs scim.GroupAttributes) (*scim.Group, error) {
+	current, err := s.scimRepo.FindGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.applyGroup(ctx, current, attrs)
+}
+
+func (s *scimService) PatchGroup(ctx context.Context, id uuid.UUID, ops []scim.PatchOperation) (*scim.Group, error) {
+	current, err := s.scimRepo.FindGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := scim.GroupAttributes{
+		DisplayName: current.DisplayName,
+		ExternalID:  current.ExternalID,
+		Members:     current.MemberIDs(),
+	}
+	if err := scim.ApplyGroupPatch(&attrs, ops); err != nil {
+		return nil, err
+	}
+	return s.applyGroup(ctx, current, attrs)
+}
+
+func (s *scimService) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	current, err := s.scimRepo.FindGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := s.scimRepo.DeleteGroup(ctx, id); err != nil {
+		return err
+	}
+	return s.syncUserRoles(ctx, current.MemberIDs())
+}
+
+func (s *scimService) SetGroupRoles(ctx context.Context, id uuid.UUID, roleNames []string) (*scim.Group, error) {
+	current, err := s.scimRepo.FindGroup(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	names := slices.Compact(slices.Sorted(slices.Values(roleNames)))
+	roles, err := s.rbacRepo.FindRolesByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) != len(names) {
+		return nil, scim.ErrUnknownRole
+	}
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if err := s.scimRepo.SetGroupRoles(ctx, id, roleIDs); err != nil {
+		return nil, err
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		Action:  audit.ActionSCIMGroupRolesChanged,
+		Outcome: audit.OutcomeSuccess,
+		Metadata: audit.Metadata{
+			"group_id": id.String(),
+			"group":    current.DisplayName,
+			"roles":    strings.Join(names, ","),
+		},
+	})
+
+	if err := s.syncUserRoles(ctx, current.MemberIDs()); err != nil {
+		return nil, err
+	}
+	return s.scimRepo.FindGroup(ctx, id)
+}
+
+func (s *scimService) applyGroup(ctx context.Context, current *scim.Group, attrs scim.GroupAttributes) (*scim.Group, error) {
+	currentMembers := current.MemberIDs()
+	var added, removed []uuid.UUID
+	for _, id := range attrs.Members {
+		if !slices.Contains(currentMembers, id) {
+			added = append(added, id)
+		}
+	}
+	for _, id := range currentMembers {
+		if !slices.Contains(attrs.Members, id) {
+			removed = append(removed, id)
+		}
+	}
+	if err := s.validateGroup(ctx, &attrs, added); err != nil {
+		return nil, err
+	}
+
+	current.DisplayName = attrs.DisplayName
+	current.ExternalID = attrs.ExternalID
+	if err := s.scimRepo.UpdateGroup(ctx, current); err != nil {
+		return nil, err
+	}
+	if err := s.scimRepo.AddMembers(ctx, current.ID, added); err != nil {
+		return nil, err
+	}
+	if err := s.scimRepo.RemoveMembers(ctx, current.ID, removed); err != nil {
+		return nil, err
+	}
+	if err := s.syncUserRoles(ctx, append(added, removed...)); err != nil {
+		return nil, err
+	}
+	return s.scimRepo.FindGroup(ctx, current.ID)
+}
+
+// validateGroup checks the display name and that newMembers are users of the
+// organization.
+func (s *scimService) validateGroup(ctx context.Context, attrs *scim.GroupAttributes, newMembers []uuid.UUID) error {
+	attrs.DisplayName = strings.TrimSpace(attrs.DisplayName)
+	if attrs.DisplayName == "" || len(attrs.DisplayName) > 255 {
+		return fmt.Errorf("%w: displayName", scim.ErrInvalidValue)
+	}
+
+	existing, err := s.scimRepo.ExistingUsers(ctx, newMembers)
+	if err != nil {
+		return err
+	}
+	for _, id := range newMembers {
+		if !slices.Contains(existing, id) {
+			return fmt.Errorf("%w: unknown member %s", scim.ErrInvalidValue, id)
+		}
+	}
+	return nil
+}
+
+// syncUserRoles grants the users the roles mapped to their groups. While no
+// group of the organization has roles, the roles are managed locally.
+func (s *scimService) syncUserRoles(ctx context.Context, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	mapped, err := s.scimRepo.HasRoleMappings(ctx)
+	if err != nil || !mapped {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		u, err := s.repo.FindByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				continue
+			}
+			return err
+		}
+		groups, err := s.scimRepo.UserGroups(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		profile := externalProfile{Source: scimProvider, GroupRoles: rbac.GroupRoles{}}
+		for _, g := range groups {
+			profile.Groups = append(profile.Groups, g.ID.String())
+			if len(g.Roles) > 0 {
+				profile.GroupRoles[g.ID.String()] = g.Roles
+			}
+		}
+		changed, err := s.syncGroupRoles(ctx, u, profile)
+		if err != nil {
+			return err
+		}
+		if changed {
+			s.recordProvisioningEvent(ctx, userID, audit.ActionAttributesSynced, audit.Metadata{"fields": "roles"})
+		}
+	}
+	return nil
+}
+
+// recordProvisioningEvent audits a change the identity provider made to the
+// user.
+func (s *scimService) recordProvisioningEvent(ctx context.Context, userID uuid.UUID, action string, metadata audit.Metadata) {
+	if metadata == nil {
+		metadata = audit.Metadata{}
+	}
+	metadata["source"] = string(user.SourceProvisioning)
+	metadata["provider"] = scimProvider
+
+	s.auditLog.Record(ctx, audit.Event{
+		TargetID: &userID,
+		Action:   action,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: metadata,
+	})
+}
+
+// scimEmail returns the normalized e-mail of a userName, which must be the
+// e-mail the user signs in with.
+func scimEmail(userName string) (string, error) {
+	email := normalizeEmail(userName)
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return "", fmt.Errorf("%w: userName must be an e-mail address", scim.ErrInvalidValue)
+	}
+	return email, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
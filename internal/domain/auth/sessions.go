@@ -0,0 +1,19 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// revokeSessions invalidates every access and refresh token of the user. The
+// token version is bumped in the database and its cached copy, which the
+// AuthMiddleware reads for up to 12 hours, is dropped along with the stored
+// refresh tokens.
+func revokeSessions(ctx context.Context, repo user.IRepository, cacheRepo ICacheRepository, userID uuid.UUID) error {
+	if err := repo.IncrementTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+	return cacheRepo.RevokeUserSessions(ctx, userID.String())
+}
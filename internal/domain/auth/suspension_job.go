@@ -0,0 +1,50 @@
+package auth
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+)
+
+// SuspensionJob reactivates users whose suspension ended. Login lifts an
+// expired suspension on its own, so the interval only bounds how long the
+// stale status shows in listings.
+type SuspensionJob struct {
+	repo     user.IRepository
+	interval time.Duration
+}
+
+func NewSuspensionJob(repo user.IRepository, interval time.Duration) *SuspensionJob {
+	return &SuspensionJob{repo: repo, interval: interval}
+}
+
+// Run sweeps until ctx is cancelled.
+func (j *SuspensionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *SuspensionJob) sweep(ctx context.Context) {
+	lifted, err := j.repo.LiftExpiredSuspensions(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to lift expired suspensions: %v", err)
+		}
+		return
+	}
+	if lifted > 0 {
+		log.Printf("[INFO] Lifted %d expired suspension(s)", lifted)
+	}
+}
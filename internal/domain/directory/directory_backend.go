@@ -0,0 +1,17 @@
+package directory
+
+import "context"
+
+// IBackend authenticates users against an external directory, such as LDAP
+// or Active Directory, in place of the local password.
+type IBackend interface {
+	Settings() Settings
+	// Authenticate checks the password of the entry found for login and
+	// returns the entry. Wrong passwords and unknown logins both return
+	// ErrInvalidCredentials.
+	Authenticate(ctx context.Context, login string, password string) (*Entry, error)
+	// Lookup returns the entry found for login without checking a password,
+	// to confirm that an account signing in some other way is still enabled.
+	// Unknown and disabled logins return ErrEntryNotFound.
+	Lookup(ctx context.Context, login string) (*Entry, error)
+}
@@ -0,0 +1,57 @@
+package directory
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+)
+
+// Settings tells which logins a backend authenticates and how its entries map
+// to users.
+type Settings struct {
+	Name string `json:"name"`
+	// Organization is the slug of the organization of the users; empty means
+	// the default organization.
+	Organization string `json:"organization"`
+	// Domains restricts the backend to these e-mail domains. Empty handles
+	// every login of the organization.
+	Domains []string `json:"domains"`
+	// JITProvisioning creates users on their first sign-in.
+	JITProvisioning bool `json:"jit_provisioning"`
+	// GroupRoles maps a directory group to the roles of its members. When set,
+	// every sign-in replaces the roles of the user with the roles of its
+	// groups, or the default user role when no group matches.
+	GroupRoles rbac.GroupRoles `json:"group_roles"`
+}
+
+// HandlesEmail reports whether the domain of email is in Domains.
+func (s Settings) HandlesEmail(email string) bool {
+	if len(s.Domains) == 0 {
+		return true
+	}
+	_, domain, found := strings.Cut(email, "@")
+	if !found {
+		return false
+	}
+	return slices.ContainsFunc(s.Domains, func(d string) bool {
+		return strings.EqualFold(d, domain)
+	})
+}
+
+// IdentityProvider is the provider of the identities linking the entries of
+// the backend to users.
+func (s Settings) IdentityProvider() string {
+	return "ldap:" + s.Name
+}
+
+// Entry is the directory entry of an authenticated user.
+type Entry struct {
+	// ID is a stable identifier of the entry, such as entryUUID or objectGUID,
+	// which survives renames.
+	ID     string
+	DN     string
+	Email  string
+	Name   string
+	Groups []string
+}
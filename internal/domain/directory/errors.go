@@ -0,0 +1,9 @@
+package directory
+
+import "errors"
+
+var (
+	ErrInvalidCredentials = errors.New("directory rejected the credentials")
+	ErrUnavailable        = errors.New("directory is unavailable")
+	ErrEntryNotFound      = errors.New("directory entry not found or disabled")
+)
@@ -0,0 +1,60 @@
+package dpop
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+)
+
+// HeaderName carries the proof; Scheme is the Authorization scheme of bound
+// access tokens (RFC 9449, section 7.1).
+const (
+	HeaderName = "DPoP"
+	Scheme     = "DPoP"
+	TokenType  = "DPoP"
+	ProofType  = "dpop+jwt"
+)
+
+// SupportedAlgorithms are the asymmetric signature algorithms accepted in
+// proofs, also published in the WWW-Authenticate challenge.
+var SupportedAlgorithms = []string{"ES256", "ES384", "RS256", "PS256", "EdDSA"}
+
+// Request is the HTTP request a proof must be bound to. AccessToken is set on
+// resource requests, whose proof carries its hash in the ath claim, and
+// Thumbprint is the cnf.jkt of a bound access token, which the proof key must
+// match.
+type Request struct {
+	Method      string
+	URL         string
+	AccessToken string
+	Thumbprint  string
+}
+
+// Proof is a verified proof. Thumbprint is the JWK SHA-256 thumbprint
+// (RFC 7638) of its key, the jkt that binds tokens.
+type Proof struct {
+	Thumbprint string
+	JTI        string
+	IssuedAt   time.Time
+}
+
+// AccessTokenHash is the ath claim of a proof sent with accessToken.
+func AccessTokenHash(accessToken string) string {
+	sum := sha256.Sum256([]byte(accessToken))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+type ctxKey struct{}
+
+// WithThumbprint marks the request as proving possession of the key, so the
+// tokens issued for it are bound to the key.
+func WithThumbprint(ctx context.Context, jkt string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, jkt)
+}
+
+// Thumbprint returns the key the request proved possession of, if any.
+func Thumbprint(ctx context.Context) string {
+	jkt, _ := ctx.Value(ctxKey{}).(string)
+	return jkt
+}
@@ -0,0 +1,127 @@
+package dpop
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// maxJTILength bounds the jti kept in the replay cache.
+const maxJTILength = 256
+
+type IReplayCache interface {
+	// ClaimDPoPProof records the proof of the key as used until ttl elapses.
+	// It returns false when the proof was already used.
+	ClaimDPoPProof(ctx context.Context, jkt string, jti string, ttl time.Duration) (bool, error)
+}
+
+// IVerifier checks DPoP proofs (RFC 9449, section 4.3). Server nonces are not
+// used; freshness comes from iat and replay from the jti of each key.
+type IVerifier interface {
+	// Verify validates the proof for req and records it as used.
+	Verify(ctx context.Context, proof string, req Request) (*Proof, error)
+}
+
+type verifier struct {
+	replay IReplayCache
+	maxAge time.Duration
+	skew   time.Duration
+}
+
+func NewVerifier(replay IReplayCache, cfg *config.Config) IVerifier {
+	return &verifier{
+		replay: replay,
+		maxAge: time.Duration(cfg.DPoPProofMaxAgeSec) * time.Second,
+		skew:   time.Duration(cfg.DPoPClockSkewSec) * time.Second,
+	}
+}
+
+func (v *verifier) Verify(ctx context.Context, proof string, req Request) (*Proof, error) {
+	if proof == "" {
+		return nil, ErrMissingProof
+	}
+
+	var thumbprint string
+	claims := jwt.MapClaims{}
+	parser := jwt.NewParser(jwt.WithValidMethods(SupportedAlgorithms), jwt.WithoutClaimsValidation())
+	_, err := parser.ParseWithClaims(proof, claims, func(t *jwt.Token) (interface{}, error) {
+		if typ, _ := t.Header["typ"].(string); typ != ProofType {
+			return nil, errors.New("typ is not " + ProofType)
+		}
+		key, err := parseJWK(t.Header["jwk"])
+		if err != nil {
+			return nil, err
+		}
+		thumbprint, err = key.thumbprint()
+		if err != nil {
+			return nil, err
+		}
+		return key.publicKey()
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidProof, err)
+	}
+
+	jti, _ := claims["jti"].(string)
+	if jti == "" || len(jti) > maxJTILength {
+		return nil, fmt.Errorf("%w: invalid jti", ErrInvalidProof)
+	}
+	if htm, _ := claims["htm"].(string); htm != req.Method {
+		return nil, fmt.Errorf("%w: htm does not match the request", ErrInvalidProof)
+	}
+	if htu, _ := claims["htu"].(string); !sameURL(htu, req.URL) {
+		return nil, fmt.Errorf("%w: htu does not match the request", ErrInvalidProof)
+	}
+
+	iat, _ := claims["iat"].(float64)
+	issuedAt := time.Unix(int64(iat), 0)
+	now := time.Now()
+	if iat == 0 || issuedAt.Before(now.Add(-v.maxAge)) || issuedAt.After(now.Add(v.skew)) {
+		return nil, fmt.Errorf("%w: iat out of the accepted window", ErrInvalidProof)
+	}
+
+	if req.AccessToken != "" {
+		ath, _ := claims["ath"].(string)
+		if subtle.ConstantTimeCompare([]byte(ath), []byte(AccessTokenHash(req.AccessToken))) != 1 {
+			return nil, fmt.Errorf("%w: ath does not match the access token", ErrInvalidProof)
+		}
+	}
+	// Checked before the jti is claimed, so a proof of another key does not
+	// burn it.
+	if req.Thumbprint != "" && subtle.ConstantTimeCompare([]byte(thumbprint), []byte(req.Thumbprint)) != 1 {
+		return nil, ErrKeyMismatch
+	}
+
+	firstUse, err := v.replay.ClaimDPoPProof(ctx, thumbprint, jti, v.maxAge+v.skew)
+	if err != nil {
+		return nil, err
+	}
+	if !firstUse {
+		return nil, ErrProofReplayed
+	}
+
+	return &Proof{Thumbprint: thumbprint, JTI: jti, IssuedAt: issuedAt}, nil
+}
+
+// sameURL compares the htu claim with the request URL ignoring the query and
+// fragment, and the case of the scheme and host (RFC 9449, section 4.3).
+func sameURL(htu string, requestURL string) bool {
+	claimed, err := url.Parse(htu)
+	if err != nil || htu == "" {
+		return false
+	}
+	actual, err := url.Parse(requestURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(claimed.Scheme, actual.Scheme) &&
+		strings.EqualFold(claimed.Host, actual.Host) &&
+		claimed.Path == actual.Path
+}
@@ -0,0 +1,10 @@
+package dpop
+
+import "errors"
+
+var (
+	ErrMissingProof  = errors.New("DPoP proof is missing")
+	ErrInvalidProof  = errors.New("invalid DPoP proof")
+	ErrProofReplayed = errors.New("DPoP proof was already used")
+	ErrKeyMismatch   = errors.New("DPoP proof key does not match the token binding")
+)
@@ -0,0 +1,19 @@
+package federation
+
+import "errors"
+
+var (
+	ErrProviderNotFound      = errors.New("identity provider not found")
+	ErrInvalidState          = errors.New("invalid or expired federated login state")
+	ErrInvalidIDToken        = errors.New("invalid ID token")
+	ErrCodeExchange          = errors.New("provider rejected the authorization code")
+	ErrEmailNotVerified      = errors.New("provider did not return a verified email")
+	ErrDomainNotAllowed      = errors.New("email domain is not allowed for this provider")
+	ErrAccountNotFound       = errors.New("no account for this identity and provisioning is disabled")
+	ErrAccountLinkRequired   = errors.New("an account with this email exists and must link the identity")
+	ErrIdentityNotFound      = errors.New("identity not found")
+	ErrIdentityAlreadyLinked = errors.New("identity is already linked to another user")
+	ErrProviderAlreadyLinked = errors.New("user already has an identity of this provider")
+	ErrLastLoginMethod       = errors.New("cannot unlink the only sign-in method of the account")
+	ErrOrganizationMismatch  = errors.New("provider belongs to another organization")
+)
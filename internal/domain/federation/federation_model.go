@@ -0,0 +1,91 @@
+package federation
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Provider is an upstream OpenID Connect provider, such as Google, Azure AD or
+// Keycloak. Each provider signs users into a single organization.
+type Provider struct {
+	Name         string   `json:"name"`
+	DisplayName  string   `json:"display_name"`
+	Issuer       string   `json:"issuer"`
+	ClientID     string   `json:"client_id"`
+	ClientSecret string   `json:"client_secret"`
+	Scopes       []string `json:"scopes"`
+	// Organization is the slug of the organization of the users; empty means
+	// the default organization.
+	Organization string `json:"organization"`
+	// AllowedDomains restricts the e-mail domains accepted from the provider.
+	// Empty accepts any domain.
+	AllowedDomains []string `json:"allowed_domains"`
+	// JITProvisioning creates users on their first sign-in.
+	JITProvisioning bool `json:"jit_provisioning"`
+	// LinkExistingAccounts links the first sign-in to the user with the same
+	// verified e-mail. Otherwise that user must link the identity from
+	// /me/identities after signing in with the password.
+	LinkExistingAccounts bool `json:"link_existing_accounts"`
+}
+
+// AllowsEmail reports whether the domain of email is in AllowedDomains.
+func (p Provider) AllowsEmail(email string) bool {
+	if len(p.AllowedDomains) == 0 {
+		return true
+	}
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	return slices.ContainsFunc(p.AllowedDomains, func(allowed string) bool {
+		return strings.EqualFold(allowed, domain)
+	})
+}
+
+// ProviderInfo is the public description of a provider.
+type ProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// Identity links the subject of a provider to a user.
+type Identity struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid" json:"organization_id"`
+	UserID         uuid.UUID  `gorm:"type:uuid" json:"user_id"`
+	Provider       string     `json:"provider"`
+	Subject        string     `json:"subject"`
+	Email          string     `json:"email"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastLoginAt    *time.Time `json:"last_login_at"`
+}
+
+// Claims are the verified claims of the ID token returned by a provider.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+// AuthorizationRequest holds the parameters of the redirect to a provider.
+// CodeChallenge is the S256 PKCE challenge.
+type AuthorizationRequest struct {
+	State         string
+	Nonce         string
+	CodeChallenge string
+	RedirectURI   string
+}
+
+// LoginState is kept between the redirect and the callback. LinkUserID is set
+// when a signed-in user links a new identity.
+type LoginState struct {
+	Provider     string     `json:"provider"`
+	Nonce        string     `json:"nonce"`
+	CodeVerifier string     `json:"code_verifier"`
+	LinkUserID   *uuid.UUID `json:"link_user_id,omitempty"`
+}
@@ -0,0 +1,24 @@
+package federation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IRepository interface {
+	// FindBySubject returns ErrIdentityNotFound when the subject is not linked.
+	FindBySubject(ctx context.Context, provider string, subject string) (*Identity, error)
+	Create(ctx context.Context, identity *Identity) error
+	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
+	ListForUser(ctx context.Context, userID uuid.UUID) ([]Identity, error)
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+}
+
+// IOIDCClient talks to the providers: discovery, authorization redirect and
+// the code exchange, which verifies the ID token signature, issuer, audience,
+// expiry and the nonce sent in the authorization request.
+type IOIDCClient interface {
+	AuthorizationURL(ctx context.Context, provider Provider, req AuthorizationRequest) (string, error)
+	Exchange(ctx context.Context, provider Provider, code string, codeVerifier string, redirectURI string, nonce string) (*Claims, error)
+}
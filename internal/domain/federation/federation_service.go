@@ -0,0 +1,22 @@
+package federation
+
+import (
+	"context"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+type IService interface {
+	Providers() []ProviderInfo
+	// StartLogin returns the authorization URL of the provider and the state
+	// the callback must present.
+	StartLogin(ctx context.Context, providerName string) (authorizationURL string, state string, err error)
+	// StartLink is StartLogin for a signed-in user linking a new identity.
+	StartLink(ctx context.Context, userID uuid.UUID, providerName string) (authorizationURL string, state string, err error)
+	// CompleteLogin exchanges the code returned by the provider and signs in
+	// the linked user, linking or provisioning it when allowed.
+	CompleteLogin(ctx context.Context, state string, code string) (*user.LoginResult, error)
+	ListIdentities(ctx context.Context, userID uuid.UUID) ([]Identity, error)
+	Unlink(ctx context.Context, userID uuid.UUID, identityID uuid.UUID) error
+}
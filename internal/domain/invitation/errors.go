@@ -0,0 +1,9 @@
+package invitation
+
+import "errors"
+
+var (
+	ErrInvitationNotFound = errors.New("invitation not found")
+	ErrInvalidInvitation  = errors.New("invalid or expired invitation token")
+	ErrInvitationClosed   = errors.New("invitation was already accepted or revoked")
+)
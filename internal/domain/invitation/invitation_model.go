@@ -0,0 +1,48 @@
+package invitation
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	StatusPending  = "pending"
+	StatusExpired  = "expired"
+	StatusAccepted = "accepted"
+	StatusRevoked  = "revoked"
+)
+
+type Invitation struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid" json:"organization_id"`
+	UserID         *uuid.UUID `gorm:"type:uuid" json:"user_id"`
+	Email          string     `json:"email"`
+	Role           string     `json:"role"`
+	TokenHash      string     `json:"-"`
+	InvitedBy      *uuid.UUID `gorm:"type:uuid" json:"invited_by"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	AcceptedAt     *time.Time `json:"accepted_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      *time.Time `json:"updated_at"`
+}
+
+func (i *Invitation) Status(now time.Time) string {
+	switch {
+	case i.AcceptedAt != nil:
+		return StatusAccepted
+	case i.RevokedAt != nil:
+		return StatusRevoked
+	case !now.Before(i.ExpiresAt):
+		return StatusExpired
+	default:
+		return StatusPending
+	}
+}
+
+// Open reports whether the invitation can still be resent or revoked, which
+// includes expired invitations.
+func (i *Invitation) Open() bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil
+}
@@ -0,0 +1,21 @@
+package invitation
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IRepository interface {
+	Create(ctx context.Context, inv *Invitation) error
+	FindByID(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (*Invitation, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*Invitation, error)
+	ListOpen(ctx context.Context, organizationID uuid.UUID) ([]Invitation, error)
+	RenewToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error
+	// Accept consumes the invitation and activates the invited user with the
+	// given name and password hash, atomically.
+	Accept(ctx context.Context, inv *Invitation, name string, passwordHash string) error
+	// Revoke closes the invitation and removes the never-activated user.
+	Revoke(ctx context.Context, inv *Invitation) error
+}
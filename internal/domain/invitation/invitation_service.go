@@ -0,0 +1,207 @@
+package invitation
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type IService interface {
+	Invite(ctx context.Context, inviterID uuid.UUID, email string, name string, role string) (*Invitation, error)
+	ListOpen(ctx context.Context) ([]Invitation, error)
+	Resend(ctx context.Context, id uuid.UUID) (*Invitation, error)
+	Revoke(ctx context.Context, id uuid.UUID) error
+	Accept(ctx context.Context, token string, name string, password string) error
+}
+
+type invitationService struct {
+	repo     IRepository
+	userRepo user.IRepository
+	orgRepo  organization.IRepository
+	rbacRepo rbac.IRepository
+	mailer   notification.IMailer
+	cfg      *config.Config
+}
+
+func NewInvitationService(repo IRepository, userRepo user.IRepository, orgRepo organization.IRepository, rbacRepo rbac.IRepository, mailer notification.IMailer, cfg *config.Config) IService {
+	return &invitationService{
+		repo:     repo,
+		userRepo: userRepo,
+		orgRepo:  orgRepo,
+		rbacRepo: rbacRepo,
+		mailer:   mailer,
+		cfg:      cfg,
+	}
+}
+
+// Invite creates the user as invited in the organization of the context, with
+// the given organization role, and mails a single-use token to set the
+// password.
+func (s *invitationService) Invite(ctx context.Context, inviterID uuid.UUID, email string, name string, role string) (*Invitation, error) {
+	if role == "" {
+		role = organization.RoleMember
+	}
+	if role != organization.RoleAdmin && role != organization.RoleMember {
+		return nil, organization.ErrInvalidMemberRole
+	}
+
+	organizationID := tenant.OrganizationID(ctx)
+	email = strings.ToLower(strings.TrimSpace(email))
+
+	existing, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, auth.ErrEmailAlreadyExists
+	}
+
+	now := time.Now()
+	invitee := &user.User{
+		Model: base.Model{
+			ID: uuid.New(),
+		},
+		Name:              strings.TrimSpace(name),
+		Email:             email,
+		Role:              user.RoleUser,
+		Status:            user.StatusInvited,
+		PasswordChangedAt: &now,
+	}
+	if err := s.userRepo.Create(ctx, invitee); err != nil {
+		return nil, err
+	}
+	if err := s.rbacRepo.AddUserRole(ctx, invitee.ID, rbac.RoleUser); err != nil {
+		return nil, err
+	}
+	if err := s.orgRepo.AddMember(ctx, organizationID, invitee.ID, role); err != nil {
+		return nil, err
+	}
+
+	token := uuid.New().String()
+	inv := &Invitation{
+		ID:             uuid.New(),
+		OrganizationID: organizationID,
+		UserID:         &invitee.ID,
+		Email:          email,
+		Role:           role,
+		TokenHash:      hashToken(token),
+		InvitedBy:      &inviterID,
+		ExpiresAt:      now.Add(s.ttl()),
+	}
+	if err := s.repo.Create(ctx, inv); err != nil {
+		return nil, err
+	}
+
+	s.send(ctx, inv, invitee.Name, token)
+	return inv, nil
+}
+
+func (s *invitationService) ListOpen(ctx context.Context) ([]Invitation, error) {
+	return s.repo.ListOpen(ctx, tenant.OrganizationID(ctx))
+}
+
+// Resend issues a new token, invalidating the previous one, and restarts the
+// expiration; expired invitations can be resent too.
+func (s *invitationService) Resend(ctx context.Context, id uuid.UUID) (*Invitation, error) {
+	inv, err := s.repo.FindByID(ctx, tenant.OrganizationID(ctx), id)
+	if err != nil {
+		return nil, err
+	}
+	if !inv.Open() {
+		return nil, ErrInvitationClosed
+	}
+
+	token := uuid.New().String()
+	inv.TokenHash = hashToken(token)
+	inv.ExpiresAt = time.Now().Add(s.ttl())
+	if err := s.repo.RenewToken(ctx, inv.ID, inv.TokenHash, inv.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	name := ""
+	if inv.UserID != nil {
+		if invitee, err := s.userRepo.FindByID(ctx, *inv.UserID); err == nil {
+			name = invitee.Name
+		}
+	}
+
+	s.send(ctx, inv, name, token)
+	return inv, nil
+}
+
+func (s *invitationService) Revoke(ctx context.Context, id uuid.UUID) error {
+	inv, err := s.repo.FindByID(ctx, tenant.OrganizationID(ctx), id)
+	if err != nil {
+		return err
+	}
+	if !inv.Open() {
+		return ErrInvitationClosed
+	}
+	return s.repo.Revoke(ctx, inv)
+}
+
+func (s *invitationService) Accept(ctx context.Context, token string, name string, password string) error {
+	inv, err := s.repo.FindByTokenHash(ctx, hashToken(token))
+	if err != nil {
+		return ErrInvalidInvitation
+	}
+	if inv.Status(time.Now()) != StatusPending || inv.UserID == nil {
+		return ErrInvalidInvitation
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cfg.BcryptCost)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.Accept(ctx, inv, strings.TrimSpace(name), string(hash))
+}
+
+func (s *invitationService) send(ctx context.Context, inv *Invitation, name string, token string) {
+	greeting := "Olá"
+	if name != "" {
+		greeting = "Olá " + name
+	}
+
+	org, err := s.orgRepo.FindByID(ctx, inv.OrganizationID)
+	if err != nil {
+		log.Printf("[ERROR] Failed to load organization %s for invitation %s: %v", inv.OrganizationID, inv.ID, err)
+		return
+	}
+
+	err = s.mailer.Send(ctx, notification.Message{
+		To:      inv.Email,
+		Subject: fmt.Sprintf("Convite para %s", org.Name),
+		Body: fmt.Sprintf(
+			"%s,\n\nVocê foi convidado para acessar %s. Para definir sua senha e ativar a conta, acesse:\n%s/invitations/accept?token=%s\n\nO convite expira em %d horas.",
+			greeting, org.Name, s.cfg.AppPublicURL, token, s.cfg.InvitationTTLHours,
+		),
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to send invitation %s: %v", inv.ID, err)
+	}
+}
+
+func (s *invitationService) ttl() time.Duration {
+	return time.Duration(s.cfg.InvitationTTLHours) * time.Hour
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,100 @@
+package loginhistory
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Entry is a login attempt of an existing user, successful or not.
+type Entry struct {
+	ID                uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	OrganizationID    uuid.UUID `gorm:"type:uuid" json:"organization_id"`
+	UserID            uuid.UUID `gorm:"type:uuid" json:"user_id"`
+	Success           bool      `json:"success"`
+	FailureReason     string    `json:"failure_reason"`
+	IPAddress         string    `json:"ip_address"`
+	IPRange           string    `json:"ip_range"`
+	UserAgent         string    `json:"user_agent"`
+	DeviceFingerprint string    `json:"device_fingerprint"`
+	NewDevice         bool      `json:"new_device"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+func (Entry) TableName() string {
+	return "login_history"
+}
+
+// Seen tells which origins of a login were already used in earlier
+// successful logins of the same user.
+type Seen struct {
+	AnyLogin bool
+	Device   bool
+	IPRange  bool
+}
+
+type Page struct {
+	Entries    []Entry
+	NextCursor string
+}
+
+// Cursor points at the last entry of a page, newest first.
+type Cursor struct {
+	CreatedAt time.Time `json:"t"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (c Cursor) Encode() string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == uuid.Nil {
+		return nil, errors.New("cursor without id")
+	}
+	return &c, nil
+}
+
+// DeviceFingerprint identifies the client software of a login: the SHA-256 of
+// the normalized user agent.
+func DeviceFingerprint(userAgent string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(userAgent))))
+	return hex.EncodeToString(sum[:])
+}
+
+// IPRange groups nearby addresses (IPv4 /24, IPv6 /48), so a new address from
+// the same network is not treated as a new origin.
+func IPRange(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ""
+	}
+	addr = addr.Unmap()
+
+	bits := 48
+	if addr.Is4() {
+		bits = 24
+	}
+	prefix, err := addr.Prefix(bits)
+	if err != nil {
+		return ""
+	}
+	return prefix.String()
+}
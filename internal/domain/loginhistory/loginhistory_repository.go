@@ -0,0 +1,14 @@
+package loginhistory
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IRepository interface {
+	Create(ctx context.Context, entry *Entry) error
+	// Seen compares the origin with the earlier successful logins of the user.
+	Seen(ctx context.Context, userID uuid.UUID, fingerprint string, ipRange string) (*Seen, error)
+	List(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, after *Cursor, limit int) ([]Entry, error)
+}
@@ -0,0 +1,131 @@
+package loginhistory
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+const (
+	defaultPageSize    = 20
+	maxUserAgentLength = 512
+	mailTimeout        = 30 * time.Second
+)
+
+type IService interface {
+	// RecordSuccess stores the login and, when it comes from a device or IP
+	// range never used by the user before, mails a security notice.
+	RecordSuccess(ctx context.Context, u *user.User)
+	RecordFailure(ctx context.Context, u *user.User, reason string)
+	List(ctx context.Context, userID uuid.UUID, cursor *Cursor, limit int) (*Page, error)
+}
+
+type loginHistoryService struct {
+	repo   IRepository
+	mailer notification.IMailer
+}
+
+func NewLoginHistoryService(repo IRepository, mailer notification.IMailer) IService {
+	return &loginHistoryService{repo: repo, mailer: mailer}
+}
+
+func (s *loginHistoryService) RecordSuccess(ctx context.Context, u *user.User) {
+	entry := newEntry(ctx, u)
+	entry.Success = true
+
+	seen, err := s.repo.Seen(ctx, u.ID, entry.DeviceFingerprint, entry.IPRange)
+	if err != nil {
+		log.Printf("[ERROR] Failed to check known login origins of user %s: %v", u.ID, err)
+	} else {
+		// The first login of an account has nothing to compare with.
+		entry.NewDevice = seen.AnyLogin && (!seen.Device || !seen.IPRange)
+	}
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		log.Printf("[ERROR] Failed to record login of user %s: %v", u.ID, err)
+	}
+
+	if entry.NewDevice {
+		go s.notifyNewDevice(context.WithoutCancel(ctx), u, entry)
+	}
+}
+
+func (s *loginHistoryService) RecordFailure(ctx context.Context, u *user.User, reason string) {
+	entry := newEntry(ctx, u)
+	entry.FailureReason = reason
+
+	if err := s.repo.Create(ctx, entry); err != nil {
+		log.Printf("[ERROR] Failed to record failed login of user %s: %v", u.ID, err)
+	}
+}
+
+// List returns the logins of a user of the organization of the context,
+// newest first.
+func (s *loginHistoryService) List(ctx context.Context, userID uuid.UUID, cursor *Cursor, limit int) (*Page, error) {
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+
+	// One extra row tells whether there is a next page.
+	entries, err := s.repo.List(ctx, tenant.OrganizationID(ctx), userID, cursor, limit+1)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page{Entries: entries}
+	if len(entries) > limit {
+		page.Entries = entries[:limit]
+		last := page.Entries[limit-1]
+		page.NextCursor = Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+	}
+	return page, nil
+}
+
+func (s *loginHistoryService) notifyNewDevice(ctx context.Context, u *user.User, entry *Entry) {
+	ctx, cancel := context.WithTimeout(ctx, mailTimeout)
+	defer cancel()
+
+	userAgent := entry.UserAgent
+	if userAgent == "" {
+		userAgent = "desconhecido"
+	}
+
+	err := s.mailer.Send(ctx, notification.Message{
+		To:      u.Email,
+		Subject: "Novo acesso à sua conta",
+		Body: fmt.Sprintf(
+			"Olá %s,\n\nDetectamos um acesso à sua conta a partir de um dispositivo ou rede não utilizados antes:\n\nData: %s UTC\nIP: %s\nDispositivo: %s\n\nSe foi você, nenhuma ação é necessária. Caso contrário, altere sua senha e encerre todas as sessões.",
+			u.Name, entry.CreatedAt.UTC().Format("02/01/2006 15:04"), entry.IPAddress, userAgent,
+		),
+	})
+	if err != nil {
+		log.Printf("[ERROR] Failed to send new device notice to user %s: %v", u.ID, err)
+	}
+}
+
+func newEntry(ctx context.Context, u *user.User) *Entry {
+	request := audit.RequestFrom(ctx)
+	userAgent := request.UserAgent
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLength], "")
+	}
+
+	return &Entry{
+		ID:                uuid.New(),
+		OrganizationID:    u.OrganizationID,
+		UserID:            u.ID,
+		IPAddress:         request.IPAddress,
+		IPRange:           IPRange(request.IPAddress),
+		UserAgent:         userAgent,
+		DeviceFingerprint: DeviceFingerprint(request.UserAgent),
+		CreatedAt:         time.Now().UTC(),
+	}
+}
@@ -0,0 +1,13 @@
+package notification
+
+import "context"
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+type IMailer interface {
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,13 @@
+package organization
+
+import "errors"
+
+var (
+	ErrOrganizationNotFound = errors.New("organization not found")
+	ErrSlugTaken            = errors.New("organization slug already exists")
+	ErrInvalidSlug          = errors.New("invalid organization slug")
+	ErrMemberNotFound       = errors.New("member not found")
+	ErrInvalidMemberRole    = errors.New("invalid organization role")
+	ErrOwnerProtected       = errors.New("organization owners cannot be changed by admins")
+	ErrCannotManageSelf     = errors.New("members cannot change their own membership")
+)
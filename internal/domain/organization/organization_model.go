@@ -0,0 +1,55 @@
+package organization
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	RoleOwner  = "org_owner"
+	RoleAdmin  = "org_admin"
+	RoleMember = "org_member"
+)
+
+const StatusActive = "active"
+
+const (
+	PermMembersRead        = "members:read"
+	PermMembersWrite       = "members:write"
+	PermOrganizationsRead  = "organizations:read"
+	PermOrganizationsWrite = "organizations:write"
+)
+
+type Organization struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	Name            string     `json:"name"`
+	Slug            string     `json:"slug"`
+	Status          string     `json:"status"`
+	AllowSelfSignup bool       `gorm:"column:allow_self_signup" json:"allow_self_signup"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       *time.Time `json:"updated_at"`
+}
+
+// NewOrganization is the input of CreateOrganization: the organization and
+// the credentials of its first owner.
+type NewOrganization struct {
+	Name            string
+	Slug            string
+	AllowSelfSignup bool
+	OwnerName       string
+	OwnerEmail      string
+	OwnerPassword   string
+}
+
+// Member is a user of the organization joined with its per-organization role.
+type Member struct {
+	UserID   uuid.UUID  `json:"user_id"`
+	Name     string     `json:"name"`
+	Email    string     `json:"email"`
+	Status   string     `json:"status"`
+	RoleID   uuid.UUID  `json:"role_id"`
+	RoleName string     `json:"role"`
+	JoinedAt time.Time  `json:"joined_at"`
+	LastSeen *time.Time `json:"last_login_at"`
+}
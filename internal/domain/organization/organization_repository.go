@@ -0,0 +1,26 @@
+package organization
+
+import (
+	"context"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/google/uuid"
+)
+
+type IRepository interface {
+	Create(ctx context.Context, org *Organization) error
+	List(ctx context.Context) ([]Organization, error)
+	FindByID(ctx context.Context, id uuid.UUID) (*Organization, error)
+	FindBySlug(ctx context.Context, slug string) (*Organization, error)
+	AddMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, roleName string) error
+	FindMemberRole(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (*rbac.Role, error)
+	ListMembers(ctx context.Context, organizationID uuid.UUID) ([]Member, error)
+	UpdateMemberRole(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, roleName string) error
+	RemoveMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) error
+}
+
+// ISessionStore drops the cached token version and the refresh tokens of a
+// user whose token version was bumped. The auth cache repository satisfies it.
+type ISessionStore interface {
+	RevokeUserSessions(ctx context.Context, userID string) error
+}
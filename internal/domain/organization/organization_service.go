@@ -0,0 +1,169 @@
+package organization
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/felipedenardo/chameleon-common/pkg/base"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9](?:[a-z0-9-]{0,61}[a-z0-9])?$`)
+
+type IService interface {
+	CreateOrganization(ctx context.Context, input NewOrganization) (*Organization, error)
+	ListOrganizations(ctx context.Context) ([]Organization, error)
+	GetOrganization(ctx context.Context, id uuid.UUID) (*Organization, error)
+	ListMembers(ctx context.Context, organizationID uuid.UUID) ([]Member, error)
+	UpdateMemberRole(ctx context.Context, organizationID uuid.UUID, actorID uuid.UUID, userID uuid.UUID, roleName string) error
+	RemoveMember(ctx context.Context, organizationID uuid.UUID, actorID uuid.UUID, userID uuid.UUID) error
+}
+
+type organizationService struct {
+	repo     IRepository
+	userRepo user.IRepository
+	rbacRepo rbac.IRepository
+	sessions ISessionStore
+	cfg      *config.Config
+}
+
+func NewOrganizationService(repo IRepository, userRepo user.IRepository, rbacRepo rbac.IRepository, sessions ISessionStore, cfg *config.Config) IService {
+	return &organizationService{
+		repo:     repo,
+		userRepo: userRepo,
+		rbacRepo: rbacRepo,
+		sessions: sessions,
+		cfg:      cfg,
+	}
+}
+
+// CreateOrganization creates the organization together with its owner. The
+// owner password is chosen by the platform admin, so it must be changed on the
+// first login.
+func (s *organizationService) CreateOrganization(ctx context.Context, input NewOrganization) (*Organization, error) {
+	slug := strings.ToLower(strings.TrimSpace(input.Slug))
+	if !slugPattern.MatchString(slug) {
+		return nil, ErrInvalidSlug
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(input.OwnerPassword), s.cfg.BcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	org := &Organization{
+		ID:              uuid.New(),
+		Name:            strings.TrimSpace(input.Name),
+		Slug:            slug,
+		Status:          StatusActive,
+		AllowSelfSignup: input.AllowSelfSignup,
+	}
+	if err := s.repo.Create(ctx, org); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	owner := &user.User{
+		Model: base.Model{
+			ID: uuid.New(),
+		},
+		Name:               strings.TrimSpace(input.OwnerName),
+		Email:              strings.ToLower(strings.TrimSpace(input.OwnerEmail)),
+		PasswordHash:       string(hash),
+		Role:               user.RoleUser,
+		Status:             user.StatusActive,
+		PasswordChangedAt:  &now,
+		MustChangePassword: true,
+	}
+
+	scopedCtx := tenant.WithOrganization(ctx, org.ID)
+	if err := s.userRepo.Create(scopedCtx, owner); err != nil {
+		return nil, err
+	}
+	if err := s.rbacRepo.AddUserRole(scopedCtx, owner.ID, rbac.RoleUser); err != nil {
+		return nil, err
+	}
+	if err := s.repo.AddMember(ctx, org.ID, owner.ID, RoleOwner); err != nil {
+		return nil, err
+	}
+	return org, nil
+}
+
+func (s *organizationService) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	return s.repo.List(ctx)
+}
+
+func (s *organizationService) GetOrganization(ctx context.Context, id uuid.UUID) (*Organization, error) {
+	return s.repo.FindByID(ctx, id)
+}
+
+func (s *organizationService) ListMembers(ctx context.Context, organizationID uuid.UUID) ([]Member, error) {
+	return s.repo.ListMembers(ctx, organizationID)
+}
+
+func (s *organizationService) UpdateMemberRole(ctx context.Context, organizationID uuid.UUID, actorID uuid.UUID, userID uuid.UUID, roleName string) error {
+	if roleName != RoleAdmin && roleName != RoleMember {
+		return ErrInvalidMemberRole
+	}
+	if err := s.checkManageable(ctx, organizationID, actorID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.UpdateMemberRole(ctx, organizationID, userID, roleName); err != nil {
+		return err
+	}
+
+	// The organization role is carried by the access token permissions.
+	return s.revokeSessions(tenant.WithOrganization(ctx, organizationID), userID)
+}
+
+// RemoveMember deactivates the user, since identities belong to a single
+// organization, and revokes every session.
+func (s *organizationService) RemoveMember(ctx context.Context, organizationID uuid.UUID, actorID uuid.UUID, userID uuid.UUID) error {
+	if err := s.checkManageable(ctx, organizationID, actorID, userID); err != nil {
+		return err
+	}
+
+	scopedCtx := tenant.WithOrganization(ctx, organizationID)
+	if err := s.userRepo.ChangeStatus(scopedCtx, userID, user.StatusChange{
+		Status:    user.StatusInactive,
+		Source:    user.SourceAdmin,
+		Reason:    "removed from organization",
+		ChangedBy: &actorID,
+	}); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveMember(ctx, organizationID, userID); err != nil {
+		return err
+	}
+	return s.revokeSessions(scopedCtx, userID)
+}
+
+func (s *organizationService) revokeSessions(ctx context.Context, userID uuid.UUID) error {
+	if err := s.userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+	return s.sessions.RevokeUserSessions(ctx, userID.String())
+}
+
+func (s *organizationService) checkManageable(ctx context.Context, organizationID uuid.UUID, actorID uuid.UUID, userID uuid.UUID) error {
+	if actorID == userID {
+		return ErrCannotManageSelf
+	}
+
+	current, err := s.repo.FindMemberRole(ctx, organizationID, userID)
+	if err != nil {
+		return err
+	}
+	if current.Name == RoleOwner {
+		return ErrOwnerProtected
+	}
+	return nil
+}
@@ -0,0 +1,12 @@
+package personaltoken
+
+import "errors"
+
+var (
+	ErrInvalidToken  = errors.New("invalid or expired personal access token")
+	ErrTokenNotFound = errors.New("personal access token not found")
+	ErrNameTaken     = errors.New("personal access token name is already in use")
+	ErrInvalidScope  = errors.New("scope is not granted to the user")
+	ErrInvalidExpiry = errors.New("invalid personal access token expiration")
+	ErrTooManyTokens = errors.New("personal access token limit reached")
+)
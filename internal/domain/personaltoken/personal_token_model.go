@@ -0,0 +1,59 @@
+package personaltoken
+
+import (
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// TokenPrefix starts every personal access token, which is how the auth
+// middleware tells them from JWTs and how leaked tokens are spotted.
+const TokenPrefix = "pat_"
+
+// MaxTokensPerUser caps the tokens of a user; expired tokens count until
+// they are revoked.
+const MaxTokensPerUser = 50
+
+// Token is a long-lived credential a user creates for scripts and CI jobs.
+// Only the SHA-256 of the token is stored; Prefix is kept so the user can
+// tell tokens apart. Scopes are permissions of the user, space-delimited.
+// TokenVersion is the token version of the user at creation: revoking the
+// sessions of the user bumps it and revokes the token as well.
+type Token struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid" json:"-"`
+	UserID         uuid.UUID  `gorm:"type:uuid" json:"-"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	TokenHash      string     `json:"-"`
+	Scopes         string     `json:"-"`
+	TokenVersion   int        `json:"-"`
+	ExpiresAt      time.Time  `json:"expires_at"`
+	LastUsedAt     *time.Time `json:"last_used_at"`
+	CreatedAt      time.Time  `json:"created_at"`
+	// Revoked is set on listing when the sessions of the user were revoked
+	// after the token was created.
+	Revoked bool `gorm:"-" json:"-"`
+}
+
+func (Token) TableName() string {
+	return "personal_access_tokens"
+}
+
+func (t *Token) ScopeList() []string {
+	return strings.Fields(t.Scopes)
+}
+
+func (t *Token) Expired(now time.Time) bool {
+	return !now.Before(t.ExpiresAt)
+}
+
+// Principal is the user authenticated by a token. Permissions are the scopes
+// of the token the user still holds.
+type Principal struct {
+	Token       *Token
+	User        *user.User
+	Permissions []string
+}
@@ -0,0 +1,20 @@
+package personaltoken
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IRepository interface {
+	Create(ctx context.Context, token *Token) error
+	// ListByUser returns the tokens of the user, expired ones included.
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]Token, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	// FindByHash looks the token up across organizations and returns
+	// ErrInvalidToken when no token has the hash.
+	FindByHash(ctx context.Context, tokenHash string) (*Token, error)
+	// Touch records the use of the token, at most once a minute.
+	Touch(ctx context.Context, id uuid.UUID) error
+}
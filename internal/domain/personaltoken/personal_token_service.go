@@ -0,0 +1,19 @@
+package personaltoken
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// IService manages the personal access tokens of the authenticated user.
+type IService interface {
+	// Create returns the token and its only plain copy. Scopes must be
+	// permissions the user holds; expiresInDays zero uses the default.
+	Create(ctx context.Context, userID uuid.UUID, name string, scopes []string, expiresInDays int) (*Token, string, error)
+	List(ctx context.Context, userID uuid.UUID) ([]Token, error)
+	Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+	// Authenticate returns the user of rawToken, from any organization; the
+	// caller scopes the request to the organization of the token.
+	Authenticate(ctx context.Context, rawToken string) (*Principal, error)
+}
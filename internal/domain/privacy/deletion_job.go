@@ -0,0 +1,46 @@
+package privacy
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// DeletionJob anonymizes the accounts whose deletion grace period ended.
+type DeletionJob struct {
+	service  IService
+	interval time.Duration
+}
+
+func NewDeletionJob(service IService, interval time.Duration) *DeletionJob {
+	return &DeletionJob{service: service, interval: interval}
+}
+
+// Run sweeps until ctx is cancelled.
+func (j *DeletionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		j.sweep(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *DeletionJob) sweep(ctx context.Context) {
+	purged, err := j.service.PurgeDue(ctx, time.Now())
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("[ERROR] Failed to look up due account deletions: %v", err)
+		}
+		return
+	}
+	if purged > 0 {
+		log.Printf("[INFO] Anonymized %d account(s) after the deletion grace period", purged)
+	}
+}
@@ -0,0 +1,20 @@
+package privacy
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+)
+
+// Export is the archive of everything stored about a user, returned by
+// GET /me/export.
+type Export struct {
+	ExportedAt    time.Time                 `json:"exported_at"`
+	Profile       *user.User                `json:"profile"`
+	Roles         []string                  `json:"roles"`
+	StatusHistory []user.StatusHistoryEntry `json:"status_history"`
+	LoginHistory  []loginhistory.Entry      `json:"login_history"`
+	AuditEvents   []audit.Event             `json:"audit_events"`
+}
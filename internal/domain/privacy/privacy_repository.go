@@ -0,0 +1,11 @@
+package privacy
+
+import (
+	"context"
+)
+
+// ICacheStore drops every cached key of a user. The auth cache repository
+// satisfies it.
+type ICacheStore interface {
+	PurgeUser(ctx context.Context, userID string) error
+}
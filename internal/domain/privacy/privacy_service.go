@@ -0,0 +1,145 @@
+package privacy
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+const (
+	exportPageSize = 500
+	purgeBatchSize = 100
+)
+
+// Sources of an anonymization, recorded in the audit event.
+const (
+	sourceAdmin     = "admin"
+	sourceScheduled = "scheduled"
+)
+
+type IService interface {
+	Export(ctx context.Context, userID uuid.UUID) (*Export, error)
+	// Purge anonymizes the user right away.
+	Purge(ctx context.Context, userID uuid.UUID) error
+	// PurgeDue anonymizes, across organizations, the users whose deletion
+	// grace period ended, and returns how many were anonymized.
+	PurgeDue(ctx context.Context, now time.Time) (int, error)
+}
+
+type privacyService struct {
+	userRepo    user.IRepository
+	rbacRepo    rbac.IRepository
+	historyRepo loginhistory.IRepository
+	auditLog    audit.IService
+	cache       ICacheStore
+}
+
+func NewPrivacyService(userRepo user.IRepository, rbacRepo rbac.IRepository, historyRepo loginhistory.IRepository, auditLog audit.IService, cache ICacheStore) IService {
+	return &privacyService{
+		userRepo:    userRepo,
+		rbacRepo:    rbacRepo,
+		historyRepo: historyRepo,
+		auditLog:    auditLog,
+		cache:       cache,
+	}
+}
+
+func (s *privacyService) Export(ctx context.Context, userID uuid.UUID) (*Export, error) {
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &Export{ExportedAt: time.Now().UTC(), Profile: u, Roles: []string{}}
+
+	roles, err := s.rbacRepo.GetUserRoles(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		export.Roles = append(export.Roles, role.Name)
+	}
+
+	if export.StatusHistory, err = s.userRepo.ListStatusHistory(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.LoginHistory, err = s.loginHistory(ctx, userID); err != nil {
+		return nil, err
+	}
+	if export.AuditEvents, err = s.auditLog.ForUser(ctx, userID); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+func (s *privacyService) loginHistory(ctx context.Context, userID uuid.UUID) ([]loginhistory.Entry, error) {
+	entries := []loginhistory.Entry{}
+	var after *loginhistory.Cursor
+	for {
+		page, err := s.historyRepo.List(ctx, tenant.OrganizationID(ctx), userID, after, exportPageSize)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, page...)
+		if len(page) < exportPageSize {
+			return entries, nil
+		}
+		last := page[len(page)-1]
+		after = &loginhistory.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}
+	}
+}
+
+func (s *privacyService) Purge(ctx context.Context, userID uuid.UUID) error {
+	return s.purge(ctx, userID, sourceAdmin)
+}
+
+// purge redacts the audit trail first: it can be retried, while the e-mail it
+// matches is gone once the user row is anonymized.
+func (s *privacyService) purge(ctx context.Context, userID uuid.UUID, source string) error {
+	u, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.auditLog.Redact(ctx, userID, u.Email); err != nil {
+		return err
+	}
+	if err := s.userRepo.Anonymize(ctx, userID); err != nil {
+		return err
+	}
+	if err := s.cache.PurgeUser(ctx, userID.String()); err != nil {
+		log.Printf("[ERROR] Failed to purge cached keys of user %s: %v", userID, err)
+	}
+
+	s.auditLog.Record(ctx, audit.Event{
+		TargetID: &userID,
+		Action:   audit.ActionAnonymized,
+		Outcome:  audit.OutcomeSuccess,
+		Metadata: audit.Metadata{"source": source},
+	})
+	return nil
+}
+
+func (s *privacyService) PurgeDue(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.userRepo.FindDueDeletions(ctx, now, purgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, u := range due {
+		if err := s.purge(tenant.WithOrganization(ctx, u.OrganizationID), u.ID, sourceScheduled); err != nil {
+			log.Printf("[ERROR] Failed to anonymize user %s: %v", u.ID, err)
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
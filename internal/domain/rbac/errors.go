@@ -0,0 +1,10 @@
+package rbac
+
+import "errors"
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrRoleNameTaken     = errors.New("role name already exists")
+	ErrSystemRole        = errors.New("system roles cannot be modified or removed")
+	ErrUnknownPermission = errors.New("unknown permission")
+)
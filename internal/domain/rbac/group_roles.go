@@ -0,0 +1,30 @@
+package rbac
+
+import (
+	"slices"
+	"strings"
+)
+
+// GroupRoles maps the groups of an external identity source, such as a
+// directory or a SAML identity provider, to roles.
+type GroupRoles map[string][]string
+
+// RolesFor returns the roles mapped to groups, in a stable order, and whether
+// any group matched. Group names are compared case-insensitively.
+func (g GroupRoles) RolesFor(groups []string) ([]string, bool) {
+	var roles []string
+	matched := false
+	for group, mapped := range g {
+		if !slices.ContainsFunc(groups, func(name string) bool { return strings.EqualFold(name, group) }) {
+			continue
+		}
+		matched = true
+		for _, role := range mapped {
+			if !slices.Contains(roles, role) {
+				roles = append(roles, role)
+			}
+		}
+	}
+	slices.Sort(roles)
+	return roles, matched
+}
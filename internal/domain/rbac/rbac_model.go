@@ -0,0 +1,55 @@
+package rbac
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	PermUsersRead   = "users:read"
+	PermUsersWrite  = "users:write"
+	PermUsersImport = "users:import"
+	PermUsersExport = "users:export"
+	PermRolesRead   = "roles:read"
+	PermRolesWrite  = "roles:write"
+)
+
+// PermUsersImpersonate allows exchanging an access token for a short-lived
+// token of another user of the organization.
+const PermUsersImpersonate = "users:impersonate"
+
+// PermUsersDelete allows anonymizing a user right away, without the grace
+// period of a self-requested deletion.
+const PermUsersDelete = "users:delete"
+
+const (
+	RoleAdmin = "admin"
+	RoleUser  = "user"
+)
+
+type Permission struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Role struct {
+	ID          uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	IsSystem    bool         `gorm:"column:is_system" json:"is_system"`
+	Permissions []Permission `gorm:"many2many:role_permissions" json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   *time.Time   `json:"updated_at"`
+}
+
+// PermissionNames returns the names of the role permissions.
+func (r *Role) PermissionNames() []string {
+	names := make([]string, 0, len(r.Permissions))
+	for _, p := range r.Permissions {
+		names = append(names, p.Name)
+	}
+	return names
+}
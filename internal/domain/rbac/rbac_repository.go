@@ -0,0 +1,21 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IRepository interface {
+	ListRoles(ctx context.Context) ([]Role, error)
+	FindRoleByID(ctx context.Context, id uuid.UUID) (*Role, error)
+	FindRolesByNames(ctx context.Context, names []string) ([]Role, error)
+	CreateRole(ctx context.Context, role *Role, permissionNames []string) error
+	UpdateRole(ctx context.Context, role *Role, permissionNames []string) error
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	ListPermissions(ctx context.Context) ([]Permission, error)
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]Role, error)
+	SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) error
+	AddUserRole(ctx context.Context, userID uuid.UUID, roleName string) error
+	GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error)
+}
@@ -0,0 +1,134 @@
+package rbac
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+type IService interface {
+	ListRoles(ctx context.Context) ([]Role, error)
+	GetRole(ctx context.Context, id uuid.UUID) (*Role, error)
+	CreateRole(ctx context.Context, name string, description string, permissions []string) (*Role, error)
+	UpdateRole(ctx context.Context, id uuid.UUID, name *string, description *string, permissions []string) (*Role, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	ListPermissions(ctx context.Context) ([]Permission, error)
+	GetUserRoles(ctx context.Context, userID uuid.UUID) ([]Role, error)
+	SetUserRoles(ctx context.Context, userID uuid.UUID, roleNames []string) ([]Role, error)
+}
+
+type rbacService struct {
+	repo IRepository
+}
+
+func NewRBACService(repo IRepository) IService {
+	return &rbacService{repo: repo}
+}
+
+func (s *rbacService) ListRoles(ctx context.Context) ([]Role, error) {
+	return s.repo.ListRoles(ctx)
+}
+
+func (s *rbacService) GetRole(ctx context.Context, id uuid.UUID) (*Role, error) {
+	return s.repo.FindRoleByID(ctx, id)
+}
+
+func (s *rbacService) CreateRole(ctx context.Context, name string, description string, permissions []string) (*Role, error) {
+	role := &Role{
+		ID:          uuid.New(),
+		Name:        normalizeRoleName(name),
+		Description: strings.TrimSpace(description),
+	}
+
+	if err := s.repo.CreateRole(ctx, role, permissions); err != nil {
+		return nil, err
+	}
+	return s.repo.FindRoleByID(ctx, role.ID)
+}
+
+func (s *rbacService) UpdateRole(ctx context.Context, id uuid.UUID, name *string, description *string, permissions []string) (*Role, error) {
+	role, err := s.repo.FindRoleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if role.IsSystem {
+		return nil, ErrSystemRole
+	}
+
+	if name != nil {
+		role.Name = normalizeRoleName(*name)
+	}
+	if description != nil {
+		role.Description = strings.TrimSpace(*description)
+	}
+	if permissions == nil {
+		permissions = role.PermissionNames()
+	}
+
+	if err := s.repo.UpdateRole(ctx, role, permissions); err != nil {
+		return nil, err
+	}
+	return s.repo.FindRoleByID(ctx, id)
+}
+
+func (s *rbacService) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	role, err := s.repo.FindRoleByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return ErrSystemRole
+	}
+	return s.repo.DeleteRole(ctx, id)
+}
+
+func (s *rbacService) ListPermissions(ctx context.Context) ([]Permission, error) {
+	return s.repo.ListPermissions(ctx)
+}
+
+func (s *rbacService) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]Role, error) {
+	return s.repo.GetUserRoles(ctx, userID)
+}
+
+func (s *rbacService) SetUserRoles(ctx context.Context, userID uuid.UUID, roleNames []string) ([]Role, error) {
+	names := make([]string, 0, len(roleNames))
+	for _, name := range roleNames {
+		names = append(names, normalizeRoleName(name))
+	}
+
+	roles, err := s.repo.FindRolesByNames(ctx, names)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) != len(uniqueStrings(names)) {
+		return nil, ErrRoleNotFound
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	for _, r := range roles {
+		roleIDs = append(roleIDs, r.ID)
+	}
+
+	if err := s.repo.SetUserRoles(ctx, userID, roleIDs); err != nil {
+		return nil, err
+	}
+	return s.repo.GetUserRoles(ctx, userID)
+}
+
+func normalizeRoleName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]struct{}, len(values))
+	unique := make([]string, 0, len(values))
+	for _, v := range values {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		unique = append(unique, v)
+	}
+	return unique
+}
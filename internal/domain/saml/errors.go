@@ -0,0 +1,15 @@
+package saml
+
+import "errors"
+
+var (
+	ErrProviderNotFound    = errors.New("SAML identity provider not found")
+	ErrInvalidRelayState   = errors.New("invalid or expired SAML request")
+	ErrInvalidResponse     = errors.New("invalid SAML response")
+	ErrAssertionReplayed   = errors.New("SAML assertion was already used")
+	ErrMissingAttributes   = errors.New("assertion lacks the subject or the email of the user")
+	ErrDomainNotAllowed    = errors.New("email domain is not allowed for this identity provider")
+	ErrAccountNotFound     = errors.New("no account for this identity and provisioning is disabled")
+	ErrAccountLinkRequired = errors.New("an account with this email exists and the provider does not link it")
+	ErrInvalidLoginCode    = errors.New("invalid or expired SAML login code")
+)
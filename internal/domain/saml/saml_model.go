@@ -0,0 +1,143 @@
+package saml
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+)
+
+const (
+	NameIDFormatEmail      = "urn:oasis:names:tc:SAML:1.1:nameid-format:emailAddress"
+	NameIDFormatPersistent = "urn:oasis:names:tc:SAML:2.0:nameid-format:persistent"
+	NameIDFormatTransient  = "urn:oasis:names:tc:SAML:2.0:nameid-format:transient"
+)
+
+// IdentityProvider is a SAML 2.0 identity provider, such as ADFS, Okta or
+// Keycloak. Each provider signs users into a single organization.
+type IdentityProvider struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	EntityID    string `json:"entity_id"`
+	// SSOURL receives the AuthnRequest through the HTTP-Redirect binding.
+	SSOURL string `json:"sso_url"`
+	// Certificates are the PEM certificates the provider signs with. More
+	// than one allows rolling the key over.
+	Certificates []string `json:"certificates"`
+	// Organization is the slug of the organization of the users; empty means
+	// the default organization.
+	Organization string `json:"organization"`
+	// AllowedDomains restricts the e-mail domains accepted from the provider.
+	// Empty accepts any domain.
+	AllowedDomains []string `json:"allowed_domains"`
+	// JITProvisioning creates users on their first sign-in.
+	JITProvisioning bool `json:"jit_provisioning"`
+	// LinkExistingAccounts links the first sign-in to the user with the same
+	// e-mail. Otherwise the sign-in is refused.
+	LinkExistingAccounts bool             `json:"link_existing_accounts"`
+	Attributes           AttributeMapping `json:"attributes"`
+	// GroupRoles maps a group of the assertion to the roles of its members.
+	// When set, every sign-in replaces the roles of the user with the roles
+	// of its groups, or the default user role when no group matches.
+	GroupRoles rbac.GroupRoles `json:"group_roles"`
+}
+
+// AttributeMapping names the attributes of the assertion copied to the user.
+// Each field lists the accepted names in order of preference.
+type AttributeMapping struct {
+	// Subject is a stable identifier of the user. Empty uses the NameID,
+	// which must then not be transient.
+	Subject []string `json:"subject"`
+	Email   []string `json:"email"`
+	Name    []string `json:"name"`
+	Groups  []string `json:"groups"`
+}
+
+// AllowsEmail reports whether the domain of email is in AllowedDomains.
+func (p IdentityProvider) AllowsEmail(email string) bool {
+	if len(p.AllowedDomains) == 0 {
+		return true
+	}
+	_, domain, found := strings.Cut(email, "@")
+	if !found {
+		return false
+	}
+	return slices.ContainsFunc(p.AllowedDomains, func(allowed string) bool {
+		return strings.EqualFold(allowed, domain)
+	})
+}
+
+// IdentityProvider is the provider of the identities linking the subjects of
+// the provider to users.
+func (p IdentityProvider) IdentityProvider() string {
+	return "saml:" + p.Name
+}
+
+// ProviderInfo is the public description of a provider.
+type ProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// Assertion is the validated assertion of a response.
+type Assertion struct {
+	ID           string
+	NameID       string
+	NameIDFormat string
+	Attributes   map[string][]string
+	// NotOnOrAfter is the end of the validity window of the assertion. A
+	// replayed assertion is rejected until then.
+	NotOnOrAfter time.Time
+}
+
+// Value returns the first value of the first attribute in names.
+func (a *Assertion) Value(names []string) string {
+	for _, name := range names {
+		for _, value := range a.Attributes[name] {
+			if value = strings.TrimSpace(value); value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// Values returns the values of the first attribute in names.
+func (a *Assertion) Values(names []string) []string {
+	for _, name := range names {
+		if values := a.Attributes[name]; len(values) > 0 {
+			return values
+		}
+	}
+	return nil
+}
+
+// Subject returns the identifier linking the assertion to a user.
+func (a *Assertion) Subject(mapping AttributeMapping) string {
+	if len(mapping.Subject) > 0 {
+		return a.Value(mapping.Subject)
+	}
+	if a.NameIDFormat == NameIDFormatTransient {
+		return ""
+	}
+	return a.NameID
+}
+
+// Email returns the mapped e-mail, or the NameID when it is an e-mail.
+func (a *Assertion) Email(mapping AttributeMapping) string {
+	if email := a.Value(mapping.Email); email != "" {
+		return email
+	}
+	if a.NameIDFormat == NameIDFormatEmail {
+		return a.NameID
+	}
+	return ""
+}
+
+// PendingRequest is kept between the AuthnRequest and the response, under the
+// RelayState sent with the request.
+type PendingRequest struct {
+	Provider  string `json:"provider"`
+	RequestID string `json:"request_id"`
+}
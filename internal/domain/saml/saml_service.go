@@ -0,0 +1,36 @@
+package saml
+
+import (
+	"context"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+)
+
+type IService interface {
+	Providers() []ProviderInfo
+	// Metadata returns the metadata of the service provider, registered with
+	// the identity providers.
+	Metadata() ([]byte, error)
+	// StartLogin returns the URL of the provider the browser is sent to and
+	// the RelayState of the request.
+	StartLogin(ctx context.Context, providerName string) (redirectURL string, relayState string, err error)
+	// ConsumeResponse validates the response posted by the provider to the
+	// assertion consumer service, links or provisions the user and returns a
+	// one-time code that CompleteLogin exchanges for tokens.
+	ConsumeResponse(ctx context.Context, samlResponse string, relayState string) (code string, err error)
+	// CompleteLogin signs in the user of the code. relayState must be the
+	// one of the request, kept by the browser that started the login.
+	CompleteLogin(ctx context.Context, code string, relayState string) (*user.LoginResult, error)
+}
+
+// IServiceProvider implements the SAML protocol for the service.
+type IServiceProvider interface {
+	Metadata() ([]byte, error)
+	// AuthnRequestURL returns the redirect carrying a new AuthnRequest and
+	// the ID of the request.
+	AuthnRequestURL(idp IdentityProvider, relayState string) (redirectURL string, requestID string, err error)
+	// ParseResponse decodes the base64 SAMLResponse and returns its assertion
+	// once the signature, issuer, destination, audience, validity window and
+	// subject confirmation are verified. Errors wrap ErrInvalidResponse.
+	ParseResponse(idp IdentityProvider, samlResponse string, requestID string) (*Assertion, error)
+}
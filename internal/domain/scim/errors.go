@@ -0,0 +1,17 @@
+package scim
+
+import "errors"
+
+var (
+	ErrInvalidToken    = errors.New("invalid SCIM token")
+	ErrTokenNotFound   = errors.New("SCIM token not found")
+	ErrUserNotFound    = errors.New("SCIM user not found")
+	ErrGroupNotFound   = errors.New("SCIM group not found")
+	ErrUserNameTaken   = errors.New("userName is already in use")
+	ErrExternalIDTaken = errors.New("externalId is already in use")
+	ErrGroupNameTaken  = errors.New("displayName is already in use")
+	ErrInvalidFilter   = errors.New("unsupported SCIM filter")
+	ErrInvalidPath     = errors.New("invalid PATCH path")
+	ErrInvalidValue    = errors.New("invalid attribute value")
+	ErrUnknownRole     = errors.New("unknown role")
+)
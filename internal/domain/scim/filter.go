@@ -0,0 +1,60 @@
+package scim
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Filter is an equality filter on a single attribute, the only form the
+// identity providers send to look resources up. Attribute is lower case and
+// without the schema URN.
+type Filter struct {
+	Attribute string
+	Value     string
+}
+
+var filterPattern = regexp.MustCompile(`^\s*([A-Za-z][\w:.\-]*)\s+(?i:eq)\s+("(?:[^"\\]|\\.)*")\s*$`)
+
+// ParseFilter parses expressions such as `userName eq "ana@example.com"`.
+// allowed lists the attributes the resource can be filtered by; other
+// attributes and operators return ErrInvalidFilter. An empty expression
+// returns nil.
+func ParseFilter(expression string, allowed ...string) (*Filter, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, nil
+	}
+
+	match := filterPattern.FindStringSubmatch(expression)
+	if match == nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFilter, expression)
+	}
+	value, err := strconv.Unquote(match[2])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidFilter, expression)
+	}
+
+	attribute := attributeName(match[1])
+	if attribute == "emails" {
+		attribute = "emails.value"
+	}
+	for _, name := range allowed {
+		if strings.EqualFold(name, attribute) {
+			return &Filter{Attribute: strings.ToLower(name), Value: value}, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: cannot filter by %s", ErrInvalidFilter, match[1])
+}
+
+// attributeName lower-cases the attribute path and removes the core schema
+// URN, e.g. urn:ietf:params:scim:schemas:core:2.0:User:userName.
+func attributeName(path string) string {
+	path = strings.ToLower(strings.TrimSpace(path))
+	for _, schema := range []string{UserSchema, GroupSchema} {
+		if rest, found := strings.CutPrefix(path, strings.ToLower(schema)+":"); found {
+			return rest
+		}
+	}
+	return path
+}
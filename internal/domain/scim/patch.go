@@ -0,0 +1,302 @@
+package scim
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PatchOperation is an operation of a PATCH request. Op is add, replace or
+// remove, in any case; Value is the raw JSON value.
+type PatchOperation struct {
+	Op    string
+	Path  string
+	Value json.RawMessage
+}
+
+type patchKind int
+
+const (
+	patchAdd patchKind = iota
+	patchReplace
+	patchRemove
+)
+
+// nameParts collects the name attributes of a patch, since the account keeps
+// a single name.
+type nameParts struct {
+	formatted, given, family *string
+}
+
+var memberFilterPath = regexp.MustCompile(`^members\[(.+)\]$`)
+
+// ApplyUserPatch applies ops to attrs. Attributes the API does not keep, such
+// as phone numbers or the enterprise extension, are ignored, since identity
+// providers send every attribute they map.
+func ApplyUserPatch(attrs *UserAttributes, ops []PatchOperation) error {
+	var name nameParts
+	err := applyPatch(ops, func(kind patchKind, path string, value json.RawMessage) error {
+		return applyUserValue(attrs, &name, kind, path, value)
+	})
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case name.formatted != nil && *name.formatted != "":
+		attrs.Name = *name.formatted
+	case name.given != nil || name.family != nil:
+		attrs.Name = JoinName(deref(name.given), deref(name.family))
+	}
+	return nil
+}
+
+// ApplyGroupPatch applies ops to attrs.
+func ApplyGroupPatch(attrs *GroupAttributes, ops []PatchOperation) error {
+	return applyPatch(ops, func(kind patchKind, path string, value json.RawMessage) error {
+		return applyGroupValue(attrs, kind, path, value)
+	})
+}
+
+// JoinName returns the account name of a SCIM name with no formatted value.
+func JoinName(givenName, familyName string) string {
+	return strings.TrimSpace(strings.TrimSpace(givenName) + " " + strings.TrimSpace(familyName))
+}
+
+// applyPatch calls apply for each attribute of ops. An operation without a
+// path carries an object whose keys are the paths.
+func applyPatch(ops []PatchOperation, apply func(kind patchKind, path string, value json.RawMessage) error) error {
+	for _, op := range ops {
+		var kind patchKind
+		switch strings.ToLower(op.Op) {
+		case "add":
+			kind = patchAdd
+		case "replace":
+			kind = patchReplace
+		case "remove":
+			kind = patchRemove
+		default:
+			return fmt.Errorf("%w: unknown op %q", ErrInvalidValue, op.Op)
+		}
+
+		if op.Path != "" {
+			if err := apply(kind, attributeName(op.Path), op.Value); err != nil {
+				return err
+			}
+			continue
+		}
+
+		var values map[string]json.RawMessage
+		if kind == patchRemove || json.Unmarshal(op.Value, &values) != nil {
+			return fmt.Errorf("%w: %s requires a path", ErrInvalidPath, op.Op)
+		}
+		for path, value := range values {
+			if err := apply(kind, attributeName(path), value); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func applyUserValue(attrs *UserAttributes, name *nameParts, kind patchKind, path string, value json.RawMessage) error {
+	switch path {
+	case "active":
+		if kind == patchRemove {
+			return fmt.Errorf("%w: active cannot be removed", ErrInvalidValue)
+		}
+		active, err := boolValue(value)
+		if err != nil {
+			return fmt.Errorf("%w: active", ErrInvalidValue)
+		}
+		attrs.Active = active
+	case "username":
+		if kind == patchRemove {
+			return fmt.Errorf("%w: userName cannot be removed", ErrInvalidValue)
+		}
+		userName, err := stringValue(value)
+		if err != nil {
+			return fmt.Errorf("%w: userName", ErrInvalidValue)
+		}
+		attrs.UserName = userName
+	case "externalid":
+		externalID, err := optionalString(kind, value)
+		if err != nil {
+			return fmt.Errorf("%w: externalId", ErrInvalidValue)
+		}
+		attrs.ExternalID = externalID
+	case "displayname", "name.formatted":
+		return setNamePart(&name.formatted, kind, value)
+	case "name.givenname":
+		return setNamePart(&name.given, kind, value)
+	case "name.familyname":
+		return setNamePart(&name.family, kind, value)
+	case "name":
+		if kind == patchRemove {
+			return nil
+		}
+		var parts struct {
+			Formatted  *string `json:"formatted"`
+			GivenName  *string `json:"givenName"`
+			FamilyName *string `json:"familyName"`
+		}
+		if err := json.Unmarshal(value, &parts); err != nil {
+			return fmt.Errorf("%w: name", ErrInvalidValue)
+		}
+		if parts.Formatted != nil {
+			name.formatted = parts.Formatted
+		}
+		if parts.GivenName != nil {
+			name.given = parts.GivenName
+		}
+		if parts.FamilyName != nil {
+			name.family = parts.FamilyName
+		}
+	}
+	return nil
+}
+
+func setNamePart(part **string, kind patchKind, value json.RawMessage) error {
+	if kind == patchRemove {
+		*part = new(string)
+		return nil
+	}
+	s, err := stringValue(value)
+	if err != nil {
+		return fmt.Errorf("%w: name", ErrInvalidValue)
+	}
+	*part = &s
+	return nil
+}
+
+func applyGroupValue(attrs *GroupAttributes, kind patchKind, path string, value json.RawMessage) error {
+	if match := memberFilterPath.FindStringSubmatch(path); match != nil {
+		if kind != patchRemove {
+			return fmt.Errorf("%w: %s only supports remove", ErrInvalidPath, path)
+		}
+		filter, err := ParseFilter(match[1], "value")
+		if err != nil || filter == nil {
+			return fmt.Errorf("%w: %s", ErrInvalidPath, path)
+		}
+		memberID, err := uuid.Parse(filter.Value)
+		if err != nil {
+			return fmt.Errorf("%w: member %s", ErrInvalidValue, filter.Value)
+		}
+		attrs.Members = slices.DeleteFunc(attrs.Members, func(id uuid.UUID) bool { return id == memberID })
+		return nil
+	}
+
+	switch path {
+	case "displayname":
+		if kind == patchRemove {
+			return fmt.Errorf("%w: displayName cannot be removed", ErrInvalidValue)
+		}
+		displayName, err := stringValue(value)
+		if err != nil {
+			return fmt.Errorf("%w: displayName", ErrInvalidValue)
+		}
+		attrs.DisplayName = displayName
+	case "externalid":
+		externalID, err := optionalString(kind, value)
+		if err != nil {
+			return fmt.Errorf("%w: externalId", ErrInvalidValue)
+		}
+		attrs.ExternalID = externalID
+	case "members":
+		if kind == patchRemove && len(value) == 0 {
+			attrs.Members = nil
+			return nil
+		}
+		memberIDs, err := MemberIDs(value)
+		if err != nil {
+			return err
+		}
+		switch kind {
+		case patchAdd:
+			for _, id := range memberIDs {
+				if !slices.Contains(attrs.Members, id) {
+					attrs.Members = append(attrs.Members, id)
+				}
+			}
+		case patchReplace:
+			attrs.Members = memberIDs
+		case patchRemove:
+			attrs.Members = slices.DeleteFunc(attrs.Members, func(id uuid.UUID) bool { return slices.Contains(memberIDs, id) })
+		}
+	default:
+		return fmt.Errorf("%w: %s", ErrInvalidPath, path)
+	}
+	return nil
+}
+
+// MemberIDs decodes a members value, an array of {"value": "<user id>"}.
+func MemberIDs(value json.RawMessage) ([]uuid.UUID, error) {
+	var members []struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(value, &members); err != nil {
+		return nil, fmt.Errorf("%w: members", ErrInvalidValue)
+	}
+
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, m := range members {
+		id, err := uuid.Parse(m.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: member %s", ErrInvalidValue, m.Value)
+		}
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// boolValue also accepts "True" and "False" strings, which Azure AD sends.
+func boolValue(value json.RawMessage) (bool, error) {
+	var b bool
+	if err := json.Unmarshal(value, &b); err == nil {
+		return b, nil
+	}
+	s, err := stringValue(value)
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(s) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	return false, ErrInvalidValue
+}
+
+func stringValue(value json.RawMessage) (string, error) {
+	var s string
+	if err := json.Unmarshal(value, &s); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(s), nil
+}
+
+// optionalString returns nil for removed and empty values.
+func optionalString(kind patchKind, value json.RawMessage) (*string, error) {
+	if kind == patchRemove || string(value) == "null" {
+		return nil, nil
+	}
+	s, err := stringValue(value)
+	if err != nil || s == "" {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
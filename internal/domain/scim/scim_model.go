@@ -0,0 +1,169 @@
+package scim
+
+import (
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+)
+
+// PermManage allows managing the SCIM tokens of the organization and the
+// roles granted to its SCIM groups.
+const PermManage = "scim:manage"
+
+// Schemas of the core resources and messages.
+const (
+	UserSchema          = "urn:ietf:params:scim:schemas:core:2.0:User"
+	GroupSchema         = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	ListResponseSchema  = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	PatchOpSchema       = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	ErrorSchema         = "urn:ietf:params:scim:api:messages:2.0:Error"
+	ServiceConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	MediaType           = "application/scim+json"
+)
+
+// TokenPrefix starts every SCIM token, so leaked tokens are easy to spot.
+const TokenPrefix = "scim_"
+
+// Page sizes of the listings. Count is capped at MaxPageSize.
+const (
+	DefaultPageSize = 100
+	MaxPageSize     = 200
+)
+
+// Token authenticates the provisioning requests of an identity provider to a
+// single organization. Only the SHA-256 of the token is stored; Prefix is kept
+// so admins can tell tokens apart.
+type Token struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primaryKey" json:"id"`
+	OrganizationID uuid.UUID  `gorm:"type:uuid" json:"organization_id"`
+	Name           string     `json:"name"`
+	Prefix         string     `json:"prefix"`
+	TokenHash      string     `json:"-"`
+	CreatedBy      *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+}
+
+func (Token) TableName() string {
+	return "scim_tokens"
+}
+
+// UserLink marks a user as managed by the identity provider and keeps the
+// externalId the provider gave it.
+type UserLink struct {
+	UserID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrganizationID uuid.UUID `gorm:"type:uuid"`
+	ExternalID     *string
+	CreatedAt      time.Time
+}
+
+func (UserLink) TableName() string {
+	return "scim_users"
+}
+
+// User is an account of the organization as seen by the identity provider.
+type User struct {
+	user.User
+	ExternalID *string
+	Groups     []Member `gorm:"-"`
+}
+
+func (User) TableName() string {
+	return "users"
+}
+
+// Active reports whether the account can sign in. Accounts suspended or
+// banned by an admin stay inactive whatever the identity provider says.
+func (u *User) Active() bool {
+	return u.Status == user.StatusActive
+}
+
+// Deprovisioned reports whether the identity provider deactivated the user.
+func (u *User) Deprovisioned() bool {
+	return u.Status == user.StatusInactive && u.StatusSource == user.SourceProvisioning
+}
+
+// UserAttributes are the attributes of a user the identity provider manages.
+// UserName is the e-mail the user signs in with.
+type UserAttributes struct {
+	UserName   string
+	ExternalID *string
+	Name       string
+	Active     bool
+}
+
+// Group is a group of the identity provider. Its members receive Roles, the
+// names of the roles an admin mapped to it.
+type Group struct {
+	ID             uuid.UUID `gorm:"type:uuid;primaryKey"`
+	OrganizationID uuid.UUID `gorm:"type:uuid"`
+	DisplayName    string
+	ExternalID     *string
+	CreatedAt      time.Time
+	UpdatedAt      *time.Time
+	Members        []Member `gorm:"-"`
+	Roles          []string `gorm:"-"`
+}
+
+func (Group) TableName() string {
+	return "scim_groups"
+}
+
+// Revision returns updated_at, or created_at for groups never updated.
+func (g *Group) Revision() time.Time {
+	if g.UpdatedAt != nil {
+		return *g.UpdatedAt
+	}
+	return g.CreatedAt
+}
+
+// MemberIDs returns the IDs of the members.
+func (g *Group) MemberIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(g.Members))
+	for _, m := range g.Members {
+		ids = append(ids, m.ID)
+	}
+	return ids
+}
+
+// Member references a user in a group, or a group of a user.
+type Member struct {
+	ID      uuid.UUID
+	Display string
+}
+
+// GroupAttributes are the attributes of a group the identity provider
+// manages.
+type GroupAttributes struct {
+	DisplayName string
+	ExternalID  *string
+	Members     []uuid.UUID
+}
+
+// Query selects a page of a listing. StartIndex is 1-based, as in SCIM.
+type Query struct {
+	Filter     *Filter
+	StartIndex int
+	Count      int
+}
+
+// Offset returns the number of rows to skip.
+func (q Query) Offset() int {
+	if q.StartIndex < 1 {
+		return 0
+	}
+	return q.StartIndex - 1
+}
+
+// Limit returns the page size, capped at MaxPageSize. A count of zero, or a
+// negative one, only returns the total.
+func (q Query) Limit() int {
+	switch {
+	case q.Count < 0:
+		return 0
+	case q.Count > MaxPageSize:
+		return MaxPageSize
+	}
+	return q.Count
+}
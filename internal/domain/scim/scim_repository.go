@@ -0,0 +1,42 @@
+package scim
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IRepository interface {
+	CreateToken(ctx context.Context, token *Token) error
+	ListTokens(ctx context.Context) ([]Token, error)
+	DeleteToken(ctx context.Context, id uuid.UUID) error
+	// FindTokenByHash looks the token up across organizations and returns
+	// ErrInvalidToken when no token has the hash.
+	FindTokenByHash(ctx context.Context, tokenHash string) (*Token, error)
+	// TouchToken records the use of the token, at most once a minute.
+	TouchToken(ctx context.Context, id uuid.UUID) error
+
+	// FindUser returns the user with its groups. Users scheduled for deletion
+	// are not found.
+	FindUser(ctx context.Context, id uuid.UUID) (*User, error)
+	ListUsers(ctx context.Context, q Query) ([]User, int64, error)
+	// SaveUserLink creates or updates the link of the user.
+	SaveUserLink(ctx context.Context, link *UserLink) error
+	IsLinked(ctx context.Context, userID uuid.UUID) (bool, error)
+	// ExistingUsers returns the IDs of ids that are users of the organization.
+	ExistingUsers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error)
+
+	CreateGroup(ctx context.Context, group *Group) error
+	// FindGroup returns the group with its members and roles.
+	FindGroup(ctx context.Context, id uuid.UUID) (*Group, error)
+	ListGroups(ctx context.Context, q Query) ([]Group, int64, error)
+	UpdateGroup(ctx context.Context, group *Group) error
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+	AddMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error
+	RemoveMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error
+	SetGroupRoles(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) error
+	// UserGroups returns the groups of the user with their roles.
+	UserGroups(ctx context.Context, userID uuid.UUID) ([]Group, error)
+	// HasRoleMappings reports whether any group of the organization has roles.
+	HasRoleMappings(ctx context.Context) (bool, error)
+}
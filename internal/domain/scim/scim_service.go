@@ -0,0 +1,37 @@
+package scim
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// IService provisions the users and groups of an organization from its
+// identity provider. Deactivating or deleting a user revokes its sessions.
+type IService interface {
+	// CreateToken returns the token and its only plain copy.
+	CreateToken(ctx context.Context, name string, createdBy uuid.UUID) (*Token, string, error)
+	ListTokens(ctx context.Context) ([]Token, error)
+	RevokeToken(ctx context.Context, id uuid.UUID) error
+	// Authenticate returns the token of rawToken, from any organization; the
+	// caller scopes the request to the organization of the token.
+	Authenticate(ctx context.Context, rawToken string) (*Token, error)
+
+	ListUsers(ctx context.Context, q Query) ([]User, int64, error)
+	GetUser(ctx context.Context, id uuid.UUID) (*User, error)
+	CreateUser(ctx context.Context, attrs UserAttributes) (*User, error)
+	ReplaceUser(ctx context.Context, id uuid.UUID, attrs UserAttributes) (*User, error)
+	PatchUser(ctx context.Context, id uuid.UUID, ops []PatchOperation) (*User, error)
+	// DeleteUser deactivates the user and schedules its deletion after the
+	// grace period. Creating the user again within the period restores it.
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+
+	ListGroups(ctx context.Context, q Query) ([]Group, int64, error)
+	GetGroup(ctx context.Context, id uuid.UUID) (*Group, error)
+	CreateGroup(ctx context.Context, attrs GroupAttributes) (*Group, error)
+	ReplaceGroup(ctx context.Context, id uuid.UUID, attrs GroupAttributes) (*Group, error)
+	PatchGroup(ctx context.Context, id uuid.UUID, ops []PatchOperation) (*Group, error)
+	DeleteGroup(ctx context.Context, id uuid.UUID) error
+	// SetGroupRoles replaces the roles granted to the members of the group.
+	SetGroupRoles(ctx context.Context, id uuid.UUID, roleNames []string) (*Group, error)
+}
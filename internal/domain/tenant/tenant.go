@@ -0,0 +1,26 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DefaultOrganizationID is the organization seeded by the organizations
+// migration; requests that do not name an organization are scoped to it.
+var DefaultOrganizationID = uuid.MustParse("00000000-0000-0000-0000-000000000001")
+
+type ctxKey struct{}
+
+func WithOrganization(ctx context.Context, organizationID uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxKey{}, organizationID)
+}
+
+// OrganizationID returns the organization the context is scoped to, falling
+// back to the default organization.
+func OrganizationID(ctx context.Context) uuid.UUID {
+	if id, ok := ctx.Value(ctxKey{}).(uuid.UUID); ok && id != uuid.Nil {
+		return id
+	}
+	return DefaultOrganizationID
+}
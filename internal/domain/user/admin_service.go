@@ -0,0 +1,21 @@
+package user
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type IAdminService interface {
+	ListUsers(ctx context.Context, filter ListFilter) (*Page, error)
+	GetUserDetails(ctx context.Context, userID uuid.UUID) (*Details, error)
+	// ForceLogout revokes every access and refresh token of the user.
+	ForceLogout(ctx context.Context, userID uuid.UUID) error
+	// SendPasswordReset mails a password reset link to the user, optionally
+	// revoking the current sessions.
+	SendPasswordReset(ctx context.Context, userID uuid.UUID, revokeSessions bool) error
+	// Unlock lifts a lockout caused by failed logins.
+	Unlock(ctx context.Context, userID uuid.UUID) error
+	// StatusHistory lists the status changes of the user, newest first.
+	StatusHistory(ctx context.Context, userID uuid.UUID) ([]StatusHistoryEntry, error)
+}
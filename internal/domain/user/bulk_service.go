@@ -0,0 +1,73 @@
+package user
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type BulkFormat string
+
+const (
+	FormatCSV   BulkFormat = "csv"
+	FormatJSONL BulkFormat = "jsonl"
+)
+
+// ImportRecord is one row of an import file. Role is the organization role
+// (org_admin or org_member) and PasswordHash an optional bcrypt or argon2id
+// hash; users imported without one set their password through the reset flow.
+type ImportRecord struct {
+	Name         string `json:"name"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	PasswordHash string `json:"password_hash"`
+}
+
+type ImportOptions struct {
+	Format    BulkFormat
+	DryRun    bool
+	BatchSize int
+}
+
+type ImportRowError struct {
+	Line  int    `json:"line"`
+	Email string `json:"email,omitempty"`
+	Error string `json:"error"`
+}
+
+// ImportReport summarizes an import. In dry-run mode Imported counts the rows
+// that would have been imported.
+type ImportReport struct {
+	DryRun   bool             `json:"dry_run"`
+	Total    int              `json:"total"`
+	Imported int              `json:"imported"`
+	Failed   int              `json:"failed"`
+	Errors   []ImportRowError `json:"errors"`
+}
+
+// ImportedUser is a validated row ready to be persisted with its
+// organization membership.
+type ImportedUser struct {
+	User             *User
+	OrganizationRole string
+}
+
+// ExportRecord is one exported user; PasswordHash is only filled when
+// explicitly requested.
+type ExportRecord struct {
+	ID           uuid.UUID  `json:"id"`
+	Name         string     `json:"name"`
+	Email        string     `json:"email"`
+	Role         string     `json:"role" gorm:"column:organization_role"`
+	Status       string     `json:"status"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	PasswordHash string     `json:"password_hash,omitempty"`
+}
+
+type IBulkService interface {
+	Import(ctx context.Context, r io.Reader, opts ImportOptions) (*ImportReport, error)
+	Export(ctx context.Context, w io.Writer, format BulkFormat, includePasswordHash bool) error
+}
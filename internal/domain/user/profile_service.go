@@ -0,0 +1,25 @@
+package user
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ProfileUpdate lists the self-editable fields; nil fields are left unchanged.
+// Metadata keys set to nil are removed from the stored metadata.
+type ProfileUpdate struct {
+	Name     *string
+	Locale   *string
+	Timezone *string
+	Metadata Metadata
+}
+
+type IProfileService interface {
+	GetProfile(ctx context.Context, userID uuid.UUID) (*User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, update ProfileUpdate, expectedRevision *time.Time) (*User, error)
+	RequestEmailChange(ctx context.Context, userID uuid.UUID, currentPassword string, newEmail string) error
+	ConfirmEmailChange(ctx context.Context, confirmToken string) error
+	RevertEmailChange(ctx context.Context, revertToken string) error
+}
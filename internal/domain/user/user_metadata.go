@@ -0,0 +1,37 @@
+package user
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+)
+
+// Metadata holds free-form profile attributes stored as JSONB.
+type Metadata map[string]interface{}
+
+func (m Metadata) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (m *Metadata) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*m = Metadata{}
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return errors.New("unsupported metadata type")
+	}
+	return json.Unmarshal(raw, m)
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/felipedenardo/chameleon-common/pkg/base"
+	"github.com/google/uuid"
 )
 
 type Role string
@@ -18,6 +19,15 @@ type Status string
 const (
 	StatusActive   Status = "active"
 	StatusInactive Status = "inactive"
+	StatusInvited  Status = "invited"
+)
+
+// Statuses that keep the account from signing in. A suspension ends at
+// SuspendedUntil; a ban is permanent and carries a reason.
+const (
+	StatusSuspended           Status = "suspended"
+	StatusBanned              Status = "banned"
+	StatusPendingVerification Status = "pending_verification"
 )
 
 type User struct {
@@ -29,4 +39,52 @@ type User struct {
 	Status       Status     `json:"status"`
 	LastLoginAt  *time.Time `gorm:"column:last_login_at" json:"last_login_at,omitempty"`
 	TokenVersion int        `gorm:"column:token_version;default:0" json:"-"`
+
+	OrganizationID uuid.UUID `gorm:"column:organization_id;type:uuid" json:"organization_id"`
+
+	PasswordChangedAt  *time.Time `gorm:"column:password_changed_at;default:CURRENT_TIMESTAMP" json:"password_changed_at,omitempty"`
+	MustChangePassword bool       `gorm:"column:must_change_password;default:false" json:"must_change_password"`
+
+	Locale   *string  `gorm:"column:locale" json:"locale,omitempty"`
+	Timezone *string  `gorm:"column:timezone" json:"timezone,omitempty"`
+	Metadata Metadata `gorm:"column:metadata;type:jsonb" json:"metadata"`
+
+	SuspendedUntil *time.Time   `gorm:"column:suspended_until" json:"suspended_until,omitempty"`
+	StatusReason   *string      `gorm:"column:status_reason" json:"-"`
+	StatusSource   StatusSource `gorm:"column:status_source;default:system" json:"-"`
+
+	DeletionScheduledFor *time.Time `gorm:"column:deletion_scheduled_for" json:"deletion_scheduled_for,omitempty"`
+
+	EmailOTPEnabled bool `gorm:"column:email_otp_enabled;default:false" json:"email_otp_enabled"`
+}
+
+// Revision returns the optimistic concurrency marker of the row, used as the
+// profile ETag: updated_at, or created_at for rows never updated.
+func (u *User) Revision() time.Time {
+	if u.UpdatedAt != nil {
+		return *u.UpdatedAt
+	}
+	return u.CreatedAt
+}
+
+// PasswordExpired reports whether the password is older than maxAge.
+// A non-positive maxAge disables expiration. Accounts without a local
+// password (federated, directory or provisioned) never expire.
+func (u *User) PasswordExpired(maxAge time.Duration, now time.Time) bool {
+	if maxAge <= 0 || u.PasswordHash == "" || u.PasswordChangedAt == nil {
+		return false
+	}
+	return now.Sub(*u.PasswordChangedAt) > maxAge
+}
+
+// SelfDeactivated reports whether the user deactivated the account and may
+// reactivate it by e-mail.
+func (u *User) SelfDeactivated() bool {
+	return u.Status == StatusInactive && u.StatusSource == SourceSelf
+}
+
+// SuspensionExpired reports whether the user is suspended and the suspension
+// already ended.
+func (u *User) SuspensionExpired(now time.Time) bool {
+	return u.Status == StatusSuspended && u.SuspendedUntil != nil && !now.Before(*u.SuspendedUntil)
 }
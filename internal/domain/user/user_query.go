@@ -0,0 +1,99 @@
+package user
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SortField string
+
+const (
+	SortByCreatedAt   SortField = "created_at"
+	SortByLastLoginAt SortField = "last_login_at"
+	SortByName        SortField = "name"
+	SortByEmail       SortField = "email"
+)
+
+// ListFilter drives the admin user listing. Results are ordered by SortBy and
+// then by id, and paginated with a keyset cursor over that same pair. Roles
+// are RBAC role names; a user matches holding any of them.
+type ListFilter struct {
+	Statuses      []Status
+	Roles         []string
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	LastLoginFrom *time.Time
+	LastLoginTo   *time.Time
+	Search        string
+	SortBy        SortField
+	Descending    bool
+	Limit         int
+	After         *Cursor
+}
+
+// Cursor points at the last row of a page: the sort key value (RFC 3339 for
+// timestamps) and the row id used as tie-breaker.
+type Cursor struct {
+	SortBy     SortField `json:"s"`
+	Descending bool      `json:"d,omitempty"`
+	Value      string    `json:"v"`
+	ID         uuid.UUID `json:"id"`
+}
+
+type Page struct {
+	Users      []User
+	NextCursor string
+}
+
+// Details is the admin view of a single user, with session and lockout state
+// read from the cache.
+type Details struct {
+	User                *User
+	SessionCount        int64
+	Locked              bool
+	LockedUntil         *time.Time
+	FailedLoginAttempts int
+}
+
+func (c Cursor) Encode() string {
+	payload, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func DecodeCursor(value string) (*Cursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(payload, &c); err != nil {
+		return nil, err
+	}
+	if c.ID == uuid.Nil {
+		return nil, errors.New("cursor without id")
+	}
+	return &c, nil
+}
+
+// SortValue returns the cursor value of u for the given sort field.
+func (u *User) SortValue(field SortField) string {
+	switch field {
+	case SortByLastLoginAt:
+		if u.LastLoginAt == nil {
+			return time.Unix(0, 0).UTC().Format(time.RFC3339Nano)
+		}
+		return u.LastLoginAt.UTC().Format(time.RFC3339Nano)
+	case SortByName:
+		return strings.ToLower(u.Name)
+	case SortByEmail:
+		return u.Email
+	default:
+		return u.CreatedAt.UTC().Format(time.RFC3339Nano)
+	}
+}
@@ -2,6 +2,7 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -10,9 +11,27 @@ type IRepository interface {
 	Create(ctx context.Context, user *User) error
 	FindByEmail(ctx context.Context, email string) (*User, error)
 	FindByID(ctx context.Context, id uuid.UUID) (*User, error)
+	List(ctx context.Context, filter ListFilter) ([]User, error)
 	UpdatePasswordHash(ctx context.Context, userID uuid.UUID, newHash string) error
 	UpdateLastLoginAt(ctx context.Context, userID uuid.UUID) error
-	UpdateStatus(ctx context.Context, userID uuid.UUID, status string) error
+	ChangeStatus(ctx context.Context, userID uuid.UUID, change StatusChange) error
+	ListStatusHistory(ctx context.Context, userID uuid.UUID) ([]StatusHistoryEntry, error)
+	LiftExpiredSuspensions(ctx context.Context, now time.Time) (int64, error)
+	ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) error
+	CancelDeletion(ctx context.Context, userID uuid.UUID) error
+	// FindDueDeletions returns, across organizations, up to limit users whose
+	// deletion is due. Only ID and OrganizationID are loaded.
+	FindDueDeletions(ctx context.Context, now time.Time, limit int) ([]User, error)
+	// Anonymize replaces the personal data of the user, removes the rows that
+	// only exist for the user and soft-deletes it.
+	Anonymize(ctx context.Context, userID uuid.UUID) error
+	SetMustChangePassword(ctx context.Context, userID uuid.UUID, mustChange bool) error
+	SetEmailOTPEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error
+	UpdateProfile(ctx context.Context, u *User, expectedRevision *time.Time) error
+	UpdateEmail(ctx context.Context, userID uuid.UUID, email string) error
 	IncrementTokenVersion(ctx context.Context, userID uuid.UUID) error
 	GetUserTokenVersion(ctx context.Context, userID string) (int, error)
+	FindExistingEmails(ctx context.Context, emails []string) (map[string]struct{}, error)
+	ImportBatch(ctx context.Context, users []ImportedUser) error
+	StreamExport(ctx context.Context, includePasswordHash bool, fn func(ExportRecord) error) error
 }
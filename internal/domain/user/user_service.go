@@ -2,19 +2,103 @@ package user
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// LoginResult carries the tokens issued by a successful authentication.
+// When PasswordChangeRequired is set, AccessToken is a restricted token only
+// accepted by the change-password endpoint and no refresh token is issued.
+type LoginResult struct {
+	AccessToken            string
+	RefreshToken           string
+	User                   *User
+	PasswordChangeRequired bool
+	// DeletionCancelled tells that the login cancelled a scheduled deletion.
+	DeletionCancelled bool
+	// OTPRequired tells that no token was issued yet: the login must be
+	// completed with the code mailed for OTPChallenge.
+	OTPRequired  bool
+	OTPChallenge string
+}
+
+// Reauthentication proves the identity of a signed-in user again. The OTP
+// fields take a step-up challenge, raising the acr of the new tokens to aal2.
+// AccessToken is revoked and RefreshToken, when given, consumed.
+type Reauthentication struct {
+	Password     string
+	OTPChallenge string
+	OTPCode      string
+	AccessToken  string
+	RefreshToken string
+}
+
+// ImpersonationResult is a non-refreshable access token of User issued to
+// ActorID, which the token names in its act claim.
+type ImpersonationResult struct {
+	AccessToken string
+	ExpiresIn   time.Duration
+	User        *User
+	ActorID     uuid.UUID
+}
+
+// DeviceAuthorization is a pending device authorization grant (RFC 8628):
+// the device polls the token endpoint with DeviceCode while the user approves
+// UserCode at VerificationURI.
+type DeviceAuthorization struct {
+	DeviceCode      string
+	UserCode        string
+	VerificationURI string
+	ExpiresIn       time.Duration
+	Interval        time.Duration
+}
+
+// DeviceRequest describes a pending device authorization to the user asked
+// to approve it.
+type DeviceRequest struct {
+	ClientID  string
+	UserCode  string
+	ExpiresAt time.Time
+}
+
 type IService interface {
-	Register(ctx context.Context, name, email, password string) (*User, error)
-	Login(ctx context.Context, email, password string) (string, string, *User, error)
+	Register(ctx context.Context, organizationSlug, name, email, password string) (*User, error)
+	Login(ctx context.Context, organizationSlug, email, password string) (*LoginResult, error)
 	Refresh(ctx context.Context, refreshToken string) (string, string, *User, error)
 	ChangePassword(ctx context.Context, userID uuid.UUID, currentPassword string, newPassword string, tokenString string) error
 	Logout(ctx context.Context, tokenString string, refreshToken string) error
 	LogoutAll(ctx context.Context, userID uuid.UUID, tokenString string) error
-	ForgotPassword(ctx context.Context, email string) (string, error)
+	ForgotPassword(ctx context.Context, organizationSlug, email string) (string, error)
 	ResetPassword(ctx context.Context, resetToken string, newPassword string) error
 	DeactivateSelf(ctx context.Context, userID uuid.UUID, password, tokenString string) error
-	UpdateUserStatus(ctx context.Context, userID uuid.UUID, status Status) error
+	// RequestDeletion schedules the anonymization of the account after the
+	// grace period and ends every session. Logging in again cancels it.
+	RequestDeletion(ctx context.Context, userID uuid.UUID, password string) (time.Time, error)
+	// RequestReactivation mails a single-use reactivation link when the
+	// account exists and was deactivated by the user. It reports nothing else.
+	RequestReactivation(ctx context.Context, organizationSlug, email string) error
+	Reactivate(ctx context.Context, reactivationToken string) error
+	// RequestMagicLink mails a single-use login link when the account exists
+	// and returns the nonce the same browser must present with the link.
+	RequestMagicLink(ctx context.Context, organizationSlug, email string) (nonce string, err error)
+	LoginWithMagicLink(ctx context.Context, token, nonce string) (*LoginResult, error)
+	LoginWithEmailOTP(ctx context.Context, challengeID, code string) (*LoginResult, error)
+	// StartStepUp mails a one-time code to the user and returns the ID of the
+	// challenge that VerifyStepUp exchanges for a step-up token.
+	StartStepUp(ctx context.Context, userID uuid.UUID) (challengeID string, err error)
+	VerifyStepUp(ctx context.Context, userID uuid.UUID, challengeID, code string) (stepUpToken string, err error)
+	RequireStepUp(ctx context.Context, userID uuid.UUID, stepUpToken string) error
+	EnableEmailOTP(ctx context.Context, userID uuid.UUID, stepUpToken string) error
+	DisableEmailOTP(ctx context.Context, userID uuid.UUID) error
+	Reauthenticate(ctx context.Context, userID uuid.UUID, req Reauthentication) (*LoginResult, error)
+	UpdateUserStatus(ctx context.Context, userID uuid.UUID, change StatusChange) error
+	ForcePasswordChange(ctx context.Context, userID uuid.UUID) error
+	Impersonate(ctx context.Context, actorToken string, targetID uuid.UUID) (*ImpersonationResult, error)
+	StartDeviceAuthorization(ctx context.Context, clientID string) (*DeviceAuthorization, error)
+	FindDeviceRequest(ctx context.Context, userCode string) (*DeviceRequest, error)
+	// DecideDeviceAuthorization approves or denies the device with the
+	// session of tokenString; the device gets tokens of that user.
+	DecideDeviceAuthorization(ctx context.Context, userID uuid.UUID, tokenString string, userCode string, approve bool) error
+	PollDeviceAuthorization(ctx context.Context, clientID string, deviceCode string) (*LoginResult, error)
 }
@@ -0,0 +1,52 @@
+package user
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StatusSource tells who made the last status change. Only accounts the user
+// deactivated themselves can be reactivated by e-mail, and only accounts the
+// identity provider deactivated can be reactivated by it.
+type StatusSource string
+
+const (
+	SourceSelf         StatusSource = "self"
+	SourceAdmin        StatusSource = "admin"
+	SourceSystem       StatusSource = "system"
+	SourceProvisioning StatusSource = "provisioning"
+)
+
+// StatusChange is a transition of the account status. SuspendedUntil only
+// applies to suspensions and is required for them; Reason is required for
+// bans. ChangedBy is nil for changes made by the system.
+type StatusChange struct {
+	Status         Status
+	Source         StatusSource
+	Reason         string
+	SuspendedUntil *time.Time
+	ChangedBy      *uuid.UUID
+}
+
+// StatusHistoryEntry is a row of the status change history of a user.
+type StatusHistoryEntry struct {
+	ID             uuid.UUID    `gorm:"type:uuid;primaryKey" json:"id"`
+	OrganizationID uuid.UUID    `gorm:"type:uuid" json:"organization_id"`
+	UserID         uuid.UUID    `gorm:"type:uuid" json:"user_id"`
+	FromStatus     Status       `json:"from_status"`
+	ToStatus       Status       `json:"to_status"`
+	Source         StatusSource `json:"source"`
+	Reason         string       `json:"reason"`
+	SuspendedUntil *time.Time   `json:"suspended_until"`
+	ChangedBy      *uuid.UUID   `gorm:"type:uuid" json:"changed_by"`
+	CreatedAt      time.Time    `json:"created_at"`
+}
+
+func (StatusHistoryEntry) TableName() string {
+	return "user_status_history"
+}
+
+// SuspensionExpiredReason is the history reason of suspensions lifted once
+// their end date passed.
+const SuspensionExpiredReason = "suspension expired"
@@ -0,0 +1,29 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261000DDLAddPasswordExpiry = gormigrate.Migration{
+	ID: "181020261000",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE users
+			  ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			  ADD COLUMN IF NOT EXISTS must_change_password BOOLEAN NOT NULL DEFAULT FALSE;
+
+			UPDATE users SET password_changed_at = COALESCE(updated_at, created_at);
+
+			COMMENT ON COLUMN users.password_changed_at IS 'Data da última troca de senha (expiração de senha).';
+			COMMENT ON COLUMN users.must_change_password IS 'Troca de senha obrigatória no próximo login (forçada por admin).';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE users
+			  DROP COLUMN IF EXISTS must_change_password,
+			  DROP COLUMN IF EXISTS password_changed_at;
+		`).Error
+	},
+}
@@ -0,0 +1,28 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261010DDLAddUserProfile = gormigrate.Migration{
+	ID: "181020261010",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE users
+			  ADD COLUMN IF NOT EXISTS locale VARCHAR(35),
+			  ADD COLUMN IF NOT EXISTS timezone VARCHAR(64),
+			  ADD COLUMN IF NOT EXISTS metadata JSONB NOT NULL DEFAULT '{}'::jsonb;
+
+			COMMENT ON COLUMN users.metadata IS 'Metadados livres do perfil, editáveis pelo próprio usuário.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE users
+			  DROP COLUMN IF EXISTS metadata,
+			  DROP COLUMN IF EXISTS timezone,
+			  DROP COLUMN IF EXISTS locale;
+		`).Error
+	},
+}
@@ -0,0 +1,72 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261020DDLCreateRBAC = gormigrate.Migration{
+	ID: "181020261020",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE TABLE roles (
+			   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			   name VARCHAR(50) NOT NULL UNIQUE,
+			   description VARCHAR(255),
+			   is_system BOOLEAN NOT NULL DEFAULT FALSE,
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   updated_at TIMESTAMP
+			);
+
+			CREATE TABLE permissions (
+			   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			   name VARCHAR(100) NOT NULL UNIQUE,
+			   description VARCHAR(255),
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE TABLE role_permissions (
+			   role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+			   permission_id UUID NOT NULL REFERENCES permissions(id) ON DELETE CASCADE,
+			   PRIMARY KEY (role_id, permission_id)
+			);
+
+			CREATE TABLE user_roles (
+			   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			   role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   PRIMARY KEY (user_id, role_id)
+			);
+
+			CREATE INDEX idx_user_roles_role_id ON user_roles(role_id);
+
+			INSERT INTO permissions (name, description) VALUES
+			   ('users:read', 'Consultar usuários'),
+			   ('users:write', 'Alterar status e credenciais de usuários'),
+			   ('roles:read', 'Consultar papéis e permissões'),
+			   ('roles:write', 'Gerenciar papéis e atribuições');
+
+			INSERT INTO roles (name, description, is_system) VALUES
+			   ('admin', 'Administrador do sistema', TRUE),
+			   ('user', 'Usuário padrão', TRUE);
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r CROSS JOIN permissions p WHERE r.name = 'admin';
+
+			INSERT INTO user_roles (user_id, role_id)
+			SELECT u.id, r.id FROM users u JOIN roles r ON r.name = u.role;
+
+			COMMENT ON TABLE roles IS 'Papéis atribuíveis aos usuários (RBAC).';
+			COMMENT ON COLUMN roles.is_system IS 'Papéis de sistema não podem ser removidos.';
+			COMMENT ON COLUMN users.role IS 'Papel principal (legado), mantido em sincronia com user_roles.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DROP TABLE IF EXISTS user_roles;
+			DROP TABLE IF EXISTS role_permissions;
+			DROP TABLE IF EXISTS permissions;
+			DROP TABLE IF EXISTS roles;
+		`).Error
+	},
+}
@@ -0,0 +1,84 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261030DDLCreateOrganizations = gormigrate.Migration{
+	ID: "181020261030",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE TABLE organizations (
+			   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			   name VARCHAR(255) NOT NULL,
+			   slug VARCHAR(63) NOT NULL UNIQUE,
+			   status VARCHAR(20) NOT NULL DEFAULT 'active',
+			   allow_self_signup BOOLEAN NOT NULL DEFAULT FALSE,
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   updated_at TIMESTAMP
+			);
+
+			INSERT INTO organizations (id, name, slug, allow_self_signup)
+			VALUES ('00000000-0000-0000-0000-000000000001', 'Default', 'default', TRUE);
+
+			ALTER TABLE users ADD COLUMN organization_id UUID REFERENCES organizations(id);
+			UPDATE users SET organization_id = '00000000-0000-0000-0000-000000000001';
+			ALTER TABLE users ALTER COLUMN organization_id SET NOT NULL;
+
+			ALTER TABLE users DROP CONSTRAINT IF EXISTS users_email_key;
+			DROP INDEX IF EXISTS idx_users_email_lower;
+			CREATE UNIQUE INDEX idx_users_org_email_lower ON users (organization_id, lower(email));
+
+			CREATE TABLE organization_members (
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			   role_id UUID NOT NULL REFERENCES roles(id),
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   PRIMARY KEY (organization_id, user_id)
+			);
+
+			CREATE INDEX idx_organization_members_user_id ON organization_members(user_id);
+
+			INSERT INTO permissions (name, description) VALUES
+			   ('members:read', 'Consultar membros da própria organização'),
+			   ('members:write', 'Gerenciar membros da própria organização'),
+			   ('organizations:read', 'Consultar organizações'),
+			   ('organizations:write', 'Gerenciar organizações');
+
+			INSERT INTO roles (name, description, is_system) VALUES
+			   ('org_owner', 'Proprietário da organização', TRUE),
+			   ('org_admin', 'Administrador da organização', TRUE),
+			   ('org_member', 'Membro da organização', TRUE);
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r JOIN permissions p
+			  ON p.name IN ('users:read', 'users:write', 'members:read', 'members:write')
+			WHERE r.name IN ('org_owner', 'org_admin');
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r JOIN permissions p
+			  ON p.name IN ('members:read', 'members:write', 'organizations:read', 'organizations:write')
+			WHERE r.name = 'admin';
+
+			INSERT INTO organization_members (organization_id, user_id, role_id)
+			SELECT u.organization_id, u.id, r.id FROM users u
+			JOIN roles r ON r.name = CASE WHEN u.role = 'admin' THEN 'org_owner' ELSE 'org_member' END;
+
+			COMMENT ON TABLE organizations IS 'Organizações (tenants) clientes do Chameleon.';
+			COMMENT ON COLUMN users.organization_id IS 'Tenant ao qual a identidade pertence; e-mail é único por tenant.';
+			COMMENT ON TABLE organization_members IS 'Papel do usuário dentro da organização.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DROP TABLE IF EXISTS organization_members;
+			DELETE FROM roles WHERE name IN ('org_owner', 'org_admin', 'org_member');
+			DELETE FROM permissions WHERE name IN ('members:read', 'members:write', 'organizations:read', 'organizations:write');
+			DROP INDEX IF EXISTS idx_users_org_email_lower;
+			CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_lower ON users (lower(email));
+			ALTER TABLE users DROP COLUMN IF EXISTS organization_id;
+			DROP TABLE IF EXISTS organizations;
+		`).Error
+	},
+}
@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261040DDLCreateInvitations = gormigrate.Migration{
+	ID: "181020261040",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE TABLE invitations (
+			   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+			   email CITEXT NOT NULL,
+			   role VARCHAR(50) NOT NULL,
+			   token_hash CHAR(64) NOT NULL UNIQUE,
+			   invited_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			   expires_at TIMESTAMP NOT NULL,
+			   accepted_at TIMESTAMP,
+			   revoked_at TIMESTAMP,
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   updated_at TIMESTAMP
+			);
+
+			CREATE INDEX idx_invitations_organization_id ON invitations(organization_id);
+
+			COMMENT ON TABLE invitations IS 'Convites de uso único para usuários criados por administradores.';
+			COMMENT ON COLUMN invitations.token_hash IS 'SHA-256 do token enviado por e-mail; o token em si não é armazenado.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS invitations;`).Error
+	},
+}
@@ -0,0 +1,25 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261050DDLAddBulkPermissions = gormigrate.Migration{
+	ID: "181020261050",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			INSERT INTO permissions (name, description) VALUES
+			   ('users:import', 'Importar usuários em lote (CSV/JSONL)'),
+			   ('users:export', 'Exportar usuários (CSV/JSONL), inclusive hashes de senha');
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r JOIN permissions p
+			  ON p.name IN ('users:import', 'users:export')
+			WHERE r.name IN ('admin', 'org_owner');
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DELETE FROM permissions WHERE name IN ('users:import', 'users:export');`).Error
+	},
+}
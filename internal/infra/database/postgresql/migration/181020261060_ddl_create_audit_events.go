@@ -0,0 +1,66 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261060DDLCreateAuditEvents = gormigrate.Migration{
+	ID: "181020261060",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE TABLE audit_events (
+			   id UUID PRIMARY KEY,
+			   sequence BIGINT NOT NULL UNIQUE,
+			   organization_id UUID,
+			   actor_id UUID,
+			   target_id UUID,
+			   action VARCHAR(64) NOT NULL,
+			   outcome VARCHAR(16) NOT NULL,
+			   ip_address VARCHAR(45) NOT NULL DEFAULT '',
+			   user_agent VARCHAR(512) NOT NULL DEFAULT '',
+			   request_id VARCHAR(64) NOT NULL DEFAULT '',
+			   metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+			   prev_hash CHAR(64) NOT NULL,
+			   hash CHAR(64) NOT NULL,
+			   created_at TIMESTAMP NOT NULL
+			);
+
+			CREATE INDEX idx_audit_events_organization_sequence ON audit_events(organization_id, sequence DESC);
+			CREATE INDEX idx_audit_events_actor_id ON audit_events(actor_id);
+			CREATE INDEX idx_audit_events_target_id ON audit_events(target_id);
+
+			CREATE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+			BEGIN
+			   RAISE EXCEPTION 'audit_events is append-only';
+			END;
+			$$ LANGUAGE plpgsql;
+
+			CREATE TRIGGER trg_audit_events_append_only
+			   BEFORE UPDATE OR DELETE ON audit_events
+			   FOR EACH ROW EXECUTE FUNCTION audit_events_append_only();
+
+			CREATE TRIGGER trg_audit_events_no_truncate
+			   BEFORE TRUNCATE ON audit_events
+			   FOR EACH STATEMENT EXECUTE FUNCTION audit_events_append_only();
+
+			COMMENT ON TABLE audit_events IS 'Log de auditoria somente inserção; cada evento encadeia o hash do anterior (sequence).';
+			COMMENT ON COLUMN audit_events.hash IS 'SHA-256 do prev_hash e dos demais campos do evento.';
+			COMMENT ON COLUMN audit_events.organization_id IS 'Sem FK: o histórico sobrevive à remoção da organização.';
+
+			INSERT INTO permissions (name, description) VALUES
+			   ('audit:read', 'Consultar o log de auditoria da organização');
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name = 'audit:read'
+			WHERE r.name IN ('admin', 'org_owner');
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DELETE FROM permissions WHERE name = 'audit:read';
+			DROP TABLE IF EXISTS audit_events;
+			DROP FUNCTION IF EXISTS audit_events_append_only();
+		`).Error
+	},
+}
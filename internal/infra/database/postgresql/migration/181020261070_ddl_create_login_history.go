@@ -0,0 +1,36 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261070DDLCreateLoginHistory = gormigrate.Migration{
+	ID: "181020261070",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE TABLE login_history (
+			   id UUID PRIMARY KEY,
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			   success BOOLEAN NOT NULL,
+			   failure_reason VARCHAR(50) NOT NULL DEFAULT '',
+			   ip_address VARCHAR(45) NOT NULL DEFAULT '',
+			   ip_range VARCHAR(50) NOT NULL DEFAULT '',
+			   user_agent VARCHAR(512) NOT NULL DEFAULT '',
+			   device_fingerprint CHAR(64) NOT NULL,
+			   new_device BOOLEAN NOT NULL DEFAULT FALSE,
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX idx_login_history_user_created ON login_history(user_id, created_at DESC, id DESC);
+
+			COMMENT ON TABLE login_history IS 'Tentativas de login (com e sem sucesso) de usuários existentes.';
+			COMMENT ON COLUMN login_history.device_fingerprint IS 'SHA-256 do user agent normalizado.';
+			COMMENT ON COLUMN login_history.ip_range IS 'Rede do IP (/24 para IPv4, /48 para IPv6) usada para detectar novas origens.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS login_history;`).Error
+	},
+}
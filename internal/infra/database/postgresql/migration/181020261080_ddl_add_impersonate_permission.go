@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261080DDLAddImpersonatePermission = gormigrate.Migration{
+	ID: "181020261080",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			INSERT INTO permissions (name, description) VALUES
+			   ('users:impersonate', 'Obter token de curta duração de outro usuário (suporte)');
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name = 'users:impersonate'
+			WHERE r.name = 'admin';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DELETE FROM permissions WHERE name = 'users:impersonate';`).Error
+	},
+}
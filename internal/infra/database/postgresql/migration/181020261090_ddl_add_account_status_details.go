@@ -0,0 +1,44 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261090DDLAddAccountStatusDetails = gormigrate.Migration{
+	ID: "181020261090",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE users
+			   ADD COLUMN suspended_until TIMESTAMP,
+			   ADD COLUMN status_reason VARCHAR(500);
+
+			CREATE INDEX idx_users_suspended_until ON users(suspended_until) WHERE status = 'suspended';
+
+			CREATE TABLE user_status_history (
+			   id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			   from_status VARCHAR(20) NOT NULL,
+			   to_status VARCHAR(20) NOT NULL,
+			   reason VARCHAR(500) NOT NULL DEFAULT '',
+			   suspended_until TIMESTAMP,
+			   changed_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+			);
+
+			CREATE INDEX idx_user_status_history_user_created ON user_status_history(user_id, created_at DESC);
+
+			COMMENT ON COLUMN users.suspended_until IS 'Fim da suspensão; a conta volta a active automaticamente.';
+			COMMENT ON COLUMN users.status_reason IS 'Motivo do status atual (obrigatório para banned).';
+			COMMENT ON TABLE user_status_history IS 'Histórico de mudanças de status de usuários. changed_by nulo indica mudança automática.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DROP TABLE IF EXISTS user_status_history;
+			DROP INDEX IF EXISTS idx_users_suspended_until;
+			ALTER TABLE users DROP COLUMN IF EXISTS status_reason, DROP COLUMN IF EXISTS suspended_until;
+		`).Error
+	},
+}
@@ -0,0 +1,66 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261100DDLAddAccountDeletion = gormigrate.Migration{
+	ID: "181020261100",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE users ADD COLUMN deletion_scheduled_for TIMESTAMP;
+
+			CREATE INDEX idx_users_deletion_scheduled_for ON users(deletion_scheduled_for)
+			   WHERE deletion_scheduled_for IS NOT NULL;
+
+			COMMENT ON COLUMN users.deletion_scheduled_for IS 'Data da anonimização pedida pelo usuário; o login cancela o pedido.';
+
+			ALTER TABLE audit_events ADD COLUMN redacted_at TIMESTAMP;
+
+			COMMENT ON COLUMN audit_events.redacted_at IS 'Dados pessoais (IP, user agent, e-mail) apagados na anonimização do usuário; o hash não é mais recalculável.';
+
+			-- The only accepted update erases the personal data of an event and
+			-- leaves every chained column untouched.
+			CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+			BEGIN
+			   IF TG_OP = 'UPDATE'
+			      AND OLD.redacted_at IS NULL AND NEW.redacted_at IS NOT NULL
+			      AND NEW.ip_address = '' AND NEW.user_agent = ''
+			      AND NEW.metadata = OLD.metadata - 'email'
+			      AND (NEW.id, NEW.sequence, NEW.organization_id, NEW.actor_id, NEW.target_id, NEW.action,
+			           NEW.outcome, NEW.request_id, NEW.prev_hash, NEW.hash, NEW.created_at)
+			          IS NOT DISTINCT FROM
+			          (OLD.id, OLD.sequence, OLD.organization_id, OLD.actor_id, OLD.target_id, OLD.action,
+			           OLD.outcome, OLD.request_id, OLD.prev_hash, OLD.hash, OLD.created_at)
+			   THEN
+			      RETURN NEW;
+			   END IF;
+			   RAISE EXCEPTION 'audit_events is append-only';
+			END;
+			$$ LANGUAGE plpgsql;
+
+			INSERT INTO permissions (name, description) VALUES
+			   ('users:delete', 'Anonimizar definitivamente usuários (exclusão de dados pessoais)');
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name = 'users:delete'
+			WHERE r.name IN ('admin', 'org_owner');
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DELETE FROM permissions WHERE name = 'users:delete';
+
+			CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+			BEGIN
+			   RAISE EXCEPTION 'audit_events is append-only';
+			END;
+			$$ LANGUAGE plpgsql;
+
+			ALTER TABLE audit_events DROP COLUMN IF EXISTS redacted_at;
+			DROP INDEX IF EXISTS idx_users_deletion_scheduled_for;
+			ALTER TABLE users DROP COLUMN IF EXISTS deletion_scheduled_for;
+		`).Error
+	},
+}
@@ -0,0 +1,39 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261110DDLAddStatusSource = gormigrate.Migration{
+	ID: "181020261110",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE users ADD COLUMN status_source VARCHAR(20) NOT NULL DEFAULT 'system';
+			ALTER TABLE user_status_history ADD COLUMN source VARCHAR(20) NOT NULL DEFAULT 'admin';
+
+			UPDATE user_status_history SET source = CASE
+			   WHEN changed_by IS NULL THEN 'system'
+			   WHEN changed_by = user_id THEN 'self'
+			   ELSE 'admin'
+			END;
+
+			-- Accounts deactivated before the history existed are attributed to an admin.
+			UPDATE users u SET status_source = COALESCE((
+			   SELECT h.source FROM user_status_history h
+			   WHERE h.user_id = u.id
+			   ORDER BY h.created_at DESC
+			   LIMIT 1
+			), 'admin')
+			WHERE u.status <> 'active';
+
+			COMMENT ON COLUMN users.status_source IS 'Origem da última mudança de status: self, admin ou system. Só contas desativadas pelo próprio usuário (self) podem ser reativadas por e-mail.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE user_status_history DROP COLUMN IF EXISTS source;
+			ALTER TABLE users DROP COLUMN IF EXISTS status_source;
+		`).Error
+	},
+}
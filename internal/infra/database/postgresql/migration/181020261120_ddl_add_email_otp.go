@@ -0,0 +1,20 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261120DDLAddEmailOTP = gormigrate.Migration{
+	ID: "181020261120",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE users ADD COLUMN email_otp_enabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+			COMMENT ON COLUMN users.email_otp_enabled IS 'Exige um código enviado por e-mail como segundo fator no login e antes de operações sensíveis.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`ALTER TABLE users DROP COLUMN IF EXISTS email_otp_enabled;`).Error
+	},
+}
@@ -0,0 +1,33 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261130DDLCreateIdentities = gormigrate.Migration{
+	ID: "181020261130",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE TABLE identities (
+			   id UUID PRIMARY KEY,
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			   provider VARCHAR(63) NOT NULL,
+			   subject VARCHAR(255) NOT NULL,
+			   email VARCHAR(255) NOT NULL DEFAULT '',
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   last_login_at TIMESTAMP,
+			   CONSTRAINT uq_identities_provider_subject UNIQUE (provider, subject),
+			   CONSTRAINT uq_identities_user_provider UNIQUE (user_id, provider)
+			);
+
+			COMMENT ON TABLE identities IS 'Contas de provedores OIDC externos vinculadas a usuários.';
+			COMMENT ON COLUMN identities.subject IS 'Claim sub do ID token, estável por provedor.';
+			COMMENT ON COLUMN identities.email IS 'E-mail informado pelo provedor no vínculo, apenas informativo.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS identities;`).Error
+	},
+}
@@ -0,0 +1,80 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261140DDLCreateSCIM = gormigrate.Migration{
+	ID: "181020261140",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE TABLE scim_tokens (
+			   id UUID PRIMARY KEY,
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   name VARCHAR(100) NOT NULL,
+			   prefix VARCHAR(16) NOT NULL,
+			   token_hash CHAR(64) NOT NULL,
+			   created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   last_used_at TIMESTAMP,
+			   CONSTRAINT uq_scim_tokens_token_hash UNIQUE (token_hash)
+			);
+
+			CREATE TABLE scim_users (
+			   user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   external_id VARCHAR(255),
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   CONSTRAINT uq_scim_users_external_id UNIQUE (organization_id, external_id)
+			);
+
+			CREATE TABLE scim_groups (
+			   id UUID PRIMARY KEY,
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   display_name VARCHAR(255) NOT NULL,
+			   external_id VARCHAR(255),
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   updated_at TIMESTAMP,
+			   CONSTRAINT uq_scim_groups_display_name UNIQUE (organization_id, display_name)
+			);
+
+			CREATE TABLE scim_group_members (
+			   group_id UUID NOT NULL REFERENCES scim_groups(id) ON DELETE CASCADE,
+			   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			   PRIMARY KEY (group_id, user_id)
+			);
+			CREATE INDEX idx_scim_group_members_user ON scim_group_members (user_id);
+
+			CREATE TABLE scim_group_roles (
+			   group_id UUID NOT NULL REFERENCES scim_groups(id) ON DELETE CASCADE,
+			   role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+			   PRIMARY KEY (group_id, role_id)
+			);
+
+			COMMENT ON TABLE scim_tokens IS 'Tokens de provisionamento SCIM, um por integração com o provedor de identidade.';
+			COMMENT ON COLUMN scim_tokens.prefix IS 'Início do token, exibido para identificá-lo.';
+			COMMENT ON TABLE scim_users IS 'Usuários gerenciados pelo provedor de identidade via SCIM.';
+			COMMENT ON TABLE scim_group_roles IS 'Roles concedidas aos membros do grupo SCIM.';
+
+			COMMENT ON COLUMN users.status_source IS 'Origem da última mudança de status: self, admin, system ou provisioning (provedor de identidade via SCIM). Só contas desativadas pelo próprio usuário (self) podem ser reativadas por e-mail.';
+
+			INSERT INTO permissions (name, description) VALUES
+			   ('scim:manage', 'Gerenciar tokens e grupos de provisionamento SCIM');
+
+			INSERT INTO role_permissions (role_id, permission_id)
+			SELECT r.id, p.id FROM roles r JOIN permissions p ON p.name = 'scim:manage'
+			WHERE r.name = 'admin';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			DELETE FROM permissions WHERE name = 'scim:manage';
+			DROP TABLE IF EXISTS scim_group_roles;
+			DROP TABLE IF EXISTS scim_group_members;
+			DROP TABLE IF EXISTS scim_groups;
+			DROP TABLE IF EXISTS scim_users;
+			DROP TABLE IF EXISTS scim_tokens;
+		`).Error
+	},
+}
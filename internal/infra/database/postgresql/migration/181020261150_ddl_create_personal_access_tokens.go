@@ -0,0 +1,35 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261150DDLCreatePersonalAccessTokens = gormigrate.Migration{
+	ID: "181020261150",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE TABLE personal_access_tokens (
+			   id UUID PRIMARY KEY,
+			   organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+			   user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			   name VARCHAR(100) NOT NULL,
+			   prefix VARCHAR(16) NOT NULL,
+			   token_hash CHAR(64) NOT NULL,
+			   scopes TEXT NOT NULL DEFAULT '',
+			   expires_at TIMESTAMP NOT NULL,
+			   last_used_at TIMESTAMP,
+			   created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			   CONSTRAINT uq_personal_access_tokens_token_hash UNIQUE (token_hash),
+			   CONSTRAINT uq_personal_access_tokens_name UNIQUE (user_id, name)
+			);
+
+			COMMENT ON TABLE personal_access_tokens IS 'Tokens de acesso pessoal de usuários, para scripts e jobs de CI.';
+			COMMENT ON COLUMN personal_access_tokens.prefix IS 'Início do token, exibido para identificá-lo.';
+			COMMENT ON COLUMN personal_access_tokens.scopes IS 'Permissões concedidas ao token, separadas por espaço.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`DROP TABLE IF EXISTS personal_access_tokens;`).Error
+	},
+}
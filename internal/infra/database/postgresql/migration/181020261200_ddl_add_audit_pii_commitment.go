@@ -0,0 +1,63 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261200DDLAddAuditPIICommitment = gormigrate.Migration{
+	ID: "181020261200",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE audit_events
+			   ADD COLUMN pii_salt VARCHAR(32) NOT NULL DEFAULT '',
+			   ADD COLUMN pii_hash VARCHAR(64) NOT NULL DEFAULT '';
+
+			COMMENT ON COLUMN audit_events.pii_hash IS 'SHA-256 do pii_salt, IP, user agent e e-mail; o hash do evento cobre o pii_hash no lugar desses dados. Vazio em eventos anteriores.';
+			COMMENT ON COLUMN audit_events.redacted_at IS 'Dados pessoais (IP, user agent, e-mail) e pii_salt apagados na anonimização do usuário; o restante do evento segue verificável pelo hash.';
+
+			-- The only accepted update erases the personal data of an event and
+			-- its salt, and leaves every chained column untouched.
+			CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+			BEGIN
+			   IF TG_OP = 'UPDATE'
+			      AND OLD.redacted_at IS NULL AND NEW.redacted_at IS NOT NULL
+			      AND NEW.ip_address = '' AND NEW.user_agent = '' AND NEW.pii_salt = ''
+			      AND NEW.metadata = OLD.metadata - 'email'
+			      AND (NEW.id, NEW.sequence, NEW.organization_id, NEW.actor_id, NEW.target_id, NEW.action,
+			           NEW.outcome, NEW.request_id, NEW.pii_hash, NEW.prev_hash, NEW.hash, NEW.created_at)
+			          IS NOT DISTINCT FROM
+			          (OLD.id, OLD.sequence, OLD.organization_id, OLD.actor_id, OLD.target_id, OLD.action,
+			           OLD.outcome, OLD.request_id, OLD.pii_hash, OLD.prev_hash, OLD.hash, OLD.created_at)
+			   THEN
+			      RETURN NEW;
+			   END IF;
+			   RAISE EXCEPTION 'audit_events is append-only';
+			END;
+			$$ LANGUAGE plpgsql;
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			CREATE OR REPLACE FUNCTION audit_events_append_only() RETURNS trigger AS $$
+			BEGIN
+			   IF TG_OP = 'UPDATE'
+			      AND OLD.redacted_at IS NULL AND NEW.redacted_at IS NOT NULL
+			      AND NEW.ip_address = '' AND NEW.user_agent = ''
+			      AND NEW.metadata = OLD.metadata - 'email'
+			      AND (NEW.id, NEW.sequence, NEW.organization_id, NEW.actor_id, NEW.target_id, NEW.action,
+			           NEW.outcome, NEW.request_id, NEW.prev_hash, NEW.hash, NEW.created_at)
+			          IS NOT DISTINCT FROM
+			          (OLD.id, OLD.sequence, OLD.organization_id, OLD.actor_id, OLD.target_id, OLD.action,
+			           OLD.outcome, OLD.request_id, OLD.prev_hash, OLD.hash, OLD.created_at)
+			   THEN
+			      RETURN NEW;
+			   END IF;
+			   RAISE EXCEPTION 'audit_events is append-only';
+			END;
+			$$ LANGUAGE plpgsql;
+
+			ALTER TABLE audit_events DROP COLUMN IF EXISTS pii_hash, DROP COLUMN IF EXISTS pii_salt;
+		`).Error
+	},
+}
@@ -0,0 +1,23 @@
+package migration
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+var ID181020261210DDLAddPersonalTokenVersion = gormigrate.Migration{
+	ID: "181020261210",
+	Migrate: func(tx *gorm.DB) error {
+		return tx.Exec(`
+			ALTER TABLE personal_access_tokens ADD COLUMN token_version INT NOT NULL DEFAULT 0;
+
+			UPDATE personal_access_tokens p SET token_version = u.token_version
+			FROM users u WHERE u.id = p.user_id;
+
+			COMMENT ON COLUMN personal_access_tokens.token_version IS 'token_version do usuário na criação; o token deixa de valer quando as sessões do usuário são revogadas.';
+		`).Error
+	},
+	Rollback: func(tx *gorm.DB) error {
+		return tx.Exec(`ALTER TABLE personal_access_tokens DROP COLUMN IF EXISTS token_version;`).Error
+	},
+}
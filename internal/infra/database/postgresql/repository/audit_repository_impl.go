@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/audit"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// auditChainLock is the transaction-level advisory lock key that serializes
+// appends to the audit chain.
+const auditChainLock = 0x61756469
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) audit.IRepository {
+	return &auditRepository{db: db}
+}
+
+func (r *auditRepository) Append(ctx context.Context, e *audit.Event) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", auditChainLock).Error; err != nil {
+			return err
+		}
+
+		var last audit.Event
+		err := tx.Select("sequence", "hash").Order("sequence DESC").Take(&last).Error
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			e.Sequence = 1
+			e.PrevHash = audit.GenesisHash
+		case err != nil:
+			return err
+		default:
+			e.Sequence = last.Sequence + 1
+			e.PrevHash = last.Hash
+		}
+
+		e.Hash = e.ComputeHash()
+		return tx.Create(e).Error
+	})
+}
+
+func (r *auditRepository) List(ctx context.Context, filter audit.Filter) ([]audit.Event, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	query := r.db.WithContext(opCtx).Where("organization_id = ?", filter.OrganizationID)
+
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if len(filter.Actions) > 0 {
+		query = query.Where("action IN ?", filter.Actions)
+	}
+	if filter.Outcome != "" {
+		query = query.Where("outcome = ?", filter.Outcome)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", filter.From.UTC())
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", filter.To.UTC())
+	}
+	if filter.BeforeSequence > 0 {
+		query = query.Where("sequence < ?", filter.BeforeSequence)
+	}
+
+	var events []audit.Event
+	if err := query.Order("sequence DESC").Limit(filter.Limit).Find(&events).Error; err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *auditRepository) Walk(ctx context.Context, fn func(e *audit.Event) error) error {
+	opCtx, cancel := context.WithTimeout(ctx, exportQueryTimeout)
+	defer cancel()
+
+	db := r.db.WithContext(opCtx)
+	rows, err := db.Model(&audit.Event{}).Order("sequence ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e audit.Event
+		if err := db.ScanRows(rows, &e); err != nil {
+			return err
+		}
+		if err := fn(&e); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *auditRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]audit.Event, error) {
+	opCtx, cancel := context.WithTimeout(ctx, exportQueryTimeout)
+	defer cancel()
+
+	var events []audit.Event
+	err := r.db.WithContext(opCtx).
+		Where("actor_id = ? OR target_id = ?", userID, userID).
+		Order("sequence ASC").
+		Find(&events).Error
+	return events, err
+}
+
+// Redact performs the only update the audit_events trigger accepts: blanking
+// the IP address, user agent and personal data salt, dropping the email
+// metadata key and setting redacted_at.
+func (r *auditRepository) Redact(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, email string) (int64, error) {
+	opCtx, cancel := context.WithTimeout(ctx, exportQueryTimeout)
+	defer cancel()
+
+	result := r.db.WithContext(opCtx).Exec(`
+		UPDATE audit_events
+		SET ip_address = '', user_agent = '', pii_salt = '', metadata = metadata - 'email', redacted_at = ?
+		WHERE redacted_at IS NULL
+		  AND (actor_id = ? OR target_id = ? OR (organization_id = ? AND metadata->>'email' = ?))`,
+		time.Now().UTC(), userID, userID, organizationID, email,
+	)
+	return result.RowsAffected, result.Error
+}
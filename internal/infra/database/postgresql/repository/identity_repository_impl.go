@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type identityRepository struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepository(db *gorm.DB) federation.IRepository {
+	return &identityRepository{db: db}
+}
+
+func (r *identityRepository) FindBySubject(ctx context.Context, provider string, subject string) (*federation.Identity, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var identity federation.Identity
+	err := r.scoped(opCtx).Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, federation.ErrIdentityNotFound
+		}
+		return nil, err
+	}
+	return &identity, nil
+}
+
+func (r *identityRepository) Create(ctx context.Context, identity *federation.Identity) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	if err := r.db.WithContext(opCtx).Create(identity).Error; err != nil {
+		if isUniqueViolation(err) {
+			return federation.ErrIdentityAlreadyLinked
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *identityRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	return r.scoped(opCtx).Model(&federation.Identity{}).Where("id = ?", id).Update("last_login_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+}
+
+func (r *identityRepository) ListForUser(ctx context.Context, userID uuid.UUID) ([]federation.Identity, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var identities []federation.Identity
+	if err := r.scoped(opCtx).Where("user_id = ?", userID).Order("created_at").Find(&identities).Error; err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+func (r *identityRepository) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.scoped(opCtx).Where("user_id = ? AND id = ?", userID, id).Delete(&federation.Identity{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return federation.ErrIdentityNotFound
+	}
+	return nil
+}
+
+func (r *identityRepository) scoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Where("identities.organization_id = ?", tenant.OrganizationID(ctx))
+}
@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/invitation"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const openInvitation = "accepted_at IS NULL AND revoked_at IS NULL"
+
+type invitationRepository struct {
+	db *gorm.DB
+}
+
+func NewInvitationRepository(db *gorm.DB) invitation.IRepository {
+	return &invitationRepository{db: db}
+}
+
+func (r *invitationRepository) Create(ctx context.Context, inv *invitation.Invitation) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	return r.db.WithContext(opCtx).Create(inv).Error
+}
+
+func (r *invitationRepository) FindByID(ctx context.Context, organizationID uuid.UUID, id uuid.UUID) (*invitation.Invitation, error) {
+	return r.findOne(ctx, "organization_id = ? AND id = ?", organizationID, id)
+}
+
+func (r *invitationRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*invitation.Invitation, error) {
+	return r.findOne(ctx, "token_hash = ?", tokenHash)
+}
+
+func (r *invitationRepository) ListOpen(ctx context.Context, organizationID uuid.UUID) ([]invitation.Invitation, error) {
+	var invitations []invitation.Invitation
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	err := r.db.WithContext(opCtx).
+		Where("organization_id = ?", organizationID).
+		Where(openInvitation).
+		Order("created_at DESC").
+		Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *invitationRepository) RenewToken(ctx context.Context, id uuid.UUID, tokenHash string, expiresAt time.Time) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.db.WithContext(opCtx).Model(&invitation.Invitation{}).
+		Where("id = ?", id).
+		Where(openInvitation).
+		Updates(map[string]interface{}{
+			"token_hash": tokenHash,
+			"expires_at": expiresAt,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return invitation.ErrInvitationClosed
+	}
+	return nil
+}
+
+func (r *invitationRepository) Accept(ctx context.Context, inv *invitation.Invitation, name string, passwordHash string) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		// The conditional update makes the token single-use under concurrency.
+		result := tx.Model(&invitation.Invitation{}).
+			Where("id = ? AND expires_at > ?", inv.ID, now).
+			Where(openInvitation).
+			Updates(map[string]interface{}{"accepted_at": now, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return invitation.ErrInvalidInvitation
+		}
+
+		result = tx.Model(&user.User{}).
+			Where("id = ? AND organization_id = ? AND status = ?", inv.UserID, inv.OrganizationID, user.StatusInvited).
+			Updates(map[string]interface{}{
+				"name":                name,
+				"password_hash":       passwordHash,
+				"status":              user.StatusActive,
+				"password_changed_at": now,
+				"updated_at":          now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return invitation.ErrInvalidInvitation
+		}
+
+		inv.AcceptedAt = &now
+		return nil
+	})
+}
+
+func (r *invitationRepository) Revoke(ctx context.Context, inv *invitation.Invitation) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+
+		result := tx.Model(&invitation.Invitation{}).
+			Where("id = ?", inv.ID).
+			Where(openInvitation).
+			Updates(map[string]interface{}{"revoked_at": now, "updated_at": now})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return invitation.ErrInvitationClosed
+		}
+
+		// The invited user never signed in; removing the row frees the e-mail
+		// for a new invitation or sign-up. Memberships and roles cascade.
+		if inv.UserID != nil {
+			err := tx.Exec("DELETE FROM users WHERE id = ? AND organization_id = ? AND status = ?",
+				*inv.UserID, inv.OrganizationID, user.StatusInvited).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		inv.RevokedAt = &now
+		return nil
+	})
+}
+
+func (r *invitationRepository) findOne(ctx context.Context, query string, args ...interface{}) (*invitation.Invitation, error) {
+	var inv invitation.Invitation
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	if err := r.db.WithContext(opCtx).Where(query, args...).First(&inv).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invitation.ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	return &inv, nil
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/loginhistory"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type loginHistoryRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginHistoryRepository(db *gorm.DB) loginhistory.IRepository {
+	return &loginHistoryRepository{db: db}
+}
+
+func (r *loginHistoryRepository) Create(ctx context.Context, entry *loginhistory.Entry) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	return r.db.WithContext(opCtx).Create(entry).Error
+}
+
+func (r *loginHistoryRepository) Seen(ctx context.Context, userID uuid.UUID, fingerprint string, ipRange string) (*loginhistory.Seen, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var seen loginhistory.Seen
+	err := r.db.WithContext(opCtx).Raw(`
+		SELECT COUNT(*) > 0 AS any_login,
+		       COALESCE(bool_or(device_fingerprint = ?), false) AS device,
+		       COALESCE(bool_or(ip_range = ?), false) AS ip_range
+		FROM login_history
+		WHERE user_id = ? AND success`,
+		fingerprint, ipRange, userID,
+	).Scan(&seen).Error
+	if err != nil {
+		return nil, err
+	}
+	return &seen, nil
+}
+
+func (r *loginHistoryRepository) List(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, after *loginhistory.Cursor, limit int) ([]loginhistory.Entry, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	query := r.db.WithContext(opCtx).
+		Where("organization_id = ? AND user_id = ?", organizationID, userID)
+	if after != nil {
+		query = query.Where("(created_at, id) < (?, ?)", after.CreatedAt.UTC(), after.ID)
+	}
+
+	var entries []loginhistory.Entry
+	if err := query.Order("created_at DESC, id DESC").Limit(limit).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,151 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/organization"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type organizationRepository struct {
+	db *gorm.DB
+}
+
+func NewOrganizationRepository(db *gorm.DB) organization.IRepository {
+	return &organizationRepository{db: db}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *organization.Organization) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	if err := r.db.WithContext(opCtx).Create(org).Error; err != nil {
+		if isUniqueViolation(err) {
+			return organization.ErrSlugTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *organizationRepository) List(ctx context.Context) ([]organization.Organization, error) {
+	var orgs []organization.Organization
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	if err := r.db.WithContext(opCtx).Order("name").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+func (r *organizationRepository) FindByID(ctx context.Context, id uuid.UUID) (*organization.Organization, error) {
+	return r.findOne(ctx, "id = ?", id)
+}
+
+func (r *organizationRepository) FindBySlug(ctx context.Context, slug string) (*organization.Organization, error) {
+	return r.findOne(ctx, "slug = ?", slug)
+}
+
+func (r *organizationRepository) AddMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, roleName string) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.db.WithContext(opCtx).Exec(`
+		INSERT INTO organization_members (organization_id, user_id, role_id)
+		SELECT ?, ?, id FROM roles WHERE name = ?
+		ON CONFLICT (organization_id, user_id) DO UPDATE SET role_id = EXCLUDED.role_id`,
+		organizationID, userID, roleName)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return organization.ErrInvalidMemberRole
+	}
+	return nil
+}
+
+func (r *organizationRepository) FindMemberRole(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) (*rbac.Role, error) {
+	var role rbac.Role
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	err := r.db.WithContext(opCtx).
+		Preload("Permissions").
+		Joins("JOIN organization_members om ON om.role_id = roles.id").
+		Where("om.organization_id = ? AND om.user_id = ?", organizationID, userID).
+		First(&role).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, organization.ErrMemberNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, organizationID uuid.UUID) ([]organization.Member, error) {
+	var members []organization.Member
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	err := r.db.WithContext(opCtx).Raw(`
+		SELECT u.id AS user_id, u.name, u.email, u.status, r.id AS role_id, r.name AS role_name,
+		       om.created_at AS joined_at, u.last_login_at AS last_seen
+		FROM organization_members om
+		JOIN users u ON u.id = om.user_id AND u.organization_id = om.organization_id AND u.deleted_at IS NULL
+		JOIN roles r ON r.id = om.role_id
+		WHERE om.organization_id = ?
+		ORDER BY lower(u.name), u.id`, organizationID).Scan(&members).Error
+	if err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+func (r *organizationRepository) UpdateMemberRole(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID, roleName string) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.db.WithContext(opCtx).Exec(`
+		UPDATE organization_members SET role_id = r.id
+		FROM roles r
+		WHERE r.name = ? AND organization_members.organization_id = ? AND organization_members.user_id = ?`,
+		roleName, organizationID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return organization.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, organizationID uuid.UUID, userID uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.db.WithContext(opCtx).Exec(
+		"DELETE FROM organization_members WHERE organization_id = ? AND user_id = ?", organizationID, userID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return organization.ErrMemberNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) findOne(ctx context.Context, query string, arg interface{}) (*organization.Organization, error) {
+	var org organization.Organization
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	if err := r.db.WithContext(opCtx).Where(query, arg).First(&org).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, organization.ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return &org, nil
+}
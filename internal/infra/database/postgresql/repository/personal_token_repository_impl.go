@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/personaltoken"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type personalTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewPersonalTokenRepository(db *gorm.DB) personaltoken.IRepository {
+	return &personalTokenRepository{db: db}
+}
+
+func (r *personalTokenRepository) Create(ctx context.Context, token *personaltoken.Token) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	token.OrganizationID = tenant.OrganizationID(ctx)
+	if err := r.db.WithContext(opCtx).Create(token).Error; err != nil {
+		if isUniqueViolation(err) {
+			return personaltoken.ErrNameTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *personalTokenRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]personaltoken.Token, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var tokens []personaltoken.Token
+	if err := r.scoped(opCtx).Where("user_id = ?", userID).Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *personalTokenRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int64, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var count int64
+	if err := r.scoped(opCtx).Model(&personaltoken.Token{}).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *personalTokenRepository) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.scoped(opCtx).Where("user_id = ? AND id = ?", userID, id).Delete(&personaltoken.Token{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return personaltoken.ErrTokenNotFound
+	}
+	return nil
+}
+
+func (r *personalTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*personaltoken.Token, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var token personaltoken.Token
+	if err := r.db.WithContext(opCtx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, personaltoken.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *personalTokenRepository) Touch(ctx context.Context, id uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Model(&personaltoken.Token{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute')", id).
+		Update("last_used_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+}
+
+func (r *personalTokenRepository) scoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Where("personal_access_tokens.organization_id = ?", tenant.OrganizationID(ctx))
+}
@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type rbacRepository struct {
+	db *gorm.DB
+}
+
+func NewRBACRepository(db *gorm.DB) rbac.IRepository {
+	return &rbacRepository{db: db}
+}
+
+func (r *rbacRepository) ListRoles(ctx context.Context) ([]rbac.Role, error) {
+	var roles []rbac.Role
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	if err := r.db.WithContext(opCtx).Preload("Permissions").Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *rbacRepository) FindRoleByID(ctx context.Context, id uuid.UUID) (*rbac.Role, error) {
+	var role rbac.Role
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	if err := r.db.WithContext(opCtx).Preload("Permissions").First(&role, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, rbac.ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *rbacRepository) FindRolesByNames(ctx context.Context, names []string) ([]rbac.Role, error) {
+	var roles []rbac.Role
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	if err := r.db.WithContext(opCtx).Where("name IN ?", names).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *rbacRepository) CreateRole(ctx context.Context, role *rbac.Role, permissionNames []string) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		permissions, err := findPermissions(tx, permissionNames)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Permissions").Create(role).Error; err != nil {
+			if isUniqueViolation(err) {
+				return rbac.ErrRoleNameTaken
+			}
+			return err
+		}
+
+		return tx.Model(role).Association("Permissions").Replace(permissions)
+	})
+}
+
+func (r *rbacRepository) UpdateRole(ctx context.Context, role *rbac.Role, permissionNames []string) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		permissions, err := findPermissions(tx, permissionNames)
+		if err != nil {
+			return err
+		}
+
+		updates := map[string]interface{}{
+			"name":        role.Name,
+			"description": role.Description,
+			"updated_at":  time.Now(),
+		}
+		if err := tx.Model(&rbac.Role{}).Where("id = ?", role.ID).Updates(updates).Error; err != nil {
+			if isUniqueViolation(err) {
+				return rbac.ErrRoleNameTaken
+			}
+			return err
+		}
+
+		return tx.Model(role).Association("Permissions").Replace(permissions)
+	})
+}
+
+func (r *rbacRepository) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.db.WithContext(opCtx).Where("id = ? AND is_system = FALSE", id).Delete(&rbac.Role{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return rbac.ErrRoleNotFound
+	}
+	return nil
+}
+
+func (r *rbacRepository) ListPermissions(ctx context.Context) ([]rbac.Permission, error) {
+	var permissions []rbac.Permission
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	if err := r.db.WithContext(opCtx).Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *rbacRepository) GetUserRoles(ctx context.Context, userID uuid.UUID) ([]rbac.Role, error) {
+	var roles []rbac.Role
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	err := r.db.WithContext(opCtx).
+		Preload("Permissions").
+		Joins("JOIN user_roles ur ON ur.role_id = roles.id").
+		Where("ur.user_id = ?", userID).
+		Order("roles.name").
+		Find(&roles).Error
+	if err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+// SetUserRoles replaces the roles of the user and keeps the legacy users.role
+// column, still read by services relying on the role claim, in sync.
+func (r *rbacRepository) SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM user_roles WHERE user_id = ?", userID).Error; err != nil {
+			return err
+		}
+
+		for _, roleID := range roleIDs {
+			if err := tx.Exec("INSERT INTO user_roles (user_id, role_id) VALUES (?, ?)", userID, roleID).Error; err != nil {
+				return err
+			}
+		}
+
+		result := tx.Exec(`
+			UPDATE users SET role = CASE WHEN EXISTS (
+			   SELECT 1 FROM user_roles ur JOIN roles r ON r.id = ur.role_id
+			   WHERE ur.user_id = users.id AND r.name = ?
+			) THEN ? ELSE ? END
+			WHERE id = ? AND organization_id = ?`,
+			rbac.RoleAdmin, rbac.RoleAdmin, rbac.RoleUser, userID, tenant.OrganizationID(ctx))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return auth.ErrUserNotFound
+		}
+		return nil
+	})
+}
+
+func (r *rbacRepository) AddUserRole(ctx context.Context, userID uuid.UUID, roleName string) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Exec(`
+		INSERT INTO user_roles (user_id, role_id)
+		SELECT ?, id FROM roles WHERE name = ?
+		ON CONFLICT DO NOTHING`, userID, roleName).Error
+}
+
+func (r *rbacRepository) GetUserPermissions(ctx context.Context, userID uuid.UUID) ([]string, error) {
+	var permissions []string
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	err := r.db.WithContext(opCtx).Raw(`
+		SELECT DISTINCT p.name FROM permissions p
+		JOIN role_permissions rp ON rp.permission_id = p.id
+		JOIN user_roles ur ON ur.role_id = rp.role_id
+		WHERE ur.user_id = ?
+		ORDER BY p.name`, userID).Scan(&permissions).Error
+	if err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func findPermissions(tx *gorm.DB, names []string) ([]rbac.Permission, error) {
+	permissions := []rbac.Permission{}
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	if err := tx.Where("name IN ?", names).Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+
+	unique := make(map[string]struct{}, len(names))
+	for _, n := range names {
+		unique[n] = struct{}{}
+	}
+	if len(permissions) != len(unique) {
+		return nil, rbac.ErrUnknownPermission
+	}
+	return permissions, nil
+}
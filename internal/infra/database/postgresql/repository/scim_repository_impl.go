@@ -0,0 +1,490 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/scim"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type scimGroupMember struct {
+	GroupID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (scimGroupMember) TableName() string {
+	return "scim_group_members"
+}
+
+type scimGroupRole struct {
+	GroupID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoleID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (scimGroupRole) TableName() string {
+	return "scim_group_roles"
+}
+
+// scimMembership is a row of the membership queries: a group and a user with
+// the display name of the other side.
+type scimMembership struct {
+	GroupID uuid.UUID
+	UserID  uuid.UUID
+	Display string
+}
+
+type scimRepository struct {
+	db *gorm.DB
+}
+
+func NewSCIMRepository(db *gorm.DB) scim.IRepository {
+	return &scimRepository{db: db}
+}
+
+func (r *scimRepository) CreateToken(ctx context.Context, token *scim.Token) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	token.OrganizationID = tenant.OrganizationID(ctx)
+	return r.db.WithContext(opCtx).Create(token).Error
+}
+
+func (r *scimRepository) ListTokens(ctx context.Context) ([]scim.Token, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var tokens []scim.Token
+	if err := r.scoped(opCtx, "scim_tokens").Order("created_at").Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (r *scimRepository) DeleteToken(ctx context.Context, id uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.scoped(opCtx, "scim_tokens").Where("id = ?", id).Delete(&scim.Token{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return scim.ErrTokenNotFound
+	}
+	return nil
+}
+
+func (r *scimRepository) FindTokenByHash(ctx context.Context, tokenHash string) (*scim.Token, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var token scim.Token
+	if err := r.db.WithContext(opCtx).Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, scim.ErrInvalidToken
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *scimRepository) TouchToken(ctx context.Context, id uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Model(&scim.Token{}).
+		Where("id = ? AND (last_used_at IS NULL OR last_used_at < CURRENT_TIMESTAMP - INTERVAL '1 minute')", id).
+		Update("last_used_at", gorm.Expr("CURRENT_TIMESTAMP")).Error
+}
+
+func (r *scimRepository) FindUser(ctx context.Context, id uuid.UUID) (*scim.User, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var users []scim.User
+	err := r.users(opCtx).Where("users.id = ?", id).Limit(1).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, scim.ErrUserNotFound
+	}
+	if err := r.loadUserGroups(opCtx, users); err != nil {
+		return nil, err
+	}
+	return &users[0], nil
+}
+
+func (r *scimRepository) ListUsers(ctx context.Context, q scim.Query) ([]scim.User, int64, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	query := r.users(opCtx)
+	if f := q.Filter; f != nil {
+		switch f.Attribute {
+		case "username", "emails.value":
+			query = query.Where("users.email = ?", strings.ToLower(strings.TrimSpace(f.Value)))
+		case "externalid":
+			query = query.Where("scim_users.external_id = ?", f.Value)
+		case "id":
+			id, err := uuid.Parse(f.Value)
+			if err != nil {
+				return []scim.User{}, 0, nil
+			}
+			query = query.Where("users.id = ?", id)
+		}
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	users := []scim.User{}
+	if limit := q.Limit(); limit > 0 {
+		err := query.Order("users.created_at, users.id").Offset(q.Offset()).Limit(limit).Find(&users).Error
+		if err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := r.loadUserGroups(opCtx, users); err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
+func (r *scimRepository) SaveUserLink(ctx context.Context, link *scim.UserLink) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	link.OrganizationID = tenant.OrganizationID(ctx)
+	err := r.db.WithContext(opCtx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"external_id"}),
+	}).Create(link).Error
+	if err != nil {
+		if isUniqueViolation(err) {
+			return scim.ErrExternalIDTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *scimRepository) IsLinked(ctx context.Context, userID uuid.UUID) (bool, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var count int64
+	err := r.scoped(opCtx, "scim_users").Model(&scim.UserLink{}).Where("user_id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+func (r *scimRepository) ExistingUsers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	existing := []uuid.UUID{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	err := r.users(opCtx).Where("users.id IN ?", ids).Pluck("users.id", &existing).Error
+	return existing, err
+}
+
+func (r *scimRepository) CreateGroup(ctx context.Context, group *scim.Group) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	group.OrganizationID = tenant.OrganizationID(ctx)
+	if err := r.db.WithContext(opCtx).Create(group).Error; err != nil {
+		if isUniqueViolation(err) {
+			return scim.ErrGroupNameTaken
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *scimRepository) FindGroup(ctx context.Context, id uuid.UUID) (*scim.Group, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var groups []scim.Group
+	if err := r.scoped(opCtx, "scim_groups").Where("id = ?", id).Limit(1).Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, scim.ErrGroupNotFound
+	}
+	if err := r.loadGroupDetails(opCtx, groups); err != nil {
+		return nil, err
+	}
+	return &groups[0], nil
+}
+
+func (r *scimRepository) ListGroups(ctx context.Context, q scim.Query) ([]scim.Group, int64, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	query := r.scoped(opCtx, "scim_groups").Model(&scim.Group{})
+	if f := q.Filter; f != nil {
+		switch f.Attribute {
+		case "displayname":
+			query = query.Where("LOWER(display_name) = LOWER(?)", f.Value)
+		case "externalid":
+			query = query.Where("external_id = ?", f.Value)
+		case "id":
+			id, err := uuid.Parse(f.Value)
+			if err != nil {
+				return []scim.Group{}, 0, nil
+			}
+			query = query.Where("id = ?", id)
+		}
+	}
+
+	var total int64
+	if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	groups := []scim.Group{}
+	if limit := q.Limit(); limit > 0 {
+		if err := query.Order("created_at, id").Offset(q.Offset()).Limit(limit).Find(&groups).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	if err := r.loadGroupDetails(opCtx, groups); err != nil {
+		return nil, 0, err
+	}
+	return groups, total, nil
+}
+
+func (r *scimRepository) UpdateGroup(ctx context.Context, group *scim.Group) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	result := r.scoped(opCtx, "scim_groups").Model(&scim.Group{}).Where("id = ?", group.ID).Updates(map[string]interface{}{
+		"display_name": group.DisplayName,
+		"external_id":  group.ExternalID,
+		"updated_at":   now,
+	})
+	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return scim.ErrGroupNameTaken
+		}
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return scim.ErrGroupNotFound
+	}
+	group.UpdatedAt = &now
+	return nil
+}
+
+func (r *scimRepository) DeleteGroup(ctx context.Context, id uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.scoped(opCtx, "scim_groups").Where("id = ?", id).Delete(&scim.Group{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return scim.ErrGroupNotFound
+	}
+	return nil
+}
+
+// AddMembers and RemoveMembers expect a group of the organization; the
+// service loads it first.
+func (r *scimRepository) AddMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	rows := make([]scimGroupMember, 0, len(userIDs))
+	for _, userID := range userIDs {
+		rows = append(rows, scimGroupMember{GroupID: groupID, UserID: userID})
+	}
+	return r.db.WithContext(opCtx).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+}
+
+func (r *scimRepository) RemoveMembers(ctx context.Context, groupID uuid.UUID, userIDs []uuid.UUID) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Where("group_id = ? AND user_id IN ?", groupID, userIDs).Delete(&scimGroupMember{}).Error
+}
+
+func (r *scimRepository) SetGroupRoles(ctx context.Context, groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", groupID).Delete(&scimGroupRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+		rows := make([]scimGroupRole, 0, len(roleIDs))
+		for _, roleID := range roleIDs {
+			rows = append(rows, scimGroupRole{GroupID: groupID, RoleID: roleID})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+func (r *scimRepository) UserGroups(ctx context.Context, userID uuid.UUID) ([]scim.Group, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var groups []scim.Group
+	err := r.scoped(opCtx, "scim_groups").
+		Where("id IN (SELECT group_id FROM scim_group_members WHERE user_id = ?)", userID).
+		Find(&groups).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadGroupRoles(opCtx, groups); err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+func (r *scimRepository) HasRoleMappings(ctx context.Context) (bool, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var exists bool
+	err := r.db.WithContext(opCtx).Raw(`
+		SELECT EXISTS (
+		   SELECT 1 FROM scim_group_roles gr JOIN scim_groups g ON g.id = gr.group_id
+		   WHERE g.organization_id = ?
+		)`, tenant.OrganizationID(ctx)).Scan(&exists).Error
+	return exists, err
+}
+
+// users selects the users of the organization with their link, leaving out
+// the ones scheduled for deletion.
+func (r *scimRepository) users(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&scim.User{}).
+		Select("users.*, scim_users.external_id").
+		Joins("LEFT JOIN scim_users ON scim_users.user_id = users.id").
+		Where("users.organization_id = ? AND users.deletion_scheduled_for IS NULL", tenant.OrganizationID(ctx))
+}
+
+func (r *scimRepository) loadUserGroups(ctx context.Context, users []scim.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	userIDs := make([]uuid.UUID, 0, len(users))
+	for _, u := range users {
+		userIDs = append(userIDs, u.ID)
+	}
+
+	var rows []scimMembership
+	err := r.db.WithContext(ctx).Table("scim_group_members m").
+		Select("m.group_id, m.user_id, g.display_name AS display").
+		Joins("JOIN scim_groups g ON g.id = m.group_id").
+		Where("m.user_id IN ?", userIDs).
+		Order("g.display_name").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range users {
+		users[i].Groups = []scim.Member{}
+		for _, row := range rows {
+			if row.UserID == users[i].ID {
+				users[i].Groups = append(users[i].Groups, scim.Member{ID: row.GroupID, Display: row.Display})
+			}
+		}
+	}
+	return nil
+}
+
+func (r *scimRepository) loadGroupDetails(ctx context.Context, groups []scim.Group) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var rows []scimMembership
+	err := r.db.WithContext(ctx).Table("scim_group_members m").
+		Select("m.group_id, m.user_id, u.name AS display").
+		Joins("JOIN users u ON u.id = m.user_id AND u.deleted_at IS NULL").
+		Where("m.group_id IN ?", groupIDs(groups)).
+		Order("u.name").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range groups {
+		groups[i].Members = []scim.Member{}
+		for _, row := range rows {
+			if row.GroupID == groups[i].ID {
+				groups[i].Members = append(groups[i].Members, scim.Member{ID: row.UserID, Display: row.Display})
+			}
+		}
+	}
+	return r.loadGroupRoles(ctx, groups)
+}
+
+func (r *scimRepository) loadGroupRoles(ctx context.Context, groups []scim.Group) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	var rows []struct {
+		GroupID uuid.UUID
+		Name    string
+	}
+	err := r.db.WithContext(ctx).Table("scim_group_roles gr").
+		Select("gr.group_id, r.name").
+		Joins("JOIN roles r ON r.id = gr.role_id").
+		Where("gr.group_id IN ?", groupIDs(groups)).
+		Order("r.name").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range groups {
+		groups[i].Roles = []string{}
+		for _, row := range rows {
+			if row.GroupID == groups[i].ID {
+				groups[i].Roles = append(groups[i].Roles, row.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (r *scimRepository) scoped(ctx context.Context, table string) *gorm.DB {
+	return r.db.WithContext(ctx).Where(table+".organization_id = ?", tenant.OrganizationID(ctx))
+}
+
+func groupIDs(groups []scim.Group) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(groups))
+	for _, g := range groups {
+		ids = append(ids, g.ID)
+	}
+	return ids
+}
@@ -0,0 +1,128 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/rbac"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const exportQueryTimeout = 10 * time.Minute
+
+// FindExistingEmails returns which of the given lower-cased e-mails are
+// already taken in the tenant, including soft-deleted users, which still hold
+// the unique index.
+func (r *userRepository) FindExistingEmails(ctx context.Context, emails []string) (map[string]struct{}, error) {
+	existing := make(map[string]struct{}, len(emails))
+	if len(emails) == 0 {
+		return existing, nil
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var found []string
+	err := r.scoped(opCtx).Unscoped().Model(&user.User{}).
+		Where("lower(email) IN ?", emails).
+		Pluck("lower(email)", &found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, email := range found {
+		existing[strings.ToLower(email)] = struct{}{}
+	}
+	return existing, nil
+}
+
+// ImportBatch inserts the users with the global user role and their
+// organization membership in a single transaction.
+func (r *userRepository) ImportBatch(ctx context.Context, users []user.ImportedUser) error {
+	if len(users) == 0 {
+		return nil
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	organizationID := tenant.OrganizationID(ctx)
+	rows := make([]user.User, 0, len(users))
+	ids := make([]uuid.UUID, 0, len(users))
+	idsByRole := map[string][]uuid.UUID{}
+	for _, u := range users {
+		u.User.OrganizationID = organizationID
+		rows = append(rows, *u.User)
+		ids = append(ids, u.User.ID)
+		idsByRole[u.OrganizationRole] = append(idsByRole[u.OrganizationRole], u.User.ID)
+	}
+
+	err := r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&rows).Error; err != nil {
+			return err
+		}
+
+		err := tx.Exec(`
+			INSERT INTO user_roles (user_id, role_id)
+			SELECT u.id, r.id FROM users u JOIN roles r ON r.name = ?
+			WHERE u.id IN ?`, rbac.RoleUser, ids).Error
+		if err != nil {
+			return err
+		}
+
+		for roleName, roleIDs := range idsByRole {
+			err := tx.Exec(`
+				INSERT INTO organization_members (organization_id, user_id, role_id)
+				SELECT u.organization_id, u.id, r.id FROM users u JOIN roles r ON r.name = ?
+				WHERE u.id IN ?`, roleName, roleIDs).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if isUniqueViolation(err) {
+		return auth.ErrEmailAlreadyExists
+	}
+	return err
+}
+
+// StreamExport walks the users of the tenant with a server-side cursor, so the
+// export never holds the whole table in memory.
+func (r *userRepository) StreamExport(ctx context.Context, includePasswordHash bool, fn func(user.ExportRecord) error) error {
+	opCtx, cancel := context.WithTimeout(ctx, exportQueryTimeout)
+	defer cancel()
+
+	columns := "u.id, u.name, u.email, u.status, u.created_at, u.last_login_at, COALESCE(r.name, '') AS organization_role"
+	if includePasswordHash {
+		columns += ", u.password_hash"
+	}
+
+	rows, err := r.db.WithContext(opCtx).Raw(`
+		SELECT `+columns+`
+		FROM users u
+		LEFT JOIN organization_members om ON om.user_id = u.id AND om.organization_id = u.organization_id
+		LEFT JOIN roles r ON r.id = om.role_id
+		WHERE u.organization_id = ? AND u.deleted_at IS NULL
+		ORDER BY u.created_at, u.id`, tenant.OrganizationID(ctx)).Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record user.ExportRecord
+		if err := r.db.ScanRows(rows, &record); err != nil {
+			return err
+		}
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
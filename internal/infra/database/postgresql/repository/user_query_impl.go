@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+)
+
+var sortExpressions = map[user.SortField]string{
+	user.SortByCreatedAt:   "created_at",
+	user.SortByLastLoginAt: "COALESCE(last_login_at, 'epoch'::timestamp)",
+	user.SortByName:        "lower(name)",
+	user.SortByEmail:       "email::text",
+}
+
+func (r *userRepository) List(ctx context.Context, filter user.ListFilter) ([]user.User, error) {
+	sortExpr, ok := sortExpressions[filter.SortBy]
+	if !ok {
+		sortExpr = sortExpressions[user.SortByCreatedAt]
+	}
+	direction, comparator := "ASC", ">"
+	if filter.Descending {
+		direction, comparator = "DESC", "<"
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	query := r.scoped(opCtx).Model(&user.User{})
+
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if len(filter.Roles) > 0 {
+		query = query.Where(`EXISTS (
+			SELECT 1 FROM user_roles ur JOIN roles r ON r.id = ur.role_id
+			WHERE ur.user_id = users.id AND r.name IN ?)`, filter.Roles)
+	}
+	if filter.CreatedFrom != nil {
+		query = query.Where("created_at >= ?", filter.CreatedFrom.UTC())
+	}
+	if filter.CreatedTo != nil {
+		query = query.Where("created_at <= ?", filter.CreatedTo.UTC())
+	}
+	if filter.LastLoginFrom != nil {
+		query = query.Where("last_login_at >= ?", filter.LastLoginFrom.UTC())
+	}
+	if filter.LastLoginTo != nil {
+		query = query.Where("last_login_at <= ?", filter.LastLoginTo.UTC())
+	}
+	if search := strings.TrimSpace(filter.Search); search != "" {
+		pattern := "%" + escapeLike(search) + "%"
+		query = query.Where("(name ILIKE ? OR email::text ILIKE ?)", pattern, pattern)
+	}
+
+	if filter.After != nil {
+		value, err := cursorValue(filter.SortBy, filter.After.Value)
+		if err != nil {
+			return nil, auth.ErrInvalidCursor
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", sortExpr, comparator), value, filter.After.ID)
+	}
+
+	var users []user.User
+	err := query.
+		Order(fmt.Sprintf("%s %s, id %s", sortExpr, direction, direction)).
+		Limit(filter.Limit).
+		Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func cursorValue(field user.SortField, raw string) (interface{}, error) {
+	switch field {
+	case user.SortByName, user.SortByEmail:
+		return raw, nil
+	default:
+		return time.Parse(time.RFC3339Nano, raw)
+	}
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
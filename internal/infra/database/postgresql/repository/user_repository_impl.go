@@ -6,12 +6,18 @@ import (
 	"time"
 
 	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
 	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-const dbOpTimeout = 3 * time.Second
+const (
+	dbOpTimeout       = 3 * time.Second
+	pgUniqueViolation = "23505"
+)
 
 type userRepository struct {
 	db *gorm.DB
@@ -24,14 +30,21 @@ func NewUserRepository(db *gorm.DB) user.IRepository {
 func (r *userRepository) Create(ctx context.Context, u *user.User) error {
 	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
 	defer cancel()
-	return r.db.WithContext(opCtx).Create(u).Error
+	u.OrganizationID = tenant.OrganizationID(ctx)
+	if err := r.db.WithContext(opCtx).Create(u).Error; err != nil {
+		if isUniqueViolation(err) {
+			return auth.ErrEmailAlreadyExists
+		}
+		return err
+	}
+	return nil
 }
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*user.User, error) {
 	var u user.User
 	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
 	defer cancel()
-	if err := r.db.WithContext(opCtx).Where("email = ?", email).First(&u).Error; err != nil {
+	if err := r.scoped(opCtx).Where("email = ?", email).First(&u).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil
 		}
@@ -44,7 +57,10 @@ func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User
 	var u user.User
 	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
 	defer cancel()
-	if err := r.db.WithContext(opCtx).First(&u, "id = ?", id).Error; err != nil {
+	if err := r.scoped(opCtx).First(&u, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, auth.ErrUserNotFound
+		}
 		return nil, err
 	}
 	return &u, nil
@@ -53,12 +69,15 @@ func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*user.User
 func (r *userRepository) UpdatePasswordHash(ctx context.Context, userID uuid.UUID, newHash string) error {
 	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
 	defer cancel()
+	now := time.Now()
 	updates := map[string]interface{}{
-		"password_hash": newHash,
-		"updated_at":    time.Now(),
+		"password_hash":        newHash,
+		"password_changed_at":  now,
+		"must_change_password": false,
+		"updated_at":           now,
 	}
 
-	result := r.db.WithContext(opCtx).Model(&user.User{}).Where("id = ?", userID).Updates(updates)
+	result := r.scoped(opCtx).Model(&user.User{}).Where("id = ?", userID).Updates(updates)
 	if result.Error != nil {
 		return result.Error
 	}
@@ -75,7 +94,7 @@ func (r *userRepository) UpdateLastLoginAt(ctx context.Context, userID uuid.UUID
 	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
 	defer cancel()
 
-	result := r.db.WithContext(opCtx).
+	result := r.scoped(opCtx).
 		Model(&user.User{}).
 		Where("id = ?", userID).
 		Update("last_login_at", now)
@@ -86,15 +105,169 @@ func (r *userRepository) UpdateLastLoginAt(ctx context.Context, userID uuid.UUID
 	return nil
 }
 
-func (r *userRepository) UpdateStatus(ctx context.Context, userID uuid.UUID, status string) error {
+// ChangeStatus updates the status and records the transition in the status
+// history within the same transaction.
+func (r *userRepository) ChangeStatus(ctx context.Context, userID uuid.UUID, change user.StatusChange) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	orgID := tenant.OrganizationID(ctx)
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		var current user.User
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "status").
+			Where("organization_id = ? AND id = ?", orgID, userID).
+			First(&current).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return auth.ErrUserNotFound
+			}
+			return err
+		}
+
+		var reason *string
+		if change.Reason != "" {
+			reason = &change.Reason
+		}
+		err = tx.Model(&user.User{}).Where("id = ?", userID).Updates(map[string]interface{}{
+			"status":          change.Status,
+			"status_source":   change.Source,
+			"suspended_until": change.SuspendedUntil,
+			"status_reason":   reason,
+			"updated_at":      time.Now(),
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&user.StatusHistoryEntry{
+			ID:             uuid.New(),
+			OrganizationID: orgID,
+			UserID:         userID,
+			FromStatus:     current.Status,
+			ToStatus:       change.Status,
+			Source:         change.Source,
+			Reason:         change.Reason,
+			SuspendedUntil: change.SuspendedUntil,
+			ChangedBy:      change.ChangedBy,
+		}).Error
+	})
+}
+
+func (r *userRepository) ListStatusHistory(ctx context.Context, userID uuid.UUID) ([]user.StatusHistoryEntry, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var entries []user.StatusHistoryEntry
+	err := r.db.WithContext(opCtx).
+		Where("organization_id = ? AND user_id = ?", tenant.OrganizationID(ctx), userID).
+		Order("created_at DESC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// LiftExpiredSuspensions reactivates every user, of any organization, whose
+// suspension ended before now.
+func (r *userRepository) LiftExpiredSuspensions(ctx context.Context, now time.Time) (int64, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.db.WithContext(opCtx).Exec(`
+		WITH lifted AS (
+			UPDATE users
+			SET status = ?, status_source = ?, suspended_until = NULL, status_reason = NULL, updated_at = ?
+			WHERE status = ? AND suspended_until <= ?
+			RETURNING id, organization_id
+		)
+		INSERT INTO user_status_history (organization_id, user_id, from_status, to_status, source, reason)
+		SELECT organization_id, id, ?, ?, ?, ? FROM lifted`,
+		user.StatusActive, user.SourceSystem, now, user.StatusSuspended, now,
+		user.StatusSuspended, user.StatusActive, user.SourceSystem, user.SuspensionExpiredReason,
+	)
+	return result.RowsAffected, result.Error
+}
+
+func (r *userRepository) SetMustChangePassword(ctx context.Context, userID uuid.UUID, mustChange bool) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.scoped(opCtx).Model(&user.User{}).
+		Where("id = ?", userID).
+		Update("must_change_password", mustChange)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return auth.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) SetEmailOTPEnabled(ctx context.Context, userID uuid.UUID, enabled bool) error {
 	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
 	defer cancel()
 
-	result := r.db.WithContext(opCtx).Model(&user.User{}).
+	result := r.scoped(opCtx).Model(&user.User{}).
 		Where("id = ?", userID).
-		Update("status", status)
+		Update("email_otp_enabled", enabled)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return auth.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) UpdateProfile(ctx context.Context, u *user.User, expectedRevision *time.Time) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	updates := map[string]interface{}{
+		"name":       u.Name,
+		"locale":     u.Locale,
+		"timezone":   u.Timezone,
+		"metadata":   u.Metadata,
+		"updated_at": now,
+	}
+
+	query := r.scoped(opCtx).Model(&user.User{}).Where("id = ?", u.ID)
+	if expectedRevision != nil {
+		query = query.Where("COALESCE(updated_at, created_at) = ?", expectedRevision.UTC())
+	}
+
+	result := query.Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if expectedRevision != nil {
+			return auth.ErrProfileModified
+		}
+		return auth.ErrUserNotFound
+	}
+
+	u.UpdatedAt = &now
+	return nil
+}
+
+func (r *userRepository) UpdateEmail(ctx context.Context, userID uuid.UUID, email string) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+	updates := map[string]interface{}{
+		"email":      email,
+		"updated_at": time.Now(),
+	}
+
+	result := r.scoped(opCtx).Model(&user.User{}).Where("id = ?", userID).Updates(updates)
 
 	if result.Error != nil {
+		if isUniqueViolation(result.Error) {
+			return auth.ErrEmailAlreadyExists
+		}
 		return result.Error
 	}
 	if result.RowsAffected == 0 {
@@ -107,7 +280,7 @@ func (r *userRepository) IncrementTokenVersion(ctx context.Context, userID uuid.
 	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
 	defer cancel()
 
-	result := r.db.WithContext(opCtx).Model(&user.User{}).
+	result := r.scoped(opCtx).Model(&user.User{}).
 		Where("id = ?", userID).
 		Update("token_version", gorm.Expr("token_version + ?", 1))
 
@@ -125,7 +298,7 @@ func (r *userRepository) GetUserTokenVersion(ctx context.Context, userID string)
 	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
 	defer cancel()
 
-	result := r.db.WithContext(opCtx).
+	result := r.scoped(opCtx).
 		Model(&user.User{}).
 		Select("token_version").
 		Where("id = ?", userID).
@@ -141,3 +314,96 @@ func (r *userRepository) GetUserTokenVersion(ctx context.Context, userID string)
 
 	return version, nil
 }
+
+// scoped restricts the query to the organization the context is scoped to;
+// every user query goes through it so tenants never see each other's users.
+func (r *userRepository) scoped(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Where("users.organization_id = ?", tenant.OrganizationID(ctx))
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation
+}
+
+func (r *userRepository) ScheduleDeletion(ctx context.Context, userID uuid.UUID, at time.Time) error {
+	return r.setDeletionSchedule(ctx, userID, &at)
+}
+
+func (r *userRepository) CancelDeletion(ctx context.Context, userID uuid.UUID) error {
+	return r.setDeletionSchedule(ctx, userID, nil)
+}
+
+func (r *userRepository) setDeletionSchedule(ctx context.Context, userID uuid.UUID, at *time.Time) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	result := r.scoped(opCtx).Model(&user.User{}).
+		Where("id = ?", userID).
+		Update("deletion_scheduled_for", at)
+
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return auth.ErrUserNotFound
+	}
+	return nil
+}
+
+func (r *userRepository) FindDueDeletions(ctx context.Context, now time.Time, limit int) ([]user.User, error) {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	var users []user.User
+	err := r.db.WithContext(opCtx).
+		Select("id", "organization_id").
+		Where("deletion_scheduled_for <= ?", now).
+		Order("deletion_scheduled_for").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+func (r *userRepository) Anonymize(ctx context.Context, userID uuid.UUID) error {
+	opCtx, cancel := context.WithTimeout(ctx, dbOpTimeout)
+	defer cancel()
+
+	orgID := tenant.OrganizationID(ctx)
+	return r.db.WithContext(opCtx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		result := tx.Exec(`
+			UPDATE users SET
+			   name = 'Deleted user',
+			   email = 'deleted-' || id || '@anonymized.invalid',
+			   password_hash = '',
+			   status = ?,
+			   status_source = ?,
+			   locale = NULL,
+			   timezone = NULL,
+			   metadata = '{}'::jsonb,
+			   last_login_at = NULL,
+			   status_reason = NULL,
+			   suspended_until = NULL,
+			   deletion_scheduled_for = NULL,
+			   token_version = token_version + 1,
+			   updated_at = ?,
+			   deleted_at = ?
+			WHERE organization_id = ? AND id = ? AND deleted_at IS NULL`,
+			user.StatusInactive, user.SourceSystem, now, now, orgID, userID,
+		)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return auth.ErrUserNotFound
+		}
+
+		for _, table := range []string{"login_history", "user_status_history", "user_roles", "organization_members", "invitations", "identities", "scim_users", "scim_group_members", "personal_access_tokens"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE user_id = ?", userID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
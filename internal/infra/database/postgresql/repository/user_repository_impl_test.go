@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/tenant"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/user"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// newDryRunDB opens the postgres dialector in dry-run mode: statements are
+// built but never sent, and without the default transaction no connection
+// is opened, so no server is needed. The returned function gives
+// the SQL of the statements built so far.
+func newDryRunDB(t *testing.T) (*gorm.DB, func() []string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 dbname=test"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statements []string
+	capture := func(tx *gorm.DB) { statements = append(statements, tx.Statement.SQL.String()) }
+	if err := db.Callback().Create().After("gorm:create").Register("test:capture_create", capture); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("test:capture_update", capture); err != nil {
+		t.Fatal(err)
+	}
+	return db, func() []string { return statements }
+}
+
+// insertColumns returns the column list of an INSERT statement.
+func insertColumns(t *testing.T, statement string) string {
+	t.Helper()
+	start, end := strings.Index(statement, "("), strings.Index(statement, ") VALUES")
+	if !strings.HasPrefix(statement, "INSERT INTO") || start < 0 || end < start {
+		t.Fatalf("not an INSERT: %s", statement)
+	}
+	return statement[start+1 : end]
+}
+
+// TestCreateWithoutPasswordChangedAt guards the NOT NULL password_changed_at
+// column: a user created without the field must leave it to the database
+// default instead of inserting NULL.
+func TestCreateWithoutPasswordChangedAt(t *testing.T) {
+	db, statements := newDryRunDB(t)
+	repo := NewUserRepository(db)
+	ctx := tenant.WithOrganization(context.Background(), uuid.New())
+
+	u := &user.User{Name: "Ana", Email: "ana@example.com", Role: user.RoleUser, Status: user.StatusActive}
+	if err := repo.Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	sql := statements()
+	if len(sql) != 1 {
+		t.Fatalf("statements = %q, want one INSERT", sql)
+	}
+	if strings.Contains(insertColumns(t, sql[0]), `"password_changed_at"`) {
+		t.Errorf("INSERT sets password_changed_at without a value: %s", sql[0])
+	}
+	if !strings.Contains(sql[0], `RETURNING`) || !strings.Contains(sql[0][strings.Index(sql[0], "RETURNING"):], `"password_changed_at"`) {
+		t.Errorf("INSERT does not read back the default password_changed_at: %s", sql[0])
+	}
+}
+
+func TestCreateWithPasswordChangedAt(t *testing.T) {
+	db, statements := newDryRunDB(t)
+	repo := NewUserRepository(db)
+	ctx := tenant.WithOrganization(context.Background(), uuid.New())
+
+	changedAt := time.Now().Add(-time.Hour)
+	u := &user.User{Name: "Ana", Email: "ana@example.com", Role: user.RoleUser, Status: user.StatusActive, PasswordChangedAt: &changedAt}
+	if err := repo.Create(ctx, u); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	sql := statements()
+	if len(sql) != 1 || !strings.Contains(insertColumns(t, sql[0]), `"password_changed_at"`) {
+		t.Errorf("INSERT does not set the given password_changed_at: %q", sql)
+	}
+}
+
+// TestUpdateEmailTouchesUpdatedAt: updated_at is the revision of the profile
+// ETag, so an e-mail change must move it.
+func TestUpdateEmailTouchesUpdatedAt(t *testing.T) {
+	db, statements := newDryRunDB(t)
+	repo := NewUserRepository(db)
+	ctx := tenant.WithOrganization(context.Background(), uuid.New())
+
+	// A dry run affects no rows, so the repository reports ErrUserNotFound.
+	if err := repo.UpdateEmail(ctx, uuid.New(), "ana@example.com"); err != nil && !errors.Is(err, auth.ErrUserNotFound) {
+		t.Fatalf("UpdateEmail: %v", err)
+	}
+
+	sql := statements()
+	if len(sql) != 1 || !strings.Contains(sql[0], `"updated_at"=`) {
+		t.Errorf("UPDATE does not set updated_at: %q", sql)
+	}
+}
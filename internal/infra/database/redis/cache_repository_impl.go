@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
+	"strconv"
 	"time"
 
 	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
@@ -21,6 +22,7 @@ const (
 	resetKeyPrefix    = "auth:reset:"
 	refreshKeyPrefix  = "auth:refresh:"
 	tokenVerKeyPrefix = "auth:token_version:"
+	sessionsKeyPrefix = "auth:sessions:"
 )
 
 func NewCacheRepository(client *redis.Client) auth.ICacheRepository {
@@ -73,14 +75,22 @@ func (r *cacheRepository) VerifyAndConsumeResetToken(ctx context.Context, resetT
 }
 
 func (r *cacheRepository) SaveRefreshToken(ctx context.Context, userID string, refreshToken string, ttl time.Duration) error {
-	key := refreshKeyPrefix + hashToken(refreshToken)
+	tokenHash := hashToken(refreshToken)
+	sessionsKey := sessionsKeyPrefix + userID
 	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
 	defer cancel()
-	return r.client.Set(opCtx, key, userID, ttl).Err()
+	_, err := r.client.TxPipelined(opCtx, func(pipe redis.Pipeliner) error {
+		pipe.Set(opCtx, refreshKeyPrefix+tokenHash, userID, ttl)
+		pipe.ZAdd(opCtx, sessionsKey, redis.Z{Score: float64(time.Now().Add(ttl).Unix()), Member: tokenHash})
+		pipe.Expire(opCtx, sessionsKey, ttl)
+		return nil
+	})
+	return err
 }
 
 func (r *cacheRepository) VerifyAndConsumeRefreshToken(ctx context.Context, refreshToken string) (string, error) {
-	key := refreshKeyPrefix + hashToken(refreshToken)
+	tokenHash := hashToken(refreshToken)
+	key := refreshKeyPrefix + tokenHash
 	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
 	defer cancel()
 	cmd := r.client.GetDel(opCtx, key)
@@ -96,9 +106,43 @@ func (r *cacheRepository) VerifyAndConsumeRefreshToken(ctx context.Context, refr
 		return "", errors.New("refresh token is invalid or expired")
 	}
 
+	r.client.ZRem(opCtx, sessionsKeyPrefix+userID, tokenHash)
+
 	return userID, nil
 }
 
+// CountUserSessions counts the refresh tokens still valid for the user,
+// pruning the expired entries of the session index first.
+func (r *cacheRepository) CountUserSessions(ctx context.Context, userID string) (int64, error) {
+	key := sessionsKeyPrefix + userID
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	if err := r.client.ZRemRangeByScore(opCtx, key, "-inf", strconv.FormatInt(time.Now().Unix(), 10)).Err(); err != nil {
+		return 0, err
+	}
+	return r.client.ZCard(opCtx, key).Result()
+}
+
+// RevokeUserSessions drops the cached token version, so the AuthMiddleware
+// reads the bumped version from the database, and every stored refresh token
+// of the user.
+func (r *cacheRepository) RevokeUserSessions(ctx context.Context, userID string) error {
+	sessionsKey := sessionsKeyPrefix + userID
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	tokenHashes, err := r.client.ZRange(opCtx, sessionsKey, 0, -1).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{tokenVerKeyPrefix + userID, sessionsKey}
+	for _, tokenHash := range tokenHashes {
+		keys = append(keys, refreshKeyPrefix+tokenHash)
+	}
+	return r.client.Del(opCtx, keys...).Err()
+}
+
 func hashToken(token string) string {
 	sum := sha256.Sum256([]byte(token))
 	return hex.EncodeToString(sum[:])
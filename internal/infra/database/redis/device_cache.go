@@ -0,0 +1,161 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	deviceKeyPrefix   = "auth:device:"
+	userCodeKeyPrefix = "auth:device_user_code:"
+)
+
+// decideDeviceLua releases the user code and records the first decision of a
+// grant that still exists.
+const decideDeviceLua = `
+local device = redis.call("GET", KEYS[1])
+if not device then
+  return 0
+end
+redis.call("DEL", KEYS[1])
+local key = ARGV[1] .. device
+if redis.call("EXISTS", key) == 0 then
+  return 0
+end
+return redis.call("HSETNX", key, "decision", ARGV[2])
+`
+
+// pollDeviceLua applies the polling interval and consumes decided grants.
+// ARGV: client ID, now in milliseconds, slow down step in seconds.
+const pollDeviceLua = `
+if redis.call("EXISTS", KEYS[1]) == 0 then
+  return {"expired"}
+end
+if redis.call("HGET", KEYS[1], "client_id") ~= ARGV[1] then
+  return {"invalid_client"}
+end
+local now = tonumber(ARGV[2])
+local interval = tonumber(redis.call("HGET", KEYS[1], "interval"))
+local last = tonumber(redis.call("HGET", KEYS[1], "last_poll"))
+redis.call("HSET", KEYS[1], "last_poll", now)
+if last > 0 and now - last < interval * 1000 then
+  redis.call("HSET", KEYS[1], "interval", interval + tonumber(ARGV[3]))
+  return {"slow_down"}
+end
+local decision = redis.call("HGET", KEYS[1], "decision")
+if not decision then
+  return {"pending"}
+end
+redis.call("DEL", KEYS[1])
+return {"decided", decision}
+`
+
+var errUnexpectedDevicePoll = errors.New("unexpected device poll result")
+
+func (r *cacheRepository) SaveDeviceAuthorization(ctx context.Context, deviceCode string, ticket auth.DeviceAuthorizationTicket, interval time.Duration, ttl time.Duration) (bool, error) {
+	payload, err := json.Marshal(ticket)
+	if err != nil {
+		return false, err
+	}
+
+	deviceHash := hashToken(deviceCode)
+	key := deviceKeyPrefix + deviceHash
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	reserved, err := r.client.SetNX(opCtx, userCodeKeyPrefix+hashToken(ticket.UserCode), deviceHash, ttl).Result()
+	if err != nil || !reserved {
+		return false, err
+	}
+	_, err = r.client.TxPipelined(opCtx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(opCtx, key,
+			"ticket", payload,
+			"client_id", ticket.ClientID,
+			"interval", int64(interval/time.Second),
+			"last_poll", 0,
+		)
+		pipe.Expire(opCtx, key, ttl)
+		return nil
+	})
+	return err == nil, err
+}
+
+func (r *cacheRepository) FindDeviceAuthorization(ctx context.Context, userCode string) (*auth.DeviceAuthorizationTicket, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	deviceHash, err := r.client.Get(opCtx, userCodeKeyPrefix+hashToken(userCode)).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, auth.ErrInvalidUserCode
+		}
+		return nil, err
+	}
+	payload, err := r.client.HGet(opCtx, deviceKeyPrefix+deviceHash, "ticket").Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, auth.ErrInvalidUserCode
+		}
+		return nil, err
+	}
+
+	var ticket auth.DeviceAuthorizationTicket
+	if err := json.Unmarshal(payload, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
+
+func (r *cacheRepository) DecideDeviceAuthorization(ctx context.Context, userCode string, decision auth.DeviceDecision) error {
+	payload, err := json.Marshal(decision)
+	if err != nil {
+		return err
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	recorded, err := r.client.Eval(opCtx, decideDeviceLua, []string{userCodeKeyPrefix + hashToken(userCode)}, deviceKeyPrefix, payload).Int()
+	if err != nil {
+		return err
+	}
+	if recorded == 0 {
+		return auth.ErrInvalidUserCode
+	}
+	return nil
+}
+
+func (r *cacheRepository) PollDeviceAuthorization(ctx context.Context, deviceCode string, clientID string, slowDownStep time.Duration) (*auth.DeviceDecision, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	args := []interface{}{clientID, strconv.FormatInt(time.Now().UnixMilli(), 10), int64(slowDownStep / time.Second)}
+	result, err := r.client.Eval(opCtx, pollDeviceLua, []string{deviceKeyPrefix + hashToken(deviceCode)}, args...).StringSlice()
+	if err != nil {
+		return nil, err
+	}
+
+	switch result[0] {
+	case "expired":
+		return nil, auth.ErrDeviceCodeExpired
+	case "invalid_client":
+		return nil, auth.ErrUnknownClient
+	case "slow_down":
+		return nil, auth.ErrSlowDown
+	case "pending":
+		return nil, auth.ErrAuthorizationPending
+	case "decided":
+		var decision auth.DeviceDecision
+		if err := json.Unmarshal([]byte(result[1]), &decision); err != nil {
+			return nil, err
+		}
+		return &decision, nil
+	}
+	return nil, errUnexpectedDevicePoll
+}
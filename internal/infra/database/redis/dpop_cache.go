@@ -0,0 +1,14 @@
+package redis
+
+import (
+	"context"
+	"time"
+)
+
+const dpopProofKeyPrefix = "auth:dpop_jti:"
+
+func (r *cacheRepository) ClaimDPoPProof(ctx context.Context, jkt string, jti string, ttl time.Duration) (bool, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.SetNX(opCtx, dpopProofKeyPrefix+hashToken(jkt+"\x00"+jti), 1, ttl).Result()
+}
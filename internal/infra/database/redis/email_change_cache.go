@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	emailChangeKeyPrefix  = "auth:email_change:"
+	emailRevertKeyPrefix  = "auth:email_revert:"
+	emailPendingKeyPrefix = "auth:email_change_pending:"
+)
+
+func (r *cacheRepository) SaveEmailChangeTicket(ctx context.Context, confirmToken string, revertToken string, ticket auth.EmailChangeTicket, confirmTTL time.Duration, revertTTL time.Duration) error {
+	payload, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	_, err = r.client.TxPipelined(opCtx, func(pipe redis.Pipeliner) error {
+		pipe.Set(opCtx, emailChangeKeyPrefix+hashToken(confirmToken), payload, confirmTTL)
+		pipe.Set(opCtx, emailRevertKeyPrefix+hashToken(revertToken), payload, revertTTL)
+		pipe.Set(opCtx, emailPendingKeyPrefix+ticket.UserID, ticket.ID, confirmTTL)
+		return nil
+	})
+	return err
+}
+
+func (r *cacheRepository) VerifyAndConsumeEmailChangeToken(ctx context.Context, confirmToken string) (*auth.EmailChangeTicket, error) {
+	return r.consumeEmailTicket(ctx, emailChangeKeyPrefix+hashToken(confirmToken))
+}
+
+func (r *cacheRepository) VerifyAndConsumeEmailRevertToken(ctx context.Context, revertToken string) (*auth.EmailChangeTicket, error) {
+	return r.consumeEmailTicket(ctx, emailRevertKeyPrefix+hashToken(revertToken))
+}
+
+func (r *cacheRepository) ConsumePendingEmailChange(ctx context.Context, userID string) (string, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	ticketID, err := r.client.GetDel(opCtx, emailPendingKeyPrefix+userID).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", nil
+		}
+		return "", err
+	}
+	return ticketID, nil
+}
+
+func (r *cacheRepository) consumeEmailTicket(ctx context.Context, key string) (*auth.EmailChangeTicket, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	payload, err := r.client.GetDel(opCtx, key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New("email change token is invalid or expired")
+		}
+		return nil, err
+	}
+
+	var ticket auth.EmailChangeTicket
+	if err := json.Unmarshal(payload, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/redis/go-redis/v9"
+)
+
+const federationStateKeyPrefix = "auth:oidc_state:"
+
+func (r *cacheRepository) SaveFederationState(ctx context.Context, state string, loginState federation.LoginState, ttl time.Duration) error {
+	payload, err := json.Marshal(loginState)
+	if err != nil {
+		return err
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Set(opCtx, federationStateKeyPrefix+hashToken(state), payload, ttl).Err()
+}
+
+func (r *cacheRepository) ConsumeFederationState(ctx context.Context, state string) (*federation.LoginState, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	payload, err := r.client.GetDel(opCtx, federationStateKeyPrefix+hashToken(state)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, federation.ErrInvalidState
+		}
+		return nil, err
+	}
+
+	var loginState federation.LoginState
+	if err := json.Unmarshal(payload, &loginState); err != nil {
+		return nil, err
+	}
+	return &loginState, nil
+}
@@ -0,0 +1,78 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	failedLoginKeyPrefix = "auth:failed_login:"
+	lockoutKeyPrefix     = "auth:lockout:"
+)
+
+func (r *cacheRepository) RegisterFailedLogin(ctx context.Context, userID string, window time.Duration) (int, error) {
+	key := failedLoginKeyPrefix + userID
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	attempts, err := r.client.Incr(opCtx, key).Result()
+	if err != nil {
+		return 0, err
+	}
+	if attempts == 1 {
+		if err := r.client.Expire(opCtx, key, window).Err(); err != nil {
+			return 0, err
+		}
+	}
+	return int(attempts), nil
+}
+
+func (r *cacheRepository) ClearFailedLogins(ctx context.Context, userID string) error {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Del(opCtx, failedLoginKeyPrefix+userID).Err()
+}
+
+func (r *cacheRepository) GetFailedLogins(ctx context.Context, userID string) (int, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	attempts, err := r.client.Get(opCtx, failedLoginKeyPrefix+userID).Int()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return attempts, nil
+}
+
+func (r *cacheRepository) LockAccount(ctx context.Context, userID string, ttl time.Duration) error {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Set(opCtx, lockoutKeyPrefix+userID, "1", ttl).Err()
+}
+
+// UnlockAccount lifts the lock and resets the failed login counter.
+func (r *cacheRepository) UnlockAccount(ctx context.Context, userID string) error {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Del(opCtx, lockoutKeyPrefix+userID, failedLoginKeyPrefix+userID).Err()
+}
+
+// GetAccountLock returns how long the account stays locked; zero means it is
+// not locked.
+func (r *cacheRepository) GetAccountLock(ctx context.Context, userID string) (time.Duration, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	ttl, err := r.client.PTTL(opCtx, lockoutKeyPrefix+userID).Result()
+	if err != nil {
+		return 0, err
+	}
+	if ttl < 0 {
+		return 0, nil
+	}
+	return ttl, nil
+}
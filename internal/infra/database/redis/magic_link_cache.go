@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/redis/go-redis/v9"
+)
+
+const magicLinkKeyPrefix = "auth:magic_link:"
+
+func (r *cacheRepository) SaveMagicLink(ctx context.Context, token string, ticket auth.MagicLinkTicket, ttl time.Duration) error {
+	payload, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Set(opCtx, magicLinkKeyPrefix+hashToken(token), payload, ttl).Err()
+}
+
+func (r *cacheRepository) VerifyAndConsumeMagicLink(ctx context.Context, token string) (*auth.MagicLinkTicket, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	payload, err := r.client.GetDel(opCtx, magicLinkKeyPrefix+hashToken(token)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, errors.New("magic link is invalid or expired")
+		}
+		return nil, err
+	}
+
+	var ticket auth.MagicLinkTicket
+	if err := json.Unmarshal(payload, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
@@ -0,0 +1,90 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	otpChallengeKeyPrefix = "auth:otp:"
+	stepUpKeyPrefix       = "auth:step_up:"
+)
+
+// attemptOTPLua increments the attempts of an existing challenge only, so an
+// expired challenge is not recreated without a TTL.
+const attemptOTPLua = `
+if redis.call("EXISTS", KEYS[1]) == 0 then
+  return false
+end
+local attempts = redis.call("HINCRBY", KEYS[1], "attempts", 1)
+return {redis.call("HGET", KEYS[1], "challenge"), attempts}
+`
+
+var errInvalidOTPChallenge = errors.New("one-time code challenge is invalid or expired")
+
+func (r *cacheRepository) SaveOTPChallenge(ctx context.Context, challengeID string, challenge auth.OTPChallenge, ttl time.Duration) error {
+	payload, err := json.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+
+	key := otpChallengeKeyPrefix + hashToken(challengeID)
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	_, err = r.client.TxPipelined(opCtx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(opCtx, key, "challenge", payload, "attempts", 0)
+		pipe.Expire(opCtx, key, ttl)
+		return nil
+	})
+	return err
+}
+
+func (r *cacheRepository) AttemptOTPChallenge(ctx context.Context, challengeID string) (*auth.OTPChallenge, int, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	result, err := r.client.Eval(opCtx, attemptOTPLua, []string{otpChallengeKeyPrefix + hashToken(challengeID)}).Slice()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, 0, errInvalidOTPChallenge
+		}
+		return nil, 0, err
+	}
+
+	payload, _ := result[0].(string)
+	attempts, _ := result[1].(int64)
+
+	var challenge auth.OTPChallenge
+	if err := json.Unmarshal([]byte(payload), &challenge); err != nil {
+		return nil, 0, err
+	}
+	return &challenge, int(attempts), nil
+}
+
+func (r *cacheRepository) DeleteOTPChallenge(ctx context.Context, challengeID string) error {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Del(opCtx, otpChallengeKeyPrefix+hashToken(challengeID)).Err()
+}
+
+func (r *cacheRepository) SaveStepUpToken(ctx context.Context, userID string, stepUpToken string, ttl time.Duration) error {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Set(opCtx, stepUpKeyPrefix+hashToken(stepUpToken), userID, ttl).Err()
+}
+
+func (r *cacheRepository) VerifyAndConsumeStepUpToken(ctx context.Context, stepUpToken string) (string, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	userID, err := r.client.GetDel(opCtx, stepUpKeyPrefix+hashToken(stepUpToken)).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && userID == "") {
+		return "", errors.New("step-up token is invalid or expired")
+	}
+	return userID, err
+}
@@ -0,0 +1,19 @@
+package redis
+
+import (
+	"context"
+)
+
+func (r *cacheRepository) PurgeUser(ctx context.Context, userID string) error {
+	if err := r.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Del(opCtx,
+		failedLoginKeyPrefix+userID,
+		lockoutKeyPrefix+userID,
+		emailPendingKeyPrefix+userID,
+	).Err()
+}
@@ -0,0 +1,30 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const reactivationKeyPrefix = "auth:reactivation:"
+
+var errInvalidReactivationToken = errors.New("reactivation token is invalid or expired")
+
+func (r *cacheRepository) SaveReactivationToken(ctx context.Context, subject string, reactivationToken string, ttl time.Duration) error {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Set(opCtx, reactivationKeyPrefix+hashToken(reactivationToken), subject, ttl).Err()
+}
+
+func (r *cacheRepository) VerifyAndConsumeReactivationToken(ctx context.Context, reactivationToken string) (string, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	subject, err := r.client.GetDel(opCtx, reactivationKeyPrefix+hashToken(reactivationToken)).Result()
+	if errors.Is(err, redis.Nil) || (err == nil && subject == "") {
+		return "", errInvalidReactivationToken
+	}
+	return subject, err
+}
@@ -0,0 +1,84 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/auth"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/saml"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	samlRequestKeyPrefix   = "auth:saml_request:"
+	samlAssertionKeyPrefix = "auth:saml_assertion:"
+	samlLoginKeyPrefix     = "auth:saml_login:"
+)
+
+func (r *cacheRepository) SaveSAMLRequest(ctx context.Context, relayState string, request saml.PendingRequest, ttl time.Duration) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Set(opCtx, samlRequestKeyPrefix+hashToken(relayState), payload, ttl).Err()
+}
+
+func (r *cacheRepository) ConsumeSAMLRequest(ctx context.Context, relayState string) (*saml.PendingRequest, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	payload, err := r.client.GetDel(opCtx, samlRequestKeyPrefix+hashToken(relayState)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, saml.ErrInvalidRelayState
+		}
+		return nil, err
+	}
+
+	var request saml.PendingRequest
+	if err := json.Unmarshal(payload, &request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *cacheRepository) ClaimSAMLAssertion(ctx context.Context, issuer string, assertionID string, ttl time.Duration) (bool, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.SetNX(opCtx, samlAssertionKeyPrefix+hashToken(issuer+"\x00"+assertionID), 1, ttl).Result()
+}
+
+func (r *cacheRepository) SaveSAMLLogin(ctx context.Context, code string, ticket auth.SAMLLoginTicket, ttl time.Duration) error {
+	payload, err := json.Marshal(ticket)
+	if err != nil {
+		return err
+	}
+
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+	return r.client.Set(opCtx, samlLoginKeyPrefix+hashToken(code), payload, ttl).Err()
+}
+
+func (r *cacheRepository) ConsumeSAMLLogin(ctx context.Context, code string) (*auth.SAMLLoginTicket, error) {
+	opCtx, cancel := context.WithTimeout(ctx, cacheOpTimeout)
+	defer cancel()
+
+	payload, err := r.client.GetDel(opCtx, samlLoginKeyPrefix+hashToken(code)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, saml.ErrInvalidLoginCode
+		}
+		return nil, err
+	}
+
+	var ticket auth.SAMLLoginTicket
+	if err := json.Unmarshal(payload, &ticket); err != nil {
+		return nil, err
+	}
+	return &ticket, nil
+}
@@ -0,0 +1,209 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+const defaultTimeout = 5 * time.Second
+
+// Config is an LDAP or Active Directory server and how users are found in
+// it.
+type Config struct {
+	directory.Settings
+
+	URL                string `json:"url"`
+	StartTLS           bool   `json:"start_tls"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"`
+	TimeoutSec         int    `json:"timeout_sec"`
+
+	// BindDN and BindPassword are the service account used to search the
+	// entry of a login. Empty searches anonymously.
+	BindDN       string `json:"bind_dn"`
+	BindPassword string `json:"bind_password"`
+
+	BaseDN string `json:"base_dn"`
+	// UserFilter finds the entry of a login; %s is replaced by the escaped
+	// login, e.g. (&(objectClass=user)(userPrincipalName=%s)) on AD.
+	UserFilter string `json:"user_filter"`
+	// DisabledFilter matches the disabled entries, which neither sign in nor
+	// keep signing in by other means, e.g.
+	// (userAccountControl:1.2.840.113556.1.4.803:=2) on AD. Empty relies on
+	// the server refusing their binds.
+	DisabledFilter string `json:"disabled_filter"`
+
+	IDAttribute    string `json:"id_attribute"`
+	EmailAttribute string `json:"email_attribute"`
+	NameAttribute  string `json:"name_attribute"`
+	GroupAttribute string `json:"group_attribute"`
+}
+
+// Backend binds as the user to check its password. Each authentication uses
+// its own connection, so a broken connection never outlives a login.
+type Backend struct {
+	cfg Config
+}
+
+func NewBackend(cfg Config) directory.IBackend {
+	return &Backend{cfg: cfg}
+}
+
+func (b *Backend) Settings() directory.Settings {
+	return b.cfg.Settings
+}
+
+func (b *Backend) Authenticate(ctx context.Context, login string, password string) (*directory.Entry, error) {
+	// An empty password would be an unauthenticated bind, which servers
+	// accept without checking anything.
+	if login == "" || password == "" {
+		return nil, directory.ErrInvalidCredentials
+	}
+
+	conn, err := b.dial(ctx)
+	if err != nil {
+		return nil, b.unavailable(err)
+	}
+	defer conn.Close()
+
+	if b.cfg.BindDN != "" {
+		if err := conn.Bind(b.cfg.BindDN, b.cfg.BindPassword); err != nil {
+			return nil, b.unavailable(err)
+		}
+	}
+
+	found, err := b.findEntry(conn, login)
+	if err != nil {
+		if errors.Is(err, directory.ErrEntryNotFound) {
+			return nil, directory.ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	if err := conn.Bind(found.DN, password); err != nil {
+		if goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+			return nil, directory.ErrInvalidCredentials
+		}
+		return nil, b.unavailable(err)
+	}
+
+	return b.toEntry(found), nil
+}
+
+func (b *Backend) Lookup(ctx context.Context, login string) (*directory.Entry, error) {
+	if login == "" {
+		return nil, directory.ErrEntryNotFound
+	}
+
+	conn, err := b.dial(ctx)
+	if err != nil {
+		return nil, b.unavailable(err)
+	}
+	defer conn.Close()
+
+	if b.cfg.BindDN != "" {
+		if err := conn.Bind(b.cfg.BindDN, b.cfg.BindPassword); err != nil {
+			return nil, b.unavailable(err)
+		}
+	}
+
+	found, err := b.findEntry(conn, login)
+	if err != nil {
+		return nil, err
+	}
+	return b.toEntry(found), nil
+}
+
+func (b *Backend) dial(ctx context.Context) (*goldap.Conn, error) {
+	timeout := defaultTimeout
+	if b.cfg.TimeoutSec > 0 {
+		timeout = time.Duration(b.cfg.TimeoutSec) * time.Second
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < timeout {
+		timeout = time.Until(deadline)
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: b.cfg.InsecureSkipVerify}
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := goldap.DialURL(b.cfg.URL, goldap.DialWithDialer(dialer), goldap.DialWithTLSConfig(tlsConfig))
+	if err != nil {
+		return nil, err
+	}
+	conn.SetTimeout(timeout)
+
+	if b.cfg.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+func (b *Backend) findEntry(conn *goldap.Conn, login string) (*goldap.Entry, error) {
+	attributes := []string{b.cfg.IDAttribute, b.cfg.EmailAttribute, b.cfg.NameAttribute}
+	if b.cfg.GroupAttribute != "" {
+		attributes = append(attributes, b.cfg.GroupAttribute)
+	}
+
+	filter := strings.ReplaceAll(b.cfg.UserFilter, "%s", goldap.EscapeFilter(login))
+	if b.cfg.DisabledFilter != "" {
+		filter = "(&" + filter + "(!" + b.cfg.DisabledFilter + "))"
+	}
+
+	request := goldap.NewSearchRequest(
+		b.cfg.BaseDN,
+		goldap.ScopeWholeSubtree, goldap.NeverDerefAliases,
+		2, 0, false,
+		filter,
+		attributes,
+		nil,
+	)
+
+	result, err := conn.Search(request)
+	if err != nil && !goldap.IsErrorWithCode(err, goldap.LDAPResultSizeLimitExceeded) {
+		return nil, b.unavailable(err)
+	}
+	switch {
+	case result == nil || len(result.Entries) == 0:
+		return nil, directory.ErrEntryNotFound
+	case len(result.Entries) > 1:
+		log.Printf("[WARN] Directory %s has more than one entry for login %s", b.cfg.Name, login)
+		return nil, directory.ErrEntryNotFound
+	}
+	return result.Entries[0], nil
+}
+
+func (b *Backend) toEntry(found *goldap.Entry) *directory.Entry {
+	entry := &directory.Entry{
+		ID:    found.DN,
+		DN:    found.DN,
+		Email: found.GetAttributeValue(b.cfg.EmailAttribute),
+		Name:  found.GetAttributeValue(b.cfg.NameAttribute),
+	}
+	if raw := found.GetRawAttributeValue(b.cfg.IDAttribute); len(raw) > 0 {
+		// objectGUID is binary; entryUUID and similar are text.
+		if strings.EqualFold(b.cfg.IDAttribute, "objectGUID") {
+			entry.ID = hex.EncodeToString(raw)
+		} else {
+			entry.ID = string(raw)
+		}
+	}
+	if b.cfg.GroupAttribute != "" {
+		entry.Groups = found.GetAttributeValues(b.cfg.GroupAttribute)
+	}
+	return entry
+}
+
+func (b *Backend) unavailable(err error) error {
+	return fmt.Errorf("%w: %s: %v", directory.ErrUnavailable, b.cfg.Name, err)
+}
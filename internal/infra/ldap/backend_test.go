@@ -0,0 +1,128 @@
+package ldap
+
+import (
+	"context"
+	"errors"
+	"net"
+	"slices"
+	"testing"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+	"github.com/felipedenardo/chameleon-auth-api/internal/infra/ldap/ldaptest"
+)
+
+// carol is disabled in the directory but still has a password, as happens
+// when the server does not refuse the binds of disabled accounts.
+var carol = ldaptest.Entry{DN: "uid=carol,ou=people,dc=example,dc=com", Password: "carol-secret", Attributes: map[string][]string{
+	"objectClass":   {"person", "inetOrgPerson"},
+	"uid":           {"carol"},
+	"cn":            {"Carol Example"},
+	"mail":          {"carol@example.com"},
+	"entryUUID":     {"9a1b2c3d-4e5f-4a6b-8c7d-9e0f1a2b3c4d"},
+	"accountStatus": {"disabled"},
+}}
+
+// newTestBackend starts an LDAP server with the default entries and carol
+// and returns a backend pointed at it.
+func newTestBackend(t *testing.T) *Backend {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	go ldaptest.NewServer(append(slices.Clone(ldaptest.DefaultEntries), carol)).Serve(listener)
+
+	return &Backend{cfg: Config{
+		Settings:       directory.Settings{Name: "test"},
+		URL:            "ldap://" + listener.Addr().String(),
+		BindDN:         "cn=admin,dc=example,dc=com",
+		BindPassword:   "admin",
+		BaseDN:         "dc=example,dc=com",
+		UserFilter:     "(&(objectClass=person)(mail=%s))",
+		DisabledFilter: "(accountStatus=disabled)",
+		IDAttribute:    "entryUUID",
+		EmailAttribute: "mail",
+		NameAttribute:  "cn",
+		GroupAttribute: "memberOf",
+	}}
+}
+
+func TestAuthenticate(t *testing.T) {
+	backend := newTestBackend(t)
+
+	entry, err := backend.Authenticate(context.Background(), "alice@example.com", "alice-secret")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if entry.ID != "5f0c2b8e-4f1e-4a4b-9f0d-8c1d2e3f4a51" || entry.DN != "uid=alice,ou=people,dc=example,dc=com" {
+		t.Errorf("entry = %+v, want alice", entry)
+	}
+	if entry.Email != "alice@example.com" || entry.Name != "Alice Example" {
+		t.Errorf("entry = %+v, want the mail and cn of alice", entry)
+	}
+	if !slices.Equal(entry.Groups, []string{"cn=admins,ou=groups,dc=example,dc=com"}) {
+		t.Errorf("groups = %v, want admins", entry.Groups)
+	}
+}
+
+func TestAuthenticateRejectsInvalidCredentials(t *testing.T) {
+	backend := newTestBackend(t)
+
+	tests := []struct {
+		name     string
+		login    string
+		password string
+	}{
+		{name: "wrong password", login: "alice@example.com", password: "bob-secret"},
+		{name: "empty password", login: "alice@example.com", password: ""},
+		{name: "unknown login", login: "mallory@example.com", password: "alice-secret"},
+		{name: "disabled account", login: "carol@example.com", password: "carol-secret"},
+		// Unescaped, these would match alice through a substring or an
+		// injected clause.
+		{name: "wildcard", login: "a*", password: "alice-secret"},
+		{name: "injected clause", login: "*)(uid=alice", password: "alice-secret"},
+		{name: "injected or", login: "x)(|(mail=alice@example.com)", password: "alice-secret"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := backend.Authenticate(context.Background(), tt.login, tt.password)
+			if !errors.Is(err, directory.ErrInvalidCredentials) {
+				t.Fatalf("Authenticate error = %v, want ErrInvalidCredentials", err)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	backend := newTestBackend(t)
+
+	entry, err := backend.Lookup(context.Background(), "bob@example.com")
+	if err != nil {
+		t.Fatalf("Lookup: %v", err)
+	}
+	if entry.Email != "bob@example.com" {
+		t.Errorf("entry = %+v, want bob", entry)
+	}
+
+	for _, login := range []string{"carol@example.com", "mallory@example.com", "*", "b*"} {
+		if _, err := backend.Lookup(context.Background(), login); !errors.Is(err, directory.ErrEntryNotFound) {
+			t.Errorf("Lookup(%q) error = %v, want ErrEntryNotFound", login, err)
+		}
+	}
+}
+
+func TestUnavailableDirectory(t *testing.T) {
+	backend := newTestBackend(t)
+	backend.cfg.BindPassword = "wrong"
+
+	if _, err := backend.Authenticate(context.Background(), "alice@example.com", "alice-secret"); !errors.Is(err, directory.ErrUnavailable) {
+		t.Errorf("Authenticate with a bad service account error = %v, want ErrUnavailable", err)
+	}
+
+	backend.cfg.URL = "ldap://127.0.0.1:1"
+	if _, err := backend.Lookup(context.Background(), "alice@example.com"); !errors.Is(err, directory.ErrUnavailable) {
+		t.Errorf("Lookup without a server error = %v, want ErrUnavailable", err)
+	}
+}
@@ -0,0 +1,75 @@
+package ldap
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/directory"
+)
+
+var backendNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// backendFile is a backend entry of LDAP_BACKENDS_FILE. The service account
+// password may be read from the environment variable named by
+// bind_password_env, so the file can be committed.
+type backendFile struct {
+	Config
+	BindPasswordEnv string `json:"bind_password_env"`
+}
+
+// LoadBackends reads the JSON array of backends at path. An empty path
+// disables directory authentication.
+func LoadBackends(path string) ([]directory.IBackend, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []backendFile
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	names := map[string]bool{}
+	backends := make([]directory.IBackend, 0, len(entries))
+	for i, entry := range entries {
+		cfg := entry.Config
+		if !backendNamePattern.MatchString(cfg.Name) {
+			return nil, fmt.Errorf("%s: backend %d: invalid name %q", path, i, cfg.Name)
+		}
+		if names[cfg.Name] {
+			return nil, fmt.Errorf("%s: backend %q repeated", path, cfg.Name)
+		}
+		names[cfg.Name] = true
+		if cfg.URL == "" || cfg.BaseDN == "" {
+			return nil, fmt.Errorf("%s: backend %q: url and base_dn are required", path, cfg.Name)
+		}
+
+		if cfg.BindPassword == "" && entry.BindPasswordEnv != "" {
+			cfg.BindPassword = os.Getenv(entry.BindPasswordEnv)
+		}
+		if cfg.UserFilter == "" {
+			cfg.UserFilter = "(&(objectClass=person)(mail=%s))"
+		}
+		if cfg.IDAttribute == "" {
+			cfg.IDAttribute = "entryUUID"
+		}
+		if cfg.EmailAttribute == "" {
+			cfg.EmailAttribute = "mail"
+		}
+		if cfg.NameAttribute == "" {
+			cfg.NameAttribute = "cn"
+		}
+		if cfg.GroupAttribute == "" {
+			cfg.GroupAttribute = "memberOf"
+		}
+		backends = append(backends, NewBackend(cfg))
+	}
+	return backends, nil
+}
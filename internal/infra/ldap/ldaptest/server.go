@@ -0,0 +1,262 @@
+// Package ldaptest is a minimal in-memory LDAP server, used by the LDAP
+// backend tests and by cmd/ldap-stub. It answers simple binds and searches
+// with equality, presence and substring filters; everything else is
+// rejected.
+package ldaptest
+
+import (
+	"errors"
+	"log"
+	"net"
+	"slices"
+	"strings"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+// Entry is an entry of the directory. Password is the one accepted by a bind
+// as DN; empty refuses every bind.
+type Entry struct {
+	DN         string              `json:"dn"`
+	Password   string              `json:"password"`
+	Attributes map[string][]string `json:"attributes"`
+}
+
+func (e Entry) values(attribute string) []string {
+	for name, values := range e.Attributes {
+		if strings.EqualFold(name, attribute) {
+			return values
+		}
+	}
+	return nil
+}
+
+// DefaultEntries is dc=example,dc=com with the service account
+// cn=admin,dc=example,dc=com (password admin) and the users alice@example.com
+// (alice-secret, group admins) and bob@example.com (bob-secret, group staff).
+var DefaultEntries = []Entry{
+	{DN: "cn=admin,dc=example,dc=com", Password: "admin", Attributes: map[string][]string{
+		"objectClass": {"organizationalRole"},
+		"cn":          {"admin"},
+	}},
+	{DN: "uid=alice,ou=people,dc=example,dc=com", Password: "alice-secret", Attributes: map[string][]string{
+		"objectClass": {"person", "inetOrgPerson"},
+		"uid":         {"alice"},
+		"cn":          {"Alice Example"},
+		"mail":        {"alice@example.com"},
+		"entryUUID":   {"5f0c2b8e-4f1e-4a4b-9f0d-8c1d2e3f4a51"},
+		"memberOf":    {"cn=admins,ou=groups,dc=example,dc=com"},
+	}},
+	{DN: "uid=bob,ou=people,dc=example,dc=com", Password: "bob-secret", Attributes: map[string][]string{
+		"objectClass": {"person", "inetOrgPerson"},
+		"uid":         {"bob"},
+		"cn":          {"Bob Example"},
+		"mail":        {"bob@example.com"},
+		"entryUUID":   {"0b6f3c1a-7d2e-4c5f-8a9b-1c2d3e4f5a62"},
+		"memberOf":    {"cn=staff,ou=groups,dc=example,dc=com"},
+	}},
+}
+
+// Server serves a fixed set of entries.
+type Server struct {
+	entries []Entry
+}
+
+func NewServer(entries []Entry) *Server {
+	return &Server{entries: entries}
+}
+
+// Serve answers the connections of listener until it is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return err
+		}
+		if err != nil {
+			log.Printf("[ERROR] Accept failed: %v", err)
+			continue
+		}
+		go serve(conn, s.entries)
+	}
+}
+
+func serve(conn net.Conn, entries []Entry) {
+	defer conn.Close()
+
+	for {
+		request, err := ber.ReadPacket(conn)
+		if err != nil || len(request.Children) < 2 {
+			return
+		}
+		messageID, _ := request.Children[0].Value.(int64)
+		op := request.Children[1]
+
+		switch op.Tag {
+		case goldap.ApplicationBindRequest:
+			write(conn, messageID, bind(op, entries))
+		case goldap.ApplicationSearchRequest:
+			for _, response := range search(op, entries) {
+				write(conn, messageID, response)
+			}
+		case goldap.ApplicationUnbindRequest:
+			return
+		case goldap.ApplicationExtendedRequest:
+			write(conn, messageID, result(goldap.ApplicationExtendedResponse, goldap.LDAPResultProtocolError, "extended operations are not supported"))
+		default:
+			log.Printf("[WARN] Unsupported operation %d", op.Tag)
+			return
+		}
+	}
+}
+
+func bind(op *ber.Packet, entries []Entry) *ber.Packet {
+	if len(op.Children) < 3 || op.Children[2].Tag != 0 {
+		return result(goldap.ApplicationBindResponse, goldap.LDAPResultAuthMethodNotSupported, "only simple bind is supported")
+	}
+	dn := text(op.Children[1])
+	password := text(op.Children[2])
+
+	// An empty password is an anonymous bind.
+	if password == "" {
+		return result(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+	}
+	for _, e := range entries {
+		if strings.EqualFold(e.DN, dn) && e.Password != "" && e.Password == password {
+			return result(goldap.ApplicationBindResponse, goldap.LDAPResultSuccess, "")
+		}
+	}
+	return result(goldap.ApplicationBindResponse, goldap.LDAPResultInvalidCredentials, "invalid credentials")
+}
+
+func search(op *ber.Packet, entries []Entry) []*ber.Packet {
+	if len(op.Children) < 8 {
+		return []*ber.Packet{result(goldap.ApplicationSearchResultDone, goldap.LDAPResultProtocolError, "malformed search")}
+	}
+	base := strings.ToLower(text(op.Children[0]))
+	scope, _ := op.Children[1].Value.(int64)
+	sizeLimit, _ := op.Children[3].Value.(int64)
+	filter := op.Children[6]
+
+	var requested []string
+	for _, attribute := range op.Children[7].Children {
+		requested = append(requested, text(attribute))
+	}
+
+	var responses []*ber.Packet
+	for _, e := range entries {
+		dn := strings.ToLower(e.DN)
+		inScope := dn == base
+		if scope != goldap.ScopeBaseObject {
+			inScope = inScope || strings.HasSuffix(dn, ","+base)
+		}
+		if !inScope || !matches(e, filter) {
+			continue
+		}
+		if sizeLimit > 0 && int64(len(responses)) == sizeLimit {
+			return append(responses, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSizeLimitExceeded, ""))
+		}
+		responses = append(responses, searchEntry(e, requested))
+	}
+	return append(responses, result(goldap.ApplicationSearchResultDone, goldap.LDAPResultSuccess, ""))
+}
+
+func matches(e Entry, filter *ber.Packet) bool {
+	switch filter.Tag {
+	case goldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matches(e, child) {
+				return false
+			}
+		}
+		return true
+	case goldap.FilterOr:
+		return slices.ContainsFunc(filter.Children, func(child *ber.Packet) bool { return matches(e, child) })
+	case goldap.FilterNot:
+		return len(filter.Children) == 1 && !matches(e, filter.Children[0])
+	case goldap.FilterEqualityMatch:
+		value := text(filter.Children[1])
+		return slices.ContainsFunc(e.values(text(filter.Children[0])), func(v string) bool { return strings.EqualFold(v, value) })
+	case goldap.FilterPresent:
+		return len(e.values(text(filter))) > 0
+	case goldap.FilterSubstrings:
+		return slices.ContainsFunc(e.values(text(filter.Children[0])), func(v string) bool {
+			return matchesSubstrings(strings.ToLower(v), filter.Children[1].Children)
+		})
+	default:
+		return false
+	}
+}
+
+func matchesSubstrings(value string, parts []*ber.Packet) bool {
+	for _, part := range parts {
+		piece := strings.ToLower(text(part))
+		switch part.Tag {
+		case goldap.FilterSubstringsInitial:
+			if !strings.HasPrefix(value, piece) {
+				return false
+			}
+			value = value[len(piece):]
+		case goldap.FilterSubstringsAny:
+			index := strings.Index(value, piece)
+			if index < 0 {
+				return false
+			}
+			value = value[index+len(piece):]
+		case goldap.FilterSubstringsFinal:
+			if !strings.HasSuffix(value, piece) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func searchEntry(e Entry, requested []string) *ber.Packet {
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, goldap.ApplicationSearchResultEntry, nil, "Search Result Entry")
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, e.DN, "Object Name"))
+
+	attributes := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attributes")
+	for name, values := range e.Attributes {
+		if len(requested) > 0 && !slices.ContainsFunc(requested, func(r string) bool { return strings.EqualFold(r, name) }) {
+			continue
+		}
+		attribute := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "Attribute")
+		attribute.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, name, "Type"))
+		set := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSet, nil, "Values")
+		for _, value := range values {
+			set.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, value, "Value"))
+		}
+		attribute.AppendChild(set)
+		attributes.AppendChild(attribute)
+	}
+	response.AppendChild(attributes)
+	return response
+}
+
+func result(tag ber.Tag, code uint16, message string) *ber.Packet {
+	response := ber.Encode(ber.ClassApplication, ber.TypeConstructed, tag, nil, "Result")
+	response.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagEnumerated, int64(code), "Result Code"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, "", "Matched DN"))
+	response.AppendChild(ber.NewString(ber.ClassUniversal, ber.TypePrimitive, ber.TagOctetString, message, "Diagnostic Message"))
+	return response
+}
+
+func write(conn net.Conn, messageID int64, response *ber.Packet) {
+	envelope := ber.Encode(ber.ClassUniversal, ber.TypeConstructed, ber.TagSequence, nil, "LDAP Response")
+	envelope.AppendChild(ber.NewInteger(ber.ClassUniversal, ber.TypePrimitive, ber.TagInteger, messageID, "Message ID"))
+	envelope.AppendChild(response)
+	if _, err := conn.Write(envelope.Bytes()); err != nil {
+		log.Printf("[ERROR] Write failed: %v", err)
+	}
+}
+
+// text returns the string of an octet string, which the decoder leaves in
+// Data for context-specific tags.
+func text(p *ber.Packet) string {
+	if value, ok := p.Value.(string); ok {
+		return value
+	}
+	return p.Data.String()
+}
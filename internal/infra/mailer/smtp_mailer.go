@@ -0,0 +1,71 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/config"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/notification"
+)
+
+type smtpMailer struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+}
+
+type logMailer struct{}
+
+// New returns an SMTP mailer, or a mailer that only logs the messages when
+// SMTP_HOST is not configured (local development).
+func New(cfg *config.Config) notification.IMailer {
+	if cfg.SMTPHost == "" {
+		log.Println("[INFO] SMTP_HOST not set, outbound e-mails will only be logged.")
+		return &logMailer{}
+	}
+
+	var auth smtp.Auth
+	if cfg.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPHost)
+	}
+
+	return &smtpMailer{
+		addr: net.JoinHostPort(cfg.SMTPHost, cfg.SMTPPort),
+		host: cfg.SMTPHost,
+		auth: auth,
+		from: cfg.MailFrom,
+	}
+}
+
+func (m *smtpMailer) Send(ctx context.Context, msg notification.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", m.from)
+	fmt.Fprintf(&b, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{msg.To}, []byte(b.String())); err != nil {
+		return fmt.Errorf("failed to send e-mail: %w", err)
+	}
+	return nil
+}
+
+func (m *logMailer) Send(_ context.Context, msg notification.Message) error {
+	log.Printf("[MAIL] to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
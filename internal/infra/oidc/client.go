@@ -0,0 +1,271 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	httpTimeout     = 10 * time.Second
+	maxResponseSize = 1 << 20
+	clockSkew       = time.Minute
+)
+
+// metadata is the part of the discovery document used by the client.
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Client is a minimal OIDC relying party: authorization code flow with PKCE
+// and ID token validation against the provider JWKS. Discovery documents and
+// keys are cached; the keys are fetched again when a token names an unknown
+// key ID, which covers key rotation.
+type Client struct {
+	httpClient *http.Client
+
+	mu       sync.Mutex
+	metadata map[string]*metadata
+	keys     map[string]map[string]crypto.PublicKey
+}
+
+func NewClient() federation.IOIDCClient {
+	return &Client{
+		httpClient: &http.Client{Timeout: httpTimeout},
+		metadata:   map[string]*metadata{},
+		keys:       map[string]map[string]crypto.PublicKey{},
+	}
+}
+
+func (c *Client) AuthorizationURL(ctx context.Context, provider federation.Provider, req federation.AuthorizationRequest) (string, error) {
+	meta, err := c.discover(ctx, provider.Issuer)
+	if err != nil {
+		return "", err
+	}
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {provider.ClientID},
+		"redirect_uri":          {req.RedirectURI},
+		"scope":                 {strings.Join(provider.Scopes, " ")},
+		"state":                 {req.State},
+		"nonce":                 {req.Nonce},
+		"code_challenge":        {req.CodeChallenge},
+		"code_challenge_method": {"S256"},
+	}
+
+	separator := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		separator = "&"
+	}
+	return meta.AuthorizationEndpoint + separator + query.Encode(), nil
+}
+
+func (c *Client) Exchange(ctx context.Context, provider federation.Provider, code string, codeVerifier string, redirectURI string, nonce string) (*federation.Claims, error) {
+	meta, err := c.discover(ctx, provider.Issuer)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {redirectURI},
+		"code_verifier": {codeVerifier},
+	}
+	if provider.ClientSecret == "" {
+		form.Set("client_id", provider.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if provider.ClientSecret != "" {
+		// client_secret_basic (RFC 6749, section 2.3.1).
+		req.SetBasicAuth(url.QueryEscape(provider.ClientID), url.QueryEscape(provider.ClientSecret))
+	}
+
+	var tokenResponse struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	status, err := c.doJSON(req, &tokenResponse)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK || tokenResponse.IDToken == "" {
+		return nil, fmt.Errorf("%w: %s %s", federation.ErrCodeExchange, tokenResponse.Error, tokenResponse.ErrorDescription)
+	}
+
+	return c.verifyIDToken(ctx, provider, meta, tokenResponse.IDToken, nonce)
+}
+
+func (c *Client) verifyIDToken(ctx context.Context, provider federation.Provider, meta *metadata, idToken string, nonce string) (*federation.Claims, error) {
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(idToken, claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return c.key(ctx, meta.JWKSURI, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384"}),
+		jwt.WithIssuer(provider.Issuer),
+		jwt.WithAudience(provider.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", federation.ErrInvalidIDToken, err)
+	}
+
+	subject, _ := claims["sub"].(string)
+	if subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", federation.ErrInvalidIDToken)
+	}
+	if tokenNonce, _ := claims["nonce"].(string); tokenNonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", federation.ErrInvalidIDToken)
+	}
+
+	result := &federation.Claims{Subject: subject}
+	result.Email, _ = claims["email"].(string)
+	result.Name, _ = claims["name"].(string)
+	// Some providers send email_verified as a string.
+	switch verified := claims["email_verified"].(type) {
+	case bool:
+		result.EmailVerified = verified
+	case string:
+		result.EmailVerified, _ = strconv.ParseBool(verified)
+	}
+	return result, nil
+}
+
+func (c *Client) discover(ctx context.Context, issuer string) (*metadata, error) {
+	c.mu.Lock()
+	cached := c.metadata[issuer]
+	c.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(issuer, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var meta metadata
+	status, err := c.doJSON(req, &meta)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery of %s: status %d", issuer, status)
+	}
+	if meta.Issuer != issuer {
+		return nil, fmt.Errorf("oidc discovery of %s: document names issuer %q", issuer, meta.Issuer)
+	}
+	if meta.AuthorizationEndpoint == "" || meta.TokenEndpoint == "" || meta.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery of %s: incomplete document", issuer)
+	}
+
+	c.mu.Lock()
+	c.metadata[issuer] = &meta
+	c.mu.Unlock()
+	return &meta, nil
+}
+
+func (c *Client) key(ctx context.Context, jwksURI string, kid string) (crypto.PublicKey, error) {
+	c.mu.Lock()
+	key, ok := c.keys[jwksURI][kid]
+	c.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	keys, err := c.fetchKeys(ctx, jwksURI)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	c.keys[jwksURI] = keys
+	c.mu.Unlock()
+
+	key, ok = keys[kid]
+	if !ok {
+		// Without a kid the token may only be signed by a single key.
+		if kid == "" && len(keys) == 1 {
+			for _, only := range keys {
+				return only, nil
+			}
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+func (c *Client) fetchKeys(ctx context.Context, jwksURI string) (map[string]crypto.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, jwksURI, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	status, err := c.doJSON(req, &set)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("jwks %s: status %d", jwksURI, status)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks has no usable signing key")
+	}
+	return keys, nil
+}
+
+func (c *Client) doJSON(req *http.Request, out interface{}) (int, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return resp.StatusCode, fmt.Errorf("decoding response of %s: %w", req.URL.Host, err)
+	}
+	return resp.StatusCode, nil
+}
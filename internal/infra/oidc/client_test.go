@@ -0,0 +1,198 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "chameleon"
+	testClientSecret = "s3cret"
+	testKeyID        = "key-1"
+	testNonce        = "nonce-123"
+	testCode         = "code-abc"
+	testVerifier     = "verifier-xyz"
+	testRedirectURI  = "https://app.example.com/callback"
+)
+
+// testProvider is an OIDC provider serving discovery, JWKS and a token
+// endpoint that answers with the ID token built by idToken.
+type testProvider struct {
+	t       *testing.T
+	server  *httptest.Server
+	key     *rsa.PrivateKey
+	idToken func(issuer string) string
+}
+
+func newTestProvider(t *testing.T) *testProvider {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p := &testProvider{t: t, key: key}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 p.server.URL,
+			"authorization_endpoint": p.server.URL + "/authorize",
+			"token_endpoint":         p.server.URL + "/token",
+			"jwks_uri":               p.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testKeyID,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		clientID, secret, ok := r.BasicAuth()
+		if r.Method != http.MethodPost || !ok || clientID != testClientID || secret != testClientSecret ||
+			r.FormValue("grant_type") != "authorization_code" || r.FormValue("code") != testCode ||
+			r.FormValue("code_verifier") != testVerifier || r.FormValue("redirect_uri") != testRedirectURI {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		writeJSON(w, map[string]string{"id_token": p.idToken(p.server.URL)})
+	})
+	p.server = httptest.NewServer(mux)
+	t.Cleanup(p.server.Close)
+	return p
+}
+
+func (p *testProvider) provider() federation.Provider {
+	return federation.Provider{
+		Name:         "test",
+		Issuer:       p.server.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		Scopes:       []string{"openid", "email"},
+	}
+}
+
+func (p *testProvider) sign(key *rsa.PrivateKey, claims jwt.MapClaims) string {
+	p.t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testKeyID
+	signed, err := token.SignedString(key)
+	if err != nil {
+		p.t.Fatal(err)
+	}
+	return signed
+}
+
+func validClaims(issuer string) jwt.MapClaims {
+	now := time.Now()
+	return jwt.MapClaims{
+		"iss":            issuer,
+		"aud":            testClientID,
+		"sub":            "user-42",
+		"email":          "ana@example.com",
+		"email_verified": "true",
+		"name":           "Ana",
+		"nonce":          testNonce,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func TestExchangeValidIDToken(t *testing.T) {
+	p := newTestProvider(t)
+	p.idToken = func(issuer string) string { return p.sign(p.key, validClaims(issuer)) }
+
+	claims, err := NewClient().Exchange(context.Background(), p.provider(), testCode, testVerifier, testRedirectURI, testNonce)
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	want := federation.Claims{Subject: "user-42", Email: "ana@example.com", EmailVerified: true, Name: "Ana"}
+	if *claims != want {
+		t.Errorf("claims = %+v, want %+v", *claims, want)
+	}
+}
+
+func TestExchangeRejectsInvalidIDToken(t *testing.T) {
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		mutate func(claims jwt.MapClaims)
+		key    *rsa.PrivateKey
+	}{
+		{name: "wrong issuer", mutate: func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" }},
+		{name: "wrong audience", mutate: func(c jwt.MapClaims) { c["aud"] = "another-client" }},
+		{name: "wrong nonce", mutate: func(c jwt.MapClaims) { c["nonce"] = "replayed" }},
+		{name: "missing nonce", mutate: func(c jwt.MapClaims) { delete(c, "nonce") }},
+		{name: "expired", mutate: func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-time.Hour).Unix() }},
+		{name: "missing subject", mutate: func(c jwt.MapClaims) { delete(c, "sub") }},
+		{name: "bad signature", key: otherKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestProvider(t)
+			p.idToken = func(issuer string) string {
+				claims := validClaims(issuer)
+				if tt.mutate != nil {
+					tt.mutate(claims)
+				}
+				key := p.key
+				if tt.key != nil {
+					key = tt.key
+				}
+				return p.sign(key, claims)
+			}
+
+			_, err := NewClient().Exchange(context.Background(), p.provider(), testCode, testVerifier, testRedirectURI, testNonce)
+			if !errors.Is(err, federation.ErrInvalidIDToken) {
+				t.Fatalf("Exchange error = %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestExchangeRejectsFailedCodeExchange(t *testing.T) {
+	p := newTestProvider(t)
+	p.idToken = func(issuer string) string { return p.sign(p.key, validClaims(issuer)) }
+
+	_, err := NewClient().Exchange(context.Background(), p.provider(), "wrong-code", testVerifier, testRedirectURI, testNonce)
+	if !errors.Is(err, federation.ErrCodeExchange) {
+		t.Fatalf("Exchange error = %v, want ErrCodeExchange", err)
+	}
+}
+
+func TestDiscoveryRejectsIssuerMismatch(t *testing.T) {
+	p := newTestProvider(t)
+	provider := p.provider()
+	provider.Issuer = p.server.URL + "/"
+
+	_, err := NewClient().AuthorizationURL(context.Background(), provider, federation.AuthorizationRequest{State: "s", Nonce: testNonce})
+	if err == nil {
+		t.Fatal("AuthorizationURL accepted a discovery document naming another issuer")
+	}
+}
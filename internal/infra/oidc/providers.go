@@ -0,0 +1,67 @@
+package oidc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/federation"
+)
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// providerFile is a provider entry of OIDC_PROVIDERS_FILE. The client secret
+// may be read from the environment variable named by client_secret_env, so
+// the file can be committed.
+type providerFile struct {
+	federation.Provider
+	ClientSecretEnv string `json:"client_secret_env"`
+}
+
+// LoadProviders reads the JSON array of providers at path. An empty path
+// disables federated login.
+func LoadProviders(path string) ([]federation.Provider, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []providerFile
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	providers := make([]federation.Provider, 0, len(entries))
+	for i, entry := range entries {
+		p := entry.Provider
+		if !providerNamePattern.MatchString(p.Name) {
+			return nil, fmt.Errorf("%s: provider %d: invalid name %q", path, i, p.Name)
+		}
+		if slices.ContainsFunc(providers, func(other federation.Provider) bool { return other.Name == p.Name }) {
+			return nil, fmt.Errorf("%s: provider %q repeated", path, p.Name)
+		}
+		if p.Issuer == "" || p.ClientID == "" {
+			return nil, fmt.Errorf("%s: provider %q: issuer and client_id are required", path, p.Name)
+		}
+
+		if p.ClientSecret == "" && entry.ClientSecretEnv != "" {
+			p.ClientSecret = os.Getenv(entry.ClientSecretEnv)
+		}
+		if p.DisplayName == "" {
+			p.DisplayName = p.Name
+		}
+		if len(p.Scopes) == 0 {
+			p.Scopes = []string{"openid", "email", "profile"}
+		} else if !slices.Contains(p.Scopes, "openid") {
+			p.Scopes = append([]string{"openid"}, p.Scopes...)
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
@@ -0,0 +1,153 @@
+package samlsp
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/saml"
+)
+
+var providerNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,62}$`)
+
+// Default attribute names, covering the plain names, the OIDs of the LDAP
+// attributes and the claim URIs sent by ADFS and Azure AD.
+var (
+	defaultEmailAttributes = []string{
+		"email",
+		"mail",
+		"urn:oid:0.9.2342.19200300.100.1.3",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/emailaddress",
+	}
+	defaultNameAttributes = []string{
+		"name",
+		"displayName",
+		"urn:oid:2.16.840.1.113730.3.1.241",
+		"http://schemas.xmlsoap.org/ws/2005/05/identity/claims/name",
+	}
+	defaultGroupAttributes = []string{
+		"groups",
+		"memberOf",
+		"urn:oid:1.3.6.1.4.1.5923.1.5.1.1",
+		"http://schemas.microsoft.com/ws/2008/06/identity/claims/groups",
+	}
+)
+
+// providerFile is a provider entry of SAML_PROVIDERS_FILE. entity_id,
+// sso_url and certificates may instead be read from the metadata document of
+// the provider at metadata_file.
+type providerFile struct {
+	saml.IdentityProvider
+	MetadataFile string `json:"metadata_file"`
+}
+
+// LoadIdentityProviders reads the JSON array of providers at path. An empty
+// path disables SAML login.
+func LoadIdentityProviders(path string) ([]saml.IdentityProvider, error) {
+	if path == "" {
+		return nil, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []providerFile
+	if err := json.Unmarshal(content, &entries); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	providers := make([]saml.IdentityProvider, 0, len(entries))
+	for i, entry := range entries {
+		p := entry.IdentityProvider
+		if !providerNamePattern.MatchString(p.Name) {
+			return nil, fmt.Errorf("%s: provider %d: invalid name %q", path, i, p.Name)
+		}
+		if slices.ContainsFunc(providers, func(other saml.IdentityProvider) bool { return other.Name == p.Name }) {
+			return nil, fmt.Errorf("%s: provider %q repeated", path, p.Name)
+		}
+
+		if entry.MetadataFile != "" {
+			if err := applyMetadata(&p, entry.MetadataFile); err != nil {
+				return nil, fmt.Errorf("%s: provider %q: %w", path, p.Name, err)
+			}
+		}
+		if p.EntityID == "" || p.SSOURL == "" || len(p.Certificates) == 0 {
+			return nil, fmt.Errorf("%s: provider %q: entity_id, sso_url and certificates are required", path, p.Name)
+		}
+		if _, err := ParseCertificates(p.Certificates); err != nil {
+			return nil, fmt.Errorf("%s: provider %q: %w", path, p.Name, err)
+		}
+
+		if p.DisplayName == "" {
+			p.DisplayName = p.Name
+		}
+		if len(p.Attributes.Email) == 0 {
+			p.Attributes.Email = defaultEmailAttributes
+		}
+		if len(p.Attributes.Name) == 0 {
+			p.Attributes.Name = defaultNameAttributes
+		}
+		if len(p.Attributes.Groups) == 0 {
+			p.Attributes.Groups = defaultGroupAttributes
+		}
+		providers = append(providers, p)
+	}
+	return providers, nil
+}
+
+type idpEntityDescriptor struct {
+	EntityID         string `xml:"entityID,attr"`
+	IDPSSODescriptor struct {
+		KeyDescriptors []struct {
+			Use          string   `xml:"use,attr"`
+			Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+		} `xml:"KeyDescriptor"`
+		SingleSignOnServices []struct {
+			Binding  string `xml:"Binding,attr"`
+			Location string `xml:"Location,attr"`
+		} `xml:"SingleSignOnService"`
+	} `xml:"IDPSSODescriptor"`
+}
+
+// applyMetadata fills the fields left empty in the file from the metadata
+// document of the provider.
+func applyMetadata(p *saml.IdentityProvider, path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var descriptor idpEntityDescriptor
+	if err := xml.Unmarshal(content, &descriptor); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if p.EntityID == "" {
+		p.EntityID = descriptor.EntityID
+	}
+	if p.SSOURL == "" {
+		for _, service := range descriptor.IDPSSODescriptor.SingleSignOnServices {
+			if service.Binding == bindingHTTPRedirect {
+				p.SSOURL = service.Location
+				break
+			}
+		}
+	}
+	if len(p.Certificates) == 0 {
+		for _, key := range descriptor.IDPSSODescriptor.KeyDescriptors {
+			if key.Use != "" && key.Use != "signing" {
+				continue
+			}
+			for _, certificate := range key.Certificates {
+				p.Certificates = append(p.Certificates, strings.Join(strings.Fields(certificate), ""))
+			}
+		}
+	}
+	return nil
+}
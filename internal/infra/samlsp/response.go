@@ -0,0 +1,320 @@
+package samlsp
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/saml"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+	"github.com/russellhaering/goxmldsig/etreeutils"
+)
+
+const (
+	statusSuccess      = "urn:oasis:names:tc:SAML:2.0:status:Success"
+	confirmationBearer = "urn:oasis:names:tc:SAML:2.0:cm:bearer"
+)
+
+// ParseResponse only reads the elements returned by the signature
+// validation, so content outside the signed element, such as a second
+// assertion wrapped around the signed one, is never trusted.
+func (sp *serviceProvider) ParseResponse(idp saml.IdentityProvider, samlResponse string, requestID string) (*saml.Assertion, error) {
+	raw, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(samlResponse), ""))
+	if err != nil {
+		return nil, invalid("response is not base64")
+	}
+	// encoding/xml does not always round-trip, which the signature of a
+	// crafted document could exploit.
+	if err := xrv.Validate(bytes.NewReader(raw)); err != nil {
+		return nil, invalid("response does not round-trip: %v", err)
+	}
+
+	doc := etree.NewDocument()
+	if err := doc.ReadFromBytes(raw); err != nil {
+		return nil, invalid("response is not XML: %v", err)
+	}
+	response := doc.Root()
+	if response == nil || !is(response, protocolNamespace, "Response") {
+		return nil, invalid("root element is not a Response")
+	}
+
+	certificates, err := ParseCertificates(idp.Certificates)
+	if err != nil {
+		return nil, err
+	}
+	validator := dsig.NewDefaultValidationContext(&dsig.MemoryX509CertificateStore{Roots: certificates})
+	validator.IdAttribute = "ID"
+
+	responseSigned := child(response, signatureNamespace, "Signature") != nil
+	if responseSigned {
+		response, err = validator.Validate(response)
+		if err != nil {
+			return nil, invalid("response signature: %v", err)
+		}
+	}
+	if err := sp.checkResponse(response, idp, requestID); err != nil {
+		return nil, err
+	}
+
+	if child(response, assertionNamespace, "EncryptedAssertion") != nil {
+		return nil, invalid("encrypted assertions are not supported")
+	}
+	assertions := children(response, assertionNamespace, "Assertion")
+	if len(assertions) != 1 {
+		return nil, invalid("response carries %d assertions", len(assertions))
+	}
+	assertion := assertions[0]
+	if child(assertion, signatureNamespace, "Signature") != nil {
+		assertion, err = validateDetached(validator, assertion)
+		if err != nil {
+			return nil, invalid("assertion signature: %v", err)
+		}
+	} else if !responseSigned {
+		return nil, invalid("neither the response nor the assertion is signed")
+	}
+
+	return sp.readAssertion(assertion, idp, requestID)
+}
+
+// checkResponse rejects responses to another request, sent to another SP or
+// reporting a failure. Responses without InResponseTo, which IdP-initiated
+// logins send, are not accepted.
+func (sp *serviceProvider) checkResponse(response *etree.Element, idp saml.IdentityProvider, requestID string) error {
+	if version := response.SelectAttrValue("Version", ""); version != "2.0" {
+		return invalid("unsupported version %q", version)
+	}
+	if inResponseTo := response.SelectAttrValue("InResponseTo", ""); inResponseTo != requestID {
+		return invalid("InResponseTo %q does not match the request", inResponseTo)
+	}
+	if destination := response.SelectAttrValue("Destination", ""); destination != "" && destination != sp.cfg.ACSURL {
+		return invalid("destination %q is not the ACS", destination)
+	}
+	if responseIssuer := child(response, assertionNamespace, "Issuer"); responseIssuer != nil && strings.TrimSpace(responseIssuer.Text()) != idp.EntityID {
+		return invalid("response issuer %q is not the provider", strings.TrimSpace(responseIssuer.Text()))
+	}
+
+	status := child(response, protocolNamespace, "Status")
+	if status == nil {
+		return invalid("response has no status")
+	}
+	statusCode := child(status, protocolNamespace, "StatusCode")
+	if statusCode == nil {
+		return invalid("response has no status code")
+	}
+	if value := statusCode.SelectAttrValue("Value", ""); value != statusSuccess {
+		return invalid("provider answered with status %q", value)
+	}
+	return nil
+}
+
+func (sp *serviceProvider) readAssertion(el *etree.Element, idp saml.IdentityProvider, requestID string) (*saml.Assertion, error) {
+	now := sp.now()
+	assertion := &saml.Assertion{
+		ID:         el.SelectAttrValue("ID", ""),
+		Attributes: map[string][]string{},
+	}
+	if assertion.ID == "" {
+		return nil, invalid("assertion has no ID")
+	}
+
+	assertionIssuer := child(el, assertionNamespace, "Issuer")
+	if assertionIssuer == nil || strings.TrimSpace(assertionIssuer.Text()) != idp.EntityID {
+		return nil, invalid("assertion was not issued by the provider")
+	}
+
+	subject := child(el, assertionNamespace, "Subject")
+	if subject == nil {
+		return nil, invalid("assertion has no subject")
+	}
+	if nameID := child(subject, assertionNamespace, "NameID"); nameID != nil {
+		assertion.NameID = strings.TrimSpace(nameID.Text())
+		assertion.NameIDFormat = nameID.SelectAttrValue("Format", "")
+	}
+	confirmedUntil, err := sp.confirmSubject(subject, requestID, now)
+	if err != nil {
+		return nil, err
+	}
+	assertion.NotOnOrAfter = confirmedUntil
+
+	conditions := child(el, assertionNamespace, "Conditions")
+	if conditions == nil {
+		return nil, invalid("assertion has no conditions")
+	}
+	if err := sp.checkWindow(conditions, now); err != nil {
+		return nil, err
+	}
+	if notOnOrAfter, err := parseInstant(conditions.SelectAttrValue("NotOnOrAfter", "")); err == nil && notOnOrAfter.Before(assertion.NotOnOrAfter) {
+		assertion.NotOnOrAfter = notOnOrAfter
+	}
+	if err := sp.checkAudience(conditions); err != nil {
+		return nil, err
+	}
+
+	if child(el, assertionNamespace, "AuthnStatement") == nil {
+		return nil, invalid("assertion has no authentication statement")
+	}
+
+	for _, statement := range children(el, assertionNamespace, "AttributeStatement") {
+		for _, attribute := range children(statement, assertionNamespace, "Attribute") {
+			var values []string
+			for _, value := range children(attribute, assertionNamespace, "AttributeValue") {
+				values = append(values, strings.TrimSpace(value.Text()))
+			}
+			for _, name := range []string{attribute.SelectAttrValue("Name", ""), attribute.SelectAttrValue("FriendlyName", "")} {
+				if name != "" {
+					assertion.Attributes[name] = append(assertion.Attributes[name], values...)
+				}
+			}
+		}
+	}
+	return assertion, nil
+}
+
+// confirmSubject requires a bearer confirmation addressed to the ACS and
+// returns the end of its validity.
+func (sp *serviceProvider) confirmSubject(subject *etree.Element, requestID string, now time.Time) (time.Time, error) {
+	for _, confirmation := range children(subject, assertionNamespace, "SubjectConfirmation") {
+		if confirmation.SelectAttrValue("Method", "") != confirmationBearer {
+			continue
+		}
+		data := child(confirmation, assertionNamespace, "SubjectConfirmationData")
+		if data == nil {
+			continue
+		}
+		if data.SelectAttrValue("Recipient", "") != sp.cfg.ACSURL {
+			continue
+		}
+		if inResponseTo := data.SelectAttrValue("InResponseTo", ""); inResponseTo != "" && inResponseTo != requestID {
+			continue
+		}
+		if notBefore, err := parseInstant(data.SelectAttrValue("NotBefore", "")); err == nil && now.Add(sp.cfg.ClockSkew).Before(notBefore) {
+			continue
+		}
+		notOnOrAfter, err := parseInstant(data.SelectAttrValue("NotOnOrAfter", ""))
+		if err != nil || !now.Add(-sp.cfg.ClockSkew).Before(notOnOrAfter) {
+			continue
+		}
+		return notOnOrAfter, nil
+	}
+	return time.Time{}, invalid("no valid bearer subject confirmation")
+}
+
+func (sp *serviceProvider) checkWindow(conditions *etree.Element, now time.Time) error {
+	if value := conditions.SelectAttrValue("NotBefore", ""); value != "" {
+		notBefore, err := parseInstant(value)
+		if err != nil {
+			return invalid("invalid NotBefore %q", value)
+		}
+		if now.Add(sp.cfg.ClockSkew).Before(notBefore) {
+			return invalid("assertion is not valid before %s", value)
+		}
+	}
+	if value := conditions.SelectAttrValue("NotOnOrAfter", ""); value != "" {
+		notOnOrAfter, err := parseInstant(value)
+		if err != nil {
+			return invalid("invalid NotOnOrAfter %q", value)
+		}
+		if !now.Add(-sp.cfg.ClockSkew).Before(notOnOrAfter) {
+			return invalid("assertion expired at %s", value)
+		}
+	}
+	return nil
+}
+
+// checkAudience requires every audience restriction to name the SP.
+func (sp *serviceProvider) checkAudience(conditions *etree.Element) error {
+	restrictions := children(conditions, assertionNamespace, "AudienceRestriction")
+	if len(restrictions) == 0 {
+		return invalid("assertion has no audience restriction")
+	}
+	for _, restriction := range restrictions {
+		found := false
+		for _, audience := range children(restriction, assertionNamespace, "Audience") {
+			if strings.TrimSpace(audience.Text()) == sp.cfg.EntityID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return invalid("assertion is not addressed to this service provider")
+		}
+	}
+	return nil
+}
+
+// validateDetached validates a signed descendant, carrying the namespaces
+// declared by its ancestors.
+func validateDetached(validator *dsig.ValidationContext, el *etree.Element) (*etree.Element, error) {
+	ctx, err := etreeutils.NSBuildParentContext(el)
+	if err != nil {
+		return nil, err
+	}
+	detached, err := etreeutils.NSDetatch(ctx, el)
+	if err != nil {
+		return nil, err
+	}
+	return validator.Validate(detached)
+}
+
+// ParseCertificates accepts PEM certificates or the bare base64 DER found in
+// metadata documents.
+func ParseCertificates(values []string) ([]*x509.Certificate, error) {
+	certificates := make([]*x509.Certificate, 0, len(values))
+	for _, value := range values {
+		var der []byte
+		if block, _ := pem.Decode([]byte(value)); block != nil {
+			der = block.Bytes
+		} else {
+			decoded, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(value), ""))
+			if err != nil {
+				return nil, errors.New("certificate is neither PEM nor base64")
+			}
+			der = decoded
+		}
+
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	return certificates, nil
+}
+
+func parseInstant(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, value)
+}
+
+func is(el *etree.Element, namespace, tag string) bool {
+	return el.Tag == tag && el.NamespaceURI() == namespace
+}
+
+func child(el *etree.Element, namespace, tag string) *etree.Element {
+	for _, c := range el.ChildElements() {
+		if is(c, namespace, tag) {
+			return c
+		}
+	}
+	return nil
+}
+
+func children(el *etree.Element, namespace, tag string) []*etree.Element {
+	var found []*etree.Element
+	for _, c := range el.ChildElements() {
+		if is(c, namespace, tag) {
+			found = append(found, c)
+		}
+	}
+	return found
+}
+
+func invalid(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", saml.ErrInvalidResponse, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,161 @@
+package samlsp
+
+import (
+	"bytes"
+	"compress/flate"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"net/url"
+	"time"
+
+	"github.com/felipedenardo/chameleon-auth-api/internal/domain/saml"
+)
+
+const (
+	protocolNamespace  = "urn:oasis:names:tc:SAML:2.0:protocol"
+	assertionNamespace = "urn:oasis:names:tc:SAML:2.0:assertion"
+	metadataNamespace  = "urn:oasis:names:tc:SAML:2.0:metadata"
+	signatureNamespace = "http://www.w3.org/2000/09/xmldsig#"
+
+	bindingHTTPPost     = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+	bindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+)
+
+// Config identifies the service provider to the identity providers.
+type Config struct {
+	EntityID string
+	// ACSURL is the assertion consumer service, which receives the responses
+	// through the HTTP-POST binding.
+	ACSURL string
+	// ClockSkew is tolerated on every instant of a response.
+	ClockSkew time.Duration
+}
+
+type serviceProvider struct {
+	cfg Config
+	now func() time.Time
+}
+
+func NewServiceProvider(cfg Config) saml.IServiceProvider {
+	return &serviceProvider{cfg: cfg, now: time.Now}
+}
+
+type entityDescriptor struct {
+	XMLName         xml.Name        `xml:"urn:oasis:names:tc:SAML:2.0:metadata EntityDescriptor"`
+	EntityID        string          `xml:"entityID,attr"`
+	SPSSODescriptor spSSODescriptor `xml:"SPSSODescriptor"`
+}
+
+type spSSODescriptor struct {
+	AuthnRequestsSigned        bool                       `xml:"AuthnRequestsSigned,attr"`
+	WantAssertionsSigned       bool                       `xml:"WantAssertionsSigned,attr"`
+	ProtocolSupportEnumeration string                     `xml:"protocolSupportEnumeration,attr"`
+	NameIDFormats              []string                   `xml:"NameIDFormat"`
+	AssertionConsumerServices  []assertionConsumerService `xml:"AssertionConsumerService"`
+}
+
+type assertionConsumerService struct {
+	Binding   string `xml:"Binding,attr"`
+	Location  string `xml:"Location,attr"`
+	Index     int    `xml:"index,attr"`
+	IsDefault bool   `xml:"isDefault,attr"`
+}
+
+// Metadata describes an SP that does not sign its requests and requires
+// signed assertions.
+func (sp *serviceProvider) Metadata() ([]byte, error) {
+	descriptor := entityDescriptor{
+		EntityID: sp.cfg.EntityID,
+		SPSSODescriptor: spSSODescriptor{
+			WantAssertionsSigned:       true,
+			ProtocolSupportEnumeration: protocolNamespace,
+			NameIDFormats:              []string{saml.NameIDFormatEmail, saml.NameIDFormatPersistent},
+			AssertionConsumerServices: []assertionConsumerService{
+				{Binding: bindingHTTPPost, Location: sp.cfg.ACSURL, Index: 0, IsDefault: true},
+			},
+		},
+	}
+
+	content, err := xml.MarshalIndent(descriptor, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), content...), nil
+}
+
+type authnRequest struct {
+	XMLName                     xml.Name     `xml:"urn:oasis:names:tc:SAML:2.0:protocol AuthnRequest"`
+	ID                          string       `xml:"ID,attr"`
+	Version                     string       `xml:"Version,attr"`
+	IssueInstant                string       `xml:"IssueInstant,attr"`
+	Destination                 string       `xml:"Destination,attr"`
+	ProtocolBinding             string       `xml:"ProtocolBinding,attr"`
+	AssertionConsumerServiceURL string       `xml:"AssertionConsumerServiceURL,attr"`
+	Issuer                      issuer       `xml:"urn:oasis:names:tc:SAML:2.0:assertion Issuer"`
+	NameIDPolicy                nameIDPolicy `xml:"NameIDPolicy"`
+}
+
+type issuer struct {
+	Value string `xml:",chardata"`
+}
+
+type nameIDPolicy struct {
+	AllowCreate bool `xml:"AllowCreate,attr"`
+}
+
+// AuthnRequestURL encodes the request for the HTTP-Redirect binding: raw
+// DEFLATE, then base64, in the SAMLRequest parameter.
+func (sp *serviceProvider) AuthnRequestURL(idp saml.IdentityProvider, relayState string) (string, string, error) {
+	requestID, err := newID()
+	if err != nil {
+		return "", "", err
+	}
+
+	request := authnRequest{
+		ID:                          requestID,
+		Version:                     "2.0",
+		IssueInstant:                sp.now().UTC().Format(time.RFC3339),
+		Destination:                 idp.SSOURL,
+		ProtocolBinding:             bindingHTTPPost,
+		AssertionConsumerServiceURL: sp.cfg.ACSURL,
+		Issuer:                      issuer{Value: sp.cfg.EntityID},
+		NameIDPolicy:                nameIDPolicy{AllowCreate: true},
+	}
+	content, err := xml.Marshal(request)
+	if err != nil {
+		return "", "", err
+	}
+
+	var compressed bytes.Buffer
+	writer, err := flate.NewWriter(&compressed, flate.BestCompression)
+	if err != nil {
+		return "", "", err
+	}
+	if _, err := writer.Write(content); err != nil {
+		return "", "", err
+	}
+	if err := writer.Close(); err != nil {
+		return "", "", err
+	}
+
+	redirectURL, err := url.Parse(idp.SSOURL)
+	if err != nil {
+		return "", "", err
+	}
+	query := redirectURL.Query()
+	query.Set("SAMLRequest", base64.StdEncoding.EncodeToString(compressed.Bytes()))
+	query.Set("RelayState", relayState)
+	redirectURL.RawQuery = query.Encode()
+	return redirectURL.String(), requestID, nil
+}
+
+// newID returns an xs:ID, which cannot start with a digit.
+func newID() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "_" + hex.EncodeToString(b), nil
+}